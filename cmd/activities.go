@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arkeo/arkeo/internal/cache"
+	"github.com/arkeo/arkeo/internal/config"
+	"github.com/arkeo/arkeo/internal/connectors"
+	"github.com/arkeo/arkeo/internal/rules"
+	"github.com/arkeo/arkeo/internal/timeline"
+	"github.com/arkeo/arkeo/internal/utils"
+)
+
+// parseDateArg parses the optional YYYY-MM-DD argument, defaulting to yesterday
+func parseDateArg(args []string) time.Time {
+	var dateStr string
+	if len(args) > 0 {
+		dateStr = args[0]
+	} else {
+		yesterday := time.Now().AddDate(0, 0, -1)
+		dateStr = yesterday.Format("2006-01-02")
+	}
+
+	parsedDate, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid date format. Use YYYY-MM-DD: %v\n", err)
+		os.Exit(1)
+	}
+	return parsedDate
+}
+
+// buildDays returns the days selected by --range or --week around targetDate,
+// or just targetDate when neither is set
+func buildDays(targetDate time.Time, week bool, rangeDays int) []time.Time {
+	var days []time.Time
+	if rangeDays > 0 {
+		days = make([]time.Time, rangeDays)
+		for i := 0; i < rangeDays; i++ {
+			days[i] = targetDate.AddDate(0, 0, -(rangeDays - 1 - i)).Truncate(24 * time.Hour)
+		}
+	} else if week {
+		weekday := targetDate.Weekday()
+		daysFromMonday := (int(weekday) - int(time.Monday) + 7) % 7
+		monday := targetDate.AddDate(0, 0, -daysFromMonday).Truncate(24 * time.Hour)
+		days = make([]time.Time, 5)
+		for i := 0; i < 5; i++ {
+			days[i] = monday.AddDate(0, 0, i)
+		}
+	} else {
+		days = []time.Time{targetDate.Truncate(24 * time.Hour)}
+	}
+	return days
+}
+
+// openActivityCache opens the activity cache in the config directory.
+// It returns nil when the cache can't be opened, after printing a warning.
+func openActivityCache(configManager *config.Manager) *cache.Cache {
+	configDir, err := configManager.GetConfigDir()
+	if err != nil {
+		return nil
+	}
+
+	activityCache, err := cache.New(filepath.Join(configDir, "cache.db"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not open cache: %v\n", err)
+		return nil
+	}
+	activityCache.SetFreshness(cacheFreshness(configManager))
+	return activityCache
+}
+
+// cacheFreshness builds the policy for refetching recent days from the cache
+// section of the config and the cache_ttl of each connector
+func cacheFreshness(configManager *config.Manager) cache.Freshness {
+	cacheConfig := configManager.GetConfig().Cache
+	freshness := cache.Freshness{
+		HorizonDays:  cacheConfig.HorizonDays,
+		TTL:          time.Duration(cacheConfig.TTLMinutes) * time.Minute,
+		ConnectorTTL: make(map[string]time.Duration),
+	}
+
+	for name, connectorConfig := range configManager.GetConfig().Connectors {
+		var minutes int
+		switch v := connectorConfig.Config[connectors.CommonConfigKeys.CacheTTL].(type) {
+		case int:
+			minutes = v
+		case float64:
+			minutes = int(v)
+		case string:
+			n, err := strconv.Atoi(strings.TrimSpace(v))
+			if err != nil {
+				continue
+			}
+			minutes = n
+		default:
+			continue
+		}
+		freshness.ConnectorTTL[name] = time.Duration(minutes) * time.Minute
+	}
+
+	return freshness
+}
+
+// loadActivities returns the activities of the enabled connectors for the
+// given days. Each connector's days that are up to date in the cache are
+// loaded from it; only the remaining connector days are fetched, in one go,
+// and stored per day and connector. It also returns how many days came
+// entirely from the cache and how many needed fetching from any connector.
+func loadActivities(ctx context.Context, enabledConnectors map[string]connectors.Connector, activityCache *cache.Cache, days []time.Time, verbose bool) ([]timeline.Activity, int, int) {
+	// Convert connectors to utils.Connector interface
+	utilsConnectors := make(map[string]utils.Connector)
+	connectorNames := make([]string, 0, len(enabledConnectors))
+	for name, conn := range enabledConnectors {
+		utilsConnectors[name] = conn
+		connectorNames = append(connectorNames, name)
+	}
+	sort.Strings(connectorNames)
+
+	if activityCache != nil {
+		activityCache.SetOutputVersions(connectors.OutputVersions(enabledConnectors))
+	}
+
+	// Load cached connector days first and collect the ones that still need
+	// fetching, so one stale connector doesn't refetch the others
+	var allActivities []timeline.Activity
+	missingDays := make(map[string][]time.Time)
+	fetchedDays := make(map[time.Time]bool)
+	cachedDays := 0
+
+	for _, day := range days {
+		cachedCount := 0
+		for _, name := range connectorNames {
+			// Check cache first (unless --no-cache)
+			if activityCache != nil && activityCache.HasDay(day, []string{name}) {
+				cachedActivities, err := activityCache.LoadConnectorDay(day, name)
+				if err == nil {
+					allActivities = append(allActivities, cachedActivities...)
+					cachedCount += len(cachedActivities)
+					continue
+				}
+			}
+			missingDays[name] = append(missingDays[name], day)
+			fetchedDays[day] = true
+		}
+
+		if !fetchedDays[day] {
+			cachedDays++
+		}
+		if verbose && cachedCount > 0 {
+			fmt.Printf("  %s: %d activities (cached)\n", day.Format("2006-01-02"), cachedCount)
+		}
+	}
+
+	if len(missingDays) == 0 {
+		return allActivities, cachedDays, 0
+	}
+
+	// Cache miss — fetch the missing days of each connector at once.
+	// Connectors that support range requests get a single call; the rest
+	// are queried day by day.
+	executor := utils.NewParallelExecutor()
+	results := executor.FetchDaysParallel(ctx, utilsConnectors, missingDays)
+
+	for _, result := range results {
+		total := 0
+		for _, dayResult := range result.Days {
+			if dayResult.Error != nil {
+				if verbose {
+					fmt.Fprintf(os.Stderr, "  Warning: %s %s: %v\n", dayResult.Date.Format("2006-01-02"), result.Name, dayResult.Error)
+				}
+				continue
+			}
+
+			total += len(dayResult.Activities)
+			allActivities = append(allActivities, dayResult.Activities...)
+
+			// Store in cache (unless --no-cache) — store even when 0 activities
+			// so HasDay knows this connector was fetched for this day.
+			if activityCache != nil {
+				if err := activityCache.StoreDay(dayResult.Date, result.Name, dayResult.Activities); err != nil {
+					if verbose {
+						fmt.Fprintf(os.Stderr, "  Warning: could not cache %s/%s: %v\n", dayResult.Date.Format("2006-01-02"), result.Name, err)
+					}
+				}
+			}
+		}
+
+		if verbose {
+			if fetched := missingDays[result.Name]; len(fetched) == 1 {
+				fmt.Printf("  %s %s: %d activities (took %v)\n",
+					fetched[0].Format("2006-01-02"),
+					result.Name, total, result.Duration.Round(time.Millisecond))
+			} else {
+				fmt.Printf("  %s: %d activities across %d days (took %v)\n",
+					result.Name, total, len(fetched), result.Duration.Round(time.Millisecond))
+			}
+		}
+	}
+
+	return allActivities, cachedDays, len(fetchedDays)
+}
+
+// applyRules tags the activities using the rules section of the config.
+// Invalid rules are reported and leave the activities untouched.
+func applyRules(configManager *config.Manager, activities []timeline.Activity) {
+	ruleConfigs := configManager.GetConfig().Rules
+	if len(ruleConfigs) == 0 {
+		return
+	}
+
+	engine, err := rules.New(ruleConfigs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: rules not applied: %v\n", err)
+		return
+	}
+	engine.Apply(activities)
+}
+
+// deduplicateActivities merges activities reported by several sources using
+// the dedup section of the config. It returns the remaining activities and
+// the number of duplicates merged.
+func deduplicateActivities(configManager *config.Manager, activities []timeline.Activity) ([]timeline.Activity, int) {
+	dedupConfig := configManager.GetConfig().Dedup
+	if !dedupConfig.Enabled {
+		return activities, 0
+	}
+	return timeline.Deduplicate(activities, dedupConfig.Options())
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/arkeo/arkeo/internal/cache"
+	"github.com/arkeo/arkeo/internal/connectors"
+	"github.com/arkeo/arkeo/internal/timeline"
+)
+
+// countingConnector returns one activity per day and counts its calls
+type countingConnector struct {
+	*connectors.BaseConnector
+	calls int
+}
+
+func newCountingConnector(name string) *countingConnector {
+	return &countingConnector{BaseConnector: connectors.NewBaseConnector(name, "Counting connector")}
+}
+
+func (c *countingConnector) GetActivities(ctx context.Context, date time.Time) ([]timeline.Activity, error) {
+	c.calls++
+	return []timeline.Activity{{ID: c.Name() + "-fetched", Source: c.Name(), Title: "Fetched", Timestamp: date.Add(9 * time.Hour)}}, nil
+}
+
+func (c *countingConnector) TestConnection(ctx context.Context) error {
+	return nil
+}
+
+func TestLoadActivities_RefetchesOnlyStaleConnectors(t *testing.T) {
+	activityCache, err := cache.New(filepath.Join(t.TempDir(), "cache.db"))
+	if err != nil {
+		t.Fatalf("Failed to open cache: %v", err)
+	}
+	defer activityCache.Close()
+
+	// Entries of today are refetched after 30 minutes for calendar only
+	activityCache.SetFreshness(cache.Freshness{
+		HorizonDays:  2,
+		ConnectorTTL: map[string]time.Duration{"calendar": 30 * time.Minute},
+		Now:          func() time.Time { return time.Now().Add(time.Hour) },
+	})
+
+	now := time.Now()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	for _, name := range []string{"github", "calendar"} {
+		cached := []timeline.Activity{{ID: name + "-cached", Source: name, Title: "Cached", Timestamp: day.Add(8 * time.Hour)}}
+		if err := activityCache.StoreDay(day, name, cached); err != nil {
+			t.Fatalf("StoreDay failed: %v", err)
+		}
+	}
+
+	github, calendar := newCountingConnector("github"), newCountingConnector("calendar")
+	enabled := map[string]connectors.Connector{"github": github, "calendar": calendar}
+
+	activities, cachedDays, fetchedDays := loadActivities(context.Background(), enabled, activityCache, []time.Time{day}, false)
+
+	if github.calls != 0 {
+		t.Errorf("Expected the fresh github entry not to be refetched, got %d calls", github.calls)
+	}
+	if calendar.calls != 1 {
+		t.Errorf("Expected the stale calendar entry to be refetched once, got %d calls", calendar.calls)
+	}
+	if cachedDays != 0 || fetchedDays != 1 {
+		t.Errorf("Expected 0 cached and 1 fetched day, got %d and %d", cachedDays, fetchedDays)
+	}
+
+	ids := make(map[string]bool)
+	for _, a := range activities {
+		ids[a.ID] = true
+	}
+	if len(activities) != 2 || !ids["github-cached"] || !ids["calendar-fetched"] {
+		t.Errorf("Expected the cached github and the fetched calendar activity, got %v", ids)
+	}
+}
@@ -0,0 +1,504 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/arkeo/arkeo/internal/cache"
+	"github.com/arkeo/arkeo/internal/config"
+	"github.com/arkeo/arkeo/internal/display/colors"
+	"github.com/arkeo/arkeo/internal/timeline"
+)
+
+// cacheCmd manages the activity cache
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the activity cache",
+	Long: `Manage the local cache of fetched activities. Use subcommands to see what is
+cached, invalidate days of a single connector, reclaim disk space, or move the
+cache between machines.`,
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show what the cache holds, per connector and per month",
+	Args:  cobra.NoArgs,
+	Run:   runCacheStatsCommand,
+}
+
+var cacheLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List the cached days and connectors",
+	Long: `List the cached days with the number of activities of each connector.
+Entries marked with * are refetched on next use because they are older than
+their cache TTL.`,
+	Example: `  # Everything cached this year
+  arkeo cache ls --from 2024-01-01
+
+  # Days with cached GitHub activities
+  arkeo cache ls --connector github
+
+  # Days cached for any GitLab instance
+  arkeo cache ls --connector gitlab --instances`,
+	Args: cobra.NoArgs,
+	Run:  runCacheLsCommand,
+}
+
+var cacheShowCmd = &cobra.Command{
+	Use:   "show <date>",
+	Short: "Show the cached activities of a day, per connector",
+	Args:  cobra.ExactArgs(1),
+	Run:   runCacheShowCommand,
+}
+
+var cacheResetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Remove cached days so they are fetched again",
+	Long: `Remove cached days so they are fetched again on next use. With --connector,
+only that connector is removed and the others stay cached. Add --instances to
+also remove every connector of that type in the config file, such as
+gitlab:client-a or an entry with type: gitlab.
+
+Give --from and --to to limit the days removed, or --all to empty the cache.`,
+	Example: `  # Refetch GitHub for January after changing its config
+  arkeo cache reset --connector github --from 2024-01-01 --to 2024-01-31
+
+  # Refetch every GitLab instance
+  arkeo cache reset --connector gitlab --instances
+
+  # Forget everything cached for a day
+  arkeo cache reset --from 2024-01-15 --to 2024-01-15
+
+  # Empty the cache
+  arkeo cache reset --all`,
+	Args: cobra.NoArgs,
+	Run:  runCacheResetCommand,
+}
+
+var cacheVacuumCmd = &cobra.Command{
+	Use:   "vacuum",
+	Short: "Compact the cache database and reclaim disk space",
+	Args:  cobra.NoArgs,
+	Run:   runCacheVacuumCommand,
+}
+
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove cached days older than a given age",
+	Long: `Remove the cached days older than --older-than, given in days (365d or 365),
+weeks (52w) or years (1y). Run 'arkeo cache vacuum' afterwards to give the
+space back to the file system.`,
+	Example: `  # Keep one year of history
+  arkeo cache prune --older-than 365d`,
+	Args: cobra.NoArgs,
+	Run:  runCachePruneCommand,
+}
+
+var cacheExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write cached days to a portable archive",
+	Long: `Write the cached activities to a gzip-compressed JSON Lines archive, one line
+per connector and day, keeping when each day was cached. The archive goes to
+standard output unless --output is given.
+
+Restore it with 'arkeo cache import', on this machine or another one.`,
+	Example: `  # Back up the whole cache
+  arkeo cache export > archive.jsonl.gz
+
+  # Only the first quarter
+  arkeo cache export --from 2024-01-01 --to 2024-03-31 --output q1.jsonl.gz`,
+	Args: cobra.NoArgs,
+	Run:  runCacheExportCommand,
+}
+
+var cacheImportCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Load an archive written by 'arkeo cache export'",
+	Long: `Load the cached days of an archive written by 'arkeo cache export', read from
+the file or from standard input. Compressed and uncompressed archives are
+both accepted.
+
+When the cache already has a connector and day from the archive, --merge
+decides which one is kept:
+  newest     keep whichever was cached last (default)
+  skip       keep the cached one
+  overwrite  take the one from the archive
+
+An invalid archive is rejected as a whole.`,
+	Example: `  # Restore a backup
+  arkeo cache import archive.jsonl.gz
+
+  # Copy the cache from another machine without touching local days
+  ssh laptop arkeo cache export | arkeo cache import --merge skip`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runCacheImportCommand,
+}
+
+var (
+	cacheFrom      string
+	cacheTo        string
+	cacheConnector string
+	cacheInstances bool
+	cacheAll       bool
+	cacheOlderThan string
+	cacheOutput    string
+	cacheMerge     string
+)
+
+func init() {
+	for _, cmd := range []*cobra.Command{cacheLsCmd, cacheResetCmd} {
+		cmd.Flags().StringVar(&cacheFrom, "from", "", "First day (YYYY-MM-DD)")
+		cmd.Flags().StringVar(&cacheTo, "to", "", "Last day (YYYY-MM-DD)")
+		cmd.Flags().StringVar(&cacheConnector, "connector", "", "Only this connector")
+		cmd.Flags().BoolVar(&cacheInstances, "instances", false, "With --connector, also every connector of that type in the config")
+	}
+	cacheResetCmd.Flags().BoolVar(&cacheAll, "all", false, "Remove every cached day")
+	cachePruneCmd.Flags().StringVar(&cacheOlderThan, "older-than", "", "Age of the oldest day to keep, e.g. 365d, 52w or 1y (required)")
+	cachePruneCmd.MarkFlagRequired("older-than")
+
+	cacheExportCmd.Flags().StringVar(&cacheFrom, "from", "", "First day to export (YYYY-MM-DD)")
+	cacheExportCmd.Flags().StringVar(&cacheTo, "to", "", "Last day to export (YYYY-MM-DD)")
+	cacheExportCmd.Flags().StringVar(&cacheOutput, "output", "", "Write the archive to this file instead of standard output")
+
+	cacheImportCmd.Flags().StringVar(&cacheMerge, "merge", string(cache.MergeNewest), "What to keep when a day is already cached (newest, skip, overwrite)")
+
+	cacheCmd.AddCommand(cacheStatsCmd)
+	cacheCmd.AddCommand(cacheLsCmd)
+	cacheCmd.AddCommand(cacheShowCmd)
+	cacheCmd.AddCommand(cacheResetCmd)
+	cacheCmd.AddCommand(cacheVacuumCmd)
+	cacheCmd.AddCommand(cachePruneCmd)
+	cacheCmd.AddCommand(cacheExportCmd)
+	cacheCmd.AddCommand(cacheImportCmd)
+}
+
+func runCacheStatsCommand(cmd *cobra.Command, args []string) {
+	activityCache := mustOpenCache()
+	defer activityCache.Close()
+
+	stats, err := activityCache.Stats()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("%s\n\n", colors.Colorize("Activity cache", colors.Bold+colors.Blue))
+	if stats.TotalEntries == 0 {
+		fmt.Printf("The cache is empty (%s on disk).\n", formatBytes(stats.SizeBytes))
+		return
+	}
+
+	fmt.Printf("Days:         %d (%s to %s)\n", stats.UniqueDates, stats.FirstDate, stats.LastDate)
+	fmt.Printf("Entries:      %d\n", stats.TotalEntries)
+	fmt.Printf("Activities:   %d\n", stats.TotalActivities)
+	fmt.Printf("Last fetched: %s\n", time.Unix(stats.NewestCachedAt, 0).Format("2006-01-02 15:04"))
+	fmt.Printf("Size on disk: %s\n", formatBytes(stats.SizeBytes))
+
+	fmt.Printf("\n%s\n", colors.Colorize("Per connector", colors.Bold))
+	fmt.Printf("%-24s %6s %11s  %-10s  %s\n", "CONNECTOR", "DAYS", "ACTIVITIES", "FIRST", "LAST")
+	for _, g := range stats.Connectors {
+		fmt.Printf("%-24s %6d %11d  %-10s  %s\n", g.Name, g.Days, g.Activities, g.FirstDate, g.LastDate)
+	}
+
+	fmt.Printf("\n%s\n", colors.Colorize("Per month", colors.Bold))
+	fmt.Printf("%-24s %6s %11s\n", "MONTH", "DAYS", "ACTIVITIES")
+	for _, g := range stats.Months {
+		fmt.Printf("%-24s %6d %11d\n", g.Name, g.Days, g.Activities)
+	}
+}
+
+func runCacheLsCommand(cmd *cobra.Command, args []string) {
+	from := parseOptionalDate(cacheFrom)
+	to := parseOptionalDate(cacheTo)
+
+	configManager, _ := initializeSystem()
+	selected := make(map[string]bool)
+	for _, name := range selectedCacheConnectors(configManager) {
+		selected[name] = true
+	}
+
+	activityCache := openCacheOrExit(configManager)
+	defer activityCache.Close()
+
+	entries, err := activityCache.List(from, to)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// One line per day
+	var dates []string
+	connectorsByDate := make(map[string][]string)
+	for _, entry := range entries {
+		if len(selected) > 0 && !selected[entry.Connector] {
+			continue
+		}
+		label := fmt.Sprintf("%s %d", entry.Connector, entry.Activities)
+		if entry.Stale {
+			label += "*"
+		}
+		if _, ok := connectorsByDate[entry.Date]; !ok {
+			dates = append(dates, entry.Date)
+		}
+		connectorsByDate[entry.Date] = append(connectorsByDate[entry.Date], label)
+	}
+
+	if len(dates) == 0 {
+		fmt.Println("No cached days match.")
+		return
+	}
+	for _, date := range dates {
+		fmt.Printf("%s  %s\n", colors.Colorize(date, colors.Bold), strings.Join(connectorsByDate[date], "  "))
+	}
+}
+
+func runCacheShowCommand(cmd *cobra.Command, args []string) {
+	date := parseDateArg(args)
+
+	activityCache := mustOpenCache()
+	defer activityCache.Close()
+
+	infos, err := activityCache.List(date, date)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(infos) == 0 {
+		fmt.Printf("%s is not cached.\n", date.Format("2006-01-02"))
+		return
+	}
+
+	activitiesByConnector := make(map[string][]timeline.Activity)
+	err = activityCache.Entries(date, date, func(entry cache.Entry) error {
+		activitiesByConnector[entry.Connector] = entry.Activities
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("%s\n", colors.Colorize("Cached activities for "+date.Format("Monday, January 2, 2006"), colors.Bold+colors.Blue))
+	for _, info := range infos {
+		status := "fetched " + info.CachedAt.Format("2006-01-02 15:04")
+		if info.OutputVersion != 0 {
+			status += fmt.Sprintf(", output version %d", info.OutputVersion)
+		}
+		if info.Stale {
+			status += ", refetched on next use"
+		}
+		fmt.Printf("\n%s  %d activities (%s)\n",
+			colors.Colorize(info.Connector, colors.Bold), info.Activities, status)
+
+		for _, a := range activitiesByConnector[info.Connector] {
+			fmt.Printf("  %s  %s\n", a.Timestamp.Format("15:04"), a.Title)
+		}
+	}
+}
+
+func runCacheResetCommand(cmd *cobra.Command, args []string) {
+	from := parseOptionalDate(cacheFrom)
+	to := parseOptionalDate(cacheTo)
+	if !cacheAll && cacheConnector == "" && from.IsZero() && to.IsZero() {
+		fmt.Fprintln(os.Stderr, "Error: give --connector, --from or --to, or --all to empty the cache")
+		os.Exit(1)
+	}
+
+	configManager, _ := initializeSystem()
+	activityCache := openCacheOrExit(configManager)
+	defer activityCache.Close()
+
+	removed, err := activityCache.Reset(selectedCacheConnectors(configManager), from, to)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Removed %d cache entries\n", removed)
+}
+
+func runCacheVacuumCommand(cmd *cobra.Command, args []string) {
+	activityCache := mustOpenCache()
+	defer activityCache.Close()
+
+	before, _ := activityCache.Size()
+	if err := activityCache.Vacuum(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	after, _ := activityCache.Size()
+	fmt.Printf("Cache compacted from %s to %s\n", formatBytes(before), formatBytes(after))
+}
+
+func runCachePruneCommand(cmd *cobra.Command, args []string) {
+	days, err := parseAgeDays(cacheOlderThan)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	now := time.Now()
+	cutoff := time.Date(now.Year(), now.Month(), now.Day()-days, 0, 0, 0, 0, time.Local)
+
+	activityCache := mustOpenCache()
+	defer activityCache.Close()
+
+	removed, err := activityCache.Prune(cutoff)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Removed %d cache entries from before %s\n", removed, cutoff.Format("2006-01-02"))
+	if removed > 0 {
+		fmt.Println("Run 'arkeo cache vacuum' to reclaim the disk space.")
+	}
+}
+
+func runCacheExportCommand(cmd *cobra.Command, args []string) {
+	from := parseOptionalDate(cacheFrom)
+	to := parseOptionalDate(cacheTo)
+
+	var out io.Writer = os.Stdout
+	if cacheOutput != "" {
+		file, err := os.Create(cacheOutput)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	activityCache := mustOpenCache()
+	defer activityCache.Close()
+
+	count, err := activityCache.Export(out, from, to)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: export failed: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d cached days\n", count)
+}
+
+func runCacheImportCommand(cmd *cobra.Command, args []string) {
+	mode, err := cache.ParseMergeMode(cacheMerge)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	var in io.Reader = os.Stdin
+	if len(args) == 1 && args[0] != "-" {
+		file, err := os.Open(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		in = file
+	}
+
+	activityCache := mustOpenCache()
+	defer activityCache.Close()
+
+	stats, err := activityCache.Import(in, mode)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: import failed: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Imported %d cached days, kept %d already cached\n", stats.Imported, stats.Skipped)
+}
+
+// mustOpenCache opens the activity cache or exits
+func mustOpenCache() *cache.Cache {
+	configManager, _ := initializeSystem()
+	return openCacheOrExit(configManager)
+}
+
+// openCacheOrExit opens the activity cache of the config or exits
+func openCacheOrExit(configManager *config.Manager) *cache.Cache {
+	activityCache := openActivityCache(configManager)
+	if activityCache == nil {
+		fmt.Fprintln(os.Stderr, "Error: could not open the activity cache")
+		os.Exit(1)
+	}
+	return activityCache
+}
+
+// selectedCacheConnectors returns the connectors chosen with --connector:
+// that connector, and with --instances also every connector the config
+// gives its type. It returns nil without --connector, meaning all of them.
+func selectedCacheConnectors(configManager *config.Manager) []string {
+	if cacheConnector == "" {
+		if cacheInstances {
+			fmt.Fprintln(os.Stderr, "Error: --instances needs --connector")
+			os.Exit(1)
+		}
+		return nil
+	}
+
+	names := []string{cacheConnector}
+	if cacheInstances {
+		var instances []string
+		for name := range configManager.GetConfig().Connectors {
+			if name != cacheConnector && configManager.GetConnectorType(name) == cacheConnector {
+				instances = append(instances, name)
+			}
+		}
+		sort.Strings(instances)
+		names = append(names, instances...)
+	}
+	return names
+}
+
+// parseAgeDays parses an age like 365d, 52w, 1y or 365 into days
+func parseAgeDays(value string) (int, error) {
+	number := strings.TrimSpace(strings.ToLower(value))
+	multiplier := 1
+	switch {
+	case strings.HasSuffix(number, "d"):
+		number = strings.TrimSuffix(number, "d")
+	case strings.HasSuffix(number, "w"):
+		number, multiplier = strings.TrimSuffix(number, "w"), 7
+	case strings.HasSuffix(number, "y"):
+		number, multiplier = strings.TrimSuffix(number, "y"), 365
+	}
+	n, err := strconv.Atoi(number)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid age %q (expected e.g. 365d, 52w or 1y)", value)
+	}
+	return n * multiplier, nil
+}
+
+// formatBytes formats a size in bytes for humans
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGT"[exp])
+}
+
+// parseOptionalDate parses a YYYY-MM-DD flag value, or returns the zero time
+// when it is empty
+func parseOptionalDate(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid date format: %s (expected YYYY-MM-DD)\n", value)
+		os.Exit(1)
+	}
+	return date
+}
@@ -7,6 +7,9 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/arkeo/arkeo/internal/connectors"
+	"github.com/arkeo/arkeo/internal/secrets"
 )
 
 // connectorsCmd manages connectors
@@ -40,10 +43,18 @@ var connectorsListCmd = &cobra.Command{
 			if configManager.IsConnectorEnabled(name) {
 				status = "✅ Enabled"
 			}
-			fmt.Printf("%-15s %s - %s\n", name, status, connector.Description())
+			description := connector.Description()
+			if _, ok := connector.(*connectors.PluginConnector); ok {
+				description += " (plugin)"
+			}
+			if connectorType := configManager.GetConnectorType(name); connectorType != name {
+				description += fmt.Sprintf(" (%s instance)", connectorType)
+			}
+			fmt.Printf("%-15s %s - %s\n", name, status, description)
 		}
 		fmt.Println()
 		fmt.Println("💡 Enable a connector: arkeo connectors enable <name>")
+		fmt.Println("🧩 Add a plugin: put an executable in ~/.config/arkeo/plugins/")
 		fmt.Println("⚙️  Edit configuration: Edit ~/.config/arkeo/config.yaml")
 	},
 }
@@ -125,6 +136,9 @@ var connectorsInfoCmd = &cobra.Command{
 		// Header
 		fmt.Printf("Connector: %s (%s)\n", connector.Name(), enabledStatus)
 		fmt.Printf("Description: %s\n", connector.Description())
+		if plugin, ok := connector.(*connectors.PluginConnector); ok {
+			fmt.Printf("Plugin: %s\n", plugin.Command())
+		}
 		fmt.Println(strings.Repeat("=", 50))
 		fmt.Println()
 
@@ -143,7 +157,10 @@ var connectorsInfoCmd = &cobra.Command{
 					if val, exists := connectorConfig.Config[field.Key]; exists && val != nil {
 						switch v := val.(type) {
 						case string:
-							if field.Type == "secret" && v != "" {
+							if field.Type == "secret" && secrets.IsReference(v) {
+								valueStr = v
+								configuredSymbol = "✓"
+							} else if field.Type == "secret" && v != "" {
 								valueStr = "********"
 								configuredSymbol = "✓"
 							} else if v != "" {
@@ -225,7 +242,13 @@ var connectorsTestCmd = &cobra.Command{
 		}
 		configWithLogLevel["log_level"] = configManager.GetConfig().App.LogLevel
 
-		if err := connector.Configure(configWithLogLevel); err != nil {
+		resolved, err := secrets.ResolveConfig(context.Background(), configWithLogLevel, connector.GetRequiredConfig())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error resolving secrets: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := connector.Configure(resolved); err != nil {
 			fmt.Fprintf(os.Stderr, "Error configuring connector: %v\n", err)
 			os.Exit(1)
 		}
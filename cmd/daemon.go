@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/arkeo/arkeo/internal/cache"
+	"github.com/arkeo/arkeo/internal/config"
+	"github.com/arkeo/arkeo/internal/connectors"
+	"github.com/arkeo/arkeo/internal/prefetch"
+)
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Keep the activity cache warm in the background",
+	Long: `Periodically fetch the previous working day from all enabled connectors and
+backfill the days missing from the cache, so timelines and timesheets open
+instantly.
+
+Requests to each connector are spaced out by the delays in the prefetch section
+of the config file. Failures are logged and retried on the next run. The status
+of the last run is shown on the Timeline page of the web UI.
+
+Use 'arkeo web --prefetch' to run the same prefetching inside the web UI.`,
+	Example: `  # Prefetch every hour (default from config)
+  arkeo daemon
+
+  # Prefetch every 15 minutes
+  arkeo daemon --interval 15
+
+  # Run once and exit, e.g. from cron
+  arkeo daemon --once`,
+	Args: cobra.NoArgs,
+	Run:  runDaemonCommand,
+}
+
+var (
+	daemonOnce     bool
+	daemonInterval int
+)
+
+func init() {
+	daemonCmd.Flags().BoolVar(&daemonOnce, "once", false, "Run a single prefetch and exit")
+	daemonCmd.Flags().IntVar(&daemonInterval, "interval", 0, "Minutes between runs (default from config)")
+}
+
+func runDaemonCommand(cmd *cobra.Command, args []string) {
+	configManager, registry := initializeSystem()
+
+	activityCache := openActivityCache(configManager)
+	if activityCache == nil {
+		fmt.Fprintln(os.Stderr, "Error: the daemon needs the activity cache")
+		os.Exit(1)
+	}
+	defer activityCache.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	prefetcher := newPrefetcher(configManager, registry, activityCache)
+	if daemonOnce {
+		status := prefetcher.RunOnce(ctx)
+		if len(status.Failures) > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, "Prefetching in the background, press Ctrl+C to stop")
+	prefetcher.Run(ctx)
+}
+
+// newPrefetcher creates a prefetcher for the enabled connectors of registry,
+// writing its status to the config directory where the web UI reads it
+func newPrefetcher(configManager *config.Manager, registry *connectors.ConnectorRegistry, activityCache *cache.Cache) *prefetch.Prefetcher {
+	opts := prefetch.OptionsFromConfig(configManager.GetConfig().Prefetch)
+	if daemonInterval > 0 {
+		opts.Interval = time.Duration(daemonInterval) * time.Minute
+	}
+
+	var statusPath string
+	if configDir, err := configManager.GetConfigDir(); err == nil {
+		statusPath = filepath.Join(configDir, prefetch.StatusFile)
+	}
+
+	return prefetch.New(activityCache, func() map[string]connectors.Connector {
+		return getEnabledConnectors(configManager, registry)
+	}, opts, statusPath)
+}
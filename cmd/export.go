@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/arkeo/arkeo/internal/cache"
+	"github.com/arkeo/arkeo/internal/display/colors"
+	"github.com/arkeo/arkeo/internal/exporters"
+	"github.com/arkeo/arkeo/internal/timesheet"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export [date]",
+	Short: "Export inferred work blocks to a time tracking tool",
+	Long: `Send the work blocks inferred for the timesheet to Clockify, Toggl Track, Harvest
+or Kimai as time entries. Each block becomes one entry with the titles of its
+activities as description, rounded to the timesheet increment.
+
+Credentials and the mapping from arkeo projects and clients to remote projects
+live in the export section of the config file. Blocks of unmapped projects are
+skipped. The time exported to each remote project per day is remembered, and
+running it again for the same days only sends the time that is new, even when
+blocks moved or grew in between. Use --dry-run to preview.`,
+	Example: `  # Preview last week's entries for Clockify
+  arkeo export --to clockify --week 2024-01-15 --dry-run
+
+  # Send yesterday's entries to Harvest
+  arkeo export --to harvest`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runExportCommand,
+}
+
+var (
+	exportTo        string
+	exportWeek      bool
+	exportRangeDays int
+	exportRound     int
+	exportDryRun    bool
+	exportNoCache   bool
+)
+
+func init() {
+	exportCmd.Flags().StringVar(&exportTo, "to", "", "Time tracking tool to export to ("+strings.Join(exporters.Names(), ", ")+")")
+	exportCmd.Flags().BoolVar(&exportWeek, "week", false, "Use the work week (Monday-Friday) containing the selected date")
+	exportCmd.Flags().IntVar(&exportRangeDays, "range", 0, "Use the last N days ending at the selected date")
+	exportCmd.Flags().IntVar(&exportRound, "round", -1, "Rounding increment in minutes (0 = no rounding, default from config)")
+	exportCmd.Flags().BoolVar(&exportDryRun, "dry-run", false, "Show the time entries that would be created without creating them")
+	exportCmd.Flags().BoolVar(&exportNoCache, "no-cache", false, "Skip cache (always fetch from connectors, don't store results)")
+	exportCmd.MarkFlagRequired("to")
+}
+
+func runExportCommand(cmd *cobra.Command, args []string) {
+	targetDate := parseDateArg(args)
+
+	configManager, registry := initializeSystem()
+
+	exportConfig, ok := configManager.GetConfig().Export[exportTo]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "No export.%s section in the config file. Available exporters: %s\n",
+			exportTo, strings.Join(exporters.Names(), ", "))
+		os.Exit(1)
+	}
+
+	sink, err := exporters.New(exportTo, exportConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid export configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	// The cache also remembers the time exported before, even with
+	// --no-cache, so that exporting the same days again creates nothing twice
+	var activityCache *cache.Cache
+	var exportLog exporters.ExportLog
+	if openedCache := openActivityCache(configManager); openedCache != nil {
+		defer openedCache.Close()
+		exportLog = openedCache
+		if !exportNoCache {
+			activityCache = openedCache
+		}
+	}
+
+	enabledConnectors := getEnabledConnectors(configManager, registry)
+	if len(enabledConnectors) == 0 {
+		fmt.Println("No connectors are enabled. Use 'arkeo connectors list' to see available connectors.")
+		fmt.Println("Enable a connector with: arkeo connectors enable <connector-name>")
+		return
+	}
+
+	days := buildDays(targetDate, exportWeek, exportRangeDays)
+	fmt.Printf("Fetching activities for %d day(s) (%s to %s)...\n",
+		len(days), days[0].Format("2006-01-02"), days[len(days)-1].Format("2006-01-02"))
+
+	ctx := context.Background()
+	activities, _, _ := loadActivities(ctx, enabledConnectors, activityCache, days, true)
+	activities, _ = deduplicateActivities(configManager, activities)
+	applyRules(configManager, activities)
+
+	tsConfig := configManager.GetConfig().Timesheet
+	opts := timesheet.Options{
+		Increment:     time.Duration(tsConfig.RoundMinutes) * time.Minute,
+		MaxGap:        time.Duration(tsConfig.GapMinutes) * time.Minute,
+		PointDuration: time.Duration(tsConfig.ActivityMinutes) * time.Minute,
+	}
+	if exportRound >= 0 {
+		opts.Increment = time.Duration(exportRound) * time.Minute
+	}
+
+	ts := timesheet.Build(activities, days, opts)
+	entries := exporters.EntriesFromBlocks(ts, activities, opts.Increment)
+	if len(entries) == 0 {
+		fmt.Println("\nNo work blocks to export.")
+		return
+	}
+
+	results := exporters.Export(ctx, sink, entries, exporters.NewMapping(exportConfig.Projects), exportLog, exportDryRun)
+
+	fmt.Println()
+	printExportResults(results)
+
+	if exportDryRun {
+		fmt.Printf("\nDry run: nothing was sent to %s. Run without --dry-run to create the new entries.\n", exportTo)
+	}
+}
+
+// printExportResults prints one line per time entry with its status
+func printExportResults(results []exporters.Result) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "DATE\tTIME\tPROJECT\tREMOTE\tDURATION\tSTATUS\tDESCRIPTION")
+
+	counts := make(map[string]int)
+	for _, r := range results {
+		status := r.Status
+		switch r.Status {
+		case exporters.StatusExists:
+			status = "skipped (exported before)"
+		case exporters.StatusUnmapped:
+			status = "skipped (no mapping)"
+		case exporters.StatusError:
+			status = "error: " + r.Error
+		case exporters.StatusNew, exporters.StatusCreated:
+			if r.Existing > 0 {
+				status = fmt.Sprintf("%s (%s exported before)", r.Status, colors.FormatDuration(r.Existing))
+			}
+		}
+		counts[r.Status]++
+
+		description := r.Entry.Description
+		if runes := []rune(description); len(runes) > 50 {
+			description = string(runes[:47]) + "..."
+		}
+		start := r.Entry.Start.In(time.Local)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			start.Format("2006-01-02"), start.Format("15:04"), r.Entry.Project, r.RemoteProject,
+			colors.FormatDuration(r.Entry.Duration()), status, description)
+	}
+	w.Flush()
+
+	fmt.Printf("\n%d new, %d created, %d exported before, %d unmapped, %d failed\n",
+		counts[exporters.StatusNew], counts[exporters.StatusCreated], counts[exporters.StatusExists],
+		counts[exporters.StatusUnmapped], counts[exporters.StatusError])
+}
@@ -1,18 +1,23 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 
 	"github.com/spf13/cobra"
 
 	"github.com/arkeo/arkeo/internal/config"
 	"github.com/arkeo/arkeo/internal/connectors"
+	"github.com/arkeo/arkeo/internal/secrets"
 )
 
 var (
-	configPath string
-	webAddr    string
+	configPath  string
+	webAddr     string
+	webPrefetch bool
 )
 
 var version = "dev" // Will be set by SetVersion function
@@ -30,7 +35,7 @@ var rootCmd = &cobra.Command{
 gather information about your daily activities and presents them in a chronological timeline.
 
 Features:
-• Connect to GitHub, GitLab, Calendar, YouTrack, Browser History, and more
+• Connect to GitHub, GitLab, Calendar, YouTrack, Jira, Browser History, and more
 • View activities in a formatted timeline or interactive web UI
 • Cache past activities for instant re-display
 • Configure connectors through YAML configuration
@@ -51,11 +56,29 @@ When run without a subcommand, launches the web UI.`,
   # Output in JSON format
   arkeo timeline --format json
 
+  # Weekly report from ~/.config/arkeo/templates/weekly.tmpl
+  arkeo timeline --week --template weekly
+
+  # Search all cached activities
+  arkeo search "billing service" --since 2024-01-01
+
+  # Standup message from the previous working day
+  arkeo standup
+
+  # Hours per project for the work week
+  arkeo timesheet --week
+
   # List all connectors and their status
   arkeo connectors list
 
   # Manage browser domain exclusions interactively
   arkeo browser domains
+
+  # Prefetch the previous working day and missing days every hour
+  arkeo daemon
+
+  # Back up the activity cache
+  arkeo cache export > archive.jsonl.gz
 `,
 	Run: func(cmd *cobra.Command, args []string) {
 		runWebCommand(cmd, args)
@@ -78,12 +101,21 @@ func init() {
 
 	// Web UI flag (also available on the root command since web is the default)
 	rootCmd.PersistentFlags().StringVar(&webAddr, "addr", "localhost:7878", "Address for the web UI")
+	rootCmd.PersistentFlags().BoolVar(&webPrefetch, "prefetch", false, "Keep the cache warm in the background while the web UI runs")
 
 	// Add subcommands
 	rootCmd.AddCommand(timelineCmd)
+	rootCmd.AddCommand(timesheetCmd)
+	rootCmd.AddCommand(rulesCmd)
 	rootCmd.AddCommand(connectorsCmd)
 	rootCmd.AddCommand(browserCmd)
+	rootCmd.AddCommand(youtrackCmd)
+	rootCmd.AddCommand(exportCmd)
 	rootCmd.AddCommand(webCmd)
+	rootCmd.AddCommand(daemonCmd)
+	rootCmd.AddCommand(searchCmd)
+	rootCmd.AddCommand(standupCmd)
+	rootCmd.AddCommand(cacheCmd)
 }
 
 // initConfig reads in config file and ENV variables if set
@@ -91,6 +123,21 @@ func initConfig() {
 	// This will be called before other commands run
 }
 
+// connectorTypes creates the built-in connectors by type name
+var connectorTypes = map[string]func() connectors.Connector{
+	"github":          func() connectors.Connector { return connectors.NewGitHubConnector() },
+	"calendar":        func() connectors.Connector { return connectors.NewCalendarConnector() },
+	"gitlab":          func() connectors.Connector { return connectors.NewGitLabConnector() },
+	"youtrack":        func() connectors.Connector { return connectors.NewYouTrackConnector() },
+	"jira":            func() connectors.Connector { return connectors.NewJiraConnector() },
+	"macos_system":    func() connectors.Connector { return connectors.NewMacOSSystemConnector() },
+	"linux_session":   func() connectors.Connector { return connectors.NewLinuxSessionConnector() },
+	"webhooks":        func() connectors.Connector { return connectors.NewWebhooksConnector() },
+	"browser_history": func() connectors.Connector { return connectors.NewBrowserHistoryConnector() },
+	"local_git":       func() connectors.Connector { return connectors.NewLocalGitConnector() },
+	"shell_history":   func() connectors.Connector { return connectors.NewShellHistoryConnector() },
+}
+
 // initializeSystem initializes the configuration manager and connector registry
 func initializeSystem() (*config.Manager, *connectors.ConnectorRegistry) {
 	// Initialize configuration
@@ -103,16 +150,14 @@ func initializeSystem() (*config.Manager, *connectors.ConnectorRegistry) {
 	// Initialize connector registry with direct registration
 	registry := connectors.NewConnectorRegistry()
 
-	// Register available connectors directly
-	availableConnectors := []connectors.Connector{
-		connectors.NewGitHubConnector(),
-		connectors.NewCalendarConnector(),
-		connectors.NewGitLabConnector(),
-		connectors.NewYouTrackConnector(),
-		connectors.NewMacOSSystemConnector(),
-		connectors.NewWebhooksConnector(),
-		connectors.NewBrowserHistoryConnector(),
+	// Register one connector of each type under its type name, then the
+	// plugins and the additional named instances from the config
+	availableConnectors := make([]connectors.Connector, 0, len(connectorTypes))
+	for _, newConnector := range connectorTypes {
+		availableConnectors = append(availableConnectors, newConnector())
 	}
+	availableConnectors = append(availableConnectors, pluginConnectors(configManager, availableConnectors)...)
+	availableConnectors = append(availableConnectors, instanceConnectors(configManager, availableConnectors)...)
 
 	for _, connector := range availableConnectors {
 		registry.Register(connector)
@@ -130,13 +175,99 @@ func initializeSystem() (*config.Manager, *connectors.ConnectorRegistry) {
 			}
 		}
 
-		// Only configure, but don't enable
-		_ = connector.Configure(baseConfig)
+		// Only configure, but don't enable. Plugins are checked against the
+		// fields they declare once they are used, as that runs them.
+		if plugin, ok := connector.(*connectors.PluginConnector); ok {
+			_ = plugin.Preconfigure(baseConfig)
+		} else {
+			_ = connector.Configure(baseConfig)
+		}
 	}
 
 	return configManager, registry
 }
 
+// pluginConnectors returns the plugins found in the plugins directory of the
+// config dir, plus connectors configured with a command of their own. Plugins
+// can't take the name of a built-in connector.
+func pluginConnectors(configManager *config.Manager, builtin []connectors.Connector) []connectors.Connector {
+	taken := make(map[string]bool, len(builtin))
+	for _, connector := range builtin {
+		taken[connector.Name()] = true
+	}
+
+	var plugins []connectors.Connector
+	if configDir, err := configManager.GetConfigDir(); err == nil {
+		discovered, err := connectors.DiscoverPlugins(filepath.Join(configDir, "plugins"))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Error reading plugins directory: %v\n", err)
+		}
+		for _, plugin := range discovered {
+			if taken[plugin.Name()] {
+				fmt.Fprintf(os.Stderr, "Warning: Plugin %s has the name of a built-in connector and is ignored\n", plugin.Command())
+				continue
+			}
+			taken[plugin.Name()] = true
+			plugins = append(plugins, plugin)
+		}
+	}
+
+	for name, connectorConfig := range configManager.GetConfig().Connectors {
+		command, ok := connectorConfig.Config[connectors.PluginCommandKey].(string)
+		if !ok || command == "" || taken[name] {
+			continue
+		}
+		taken[name] = true
+		plugins = append(plugins, connectors.NewPluginConnector(name, command))
+	}
+
+	return plugins
+}
+
+// instanceConnectors returns the additional named instances configured in
+// the connectors section, such as gitlab:client-a, each a connector of its
+// type (a built-in connector or a plugin) renamed after its entry
+func instanceConnectors(configManager *config.Manager, registered []connectors.Connector) []connectors.Connector {
+	byName := make(map[string]connectors.Connector, len(registered))
+	for _, connector := range registered {
+		byName[connector.Name()] = connector
+	}
+
+	names := make([]string, 0, len(configManager.GetConfig().Connectors))
+	for name := range configManager.GetConfig().Connectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var instances []connectors.Connector
+	for _, name := range names {
+		connectorType := configManager.GetConnectorType(name)
+		if _, exists := byName[name]; exists || connectorType == name {
+			continue
+		}
+
+		var instance connectors.Connector
+		if newConnector, ok := connectorTypes[connectorType]; ok {
+			instance = newConnector()
+		} else if plugin, ok := byName[connectorType].(*connectors.PluginConnector); ok {
+			instance = connectors.NewPluginConnector(connectorType, plugin.Command())
+		} else {
+			fmt.Fprintf(os.Stderr, "Warning: Connector %s has unknown type %q\n", name, connectorType)
+			continue
+		}
+
+		named, ok := instance.(connectors.NamedConnector)
+		if !ok {
+			continue
+		}
+		named.SetName(name)
+		byName[name] = named
+		instances = append(instances, named)
+	}
+
+	return instances
+}
+
 // getEnabledConnectors returns configured and enabled connectors
 func getEnabledConnectors(configManager *config.Manager, registry *connectors.ConnectorRegistry) map[string]connectors.Connector {
 	enabled := make(map[string]connectors.Connector)
@@ -166,7 +297,13 @@ func getEnabledConnectors(configManager *config.Manager, registry *connectors.Co
 				configWithAppSettings[connectors.CommonConfigKeys.DebugMode] = true
 			}
 
-			if err := connector.Configure(configWithAppSettings); err != nil {
+			resolved, err := secrets.ResolveConfig(context.Background(), configWithAppSettings, connector.GetRequiredConfig())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: Error resolving secrets of %s connector: %v\n", name, err)
+				continue
+			}
+
+			if err := connector.Configure(resolved); err != nil {
 				fmt.Fprintf(os.Stderr, "Warning: Error configuring %s connector: %v\n", name, err)
 				continue
 			}
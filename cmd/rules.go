@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/arkeo/arkeo/internal/cache"
+	"github.com/arkeo/arkeo/internal/display/colors"
+	"github.com/arkeo/arkeo/internal/rules"
+)
+
+// rulesCmd groups commands for the activity tagging rules
+var rulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Inspect the rules that tag activities with projects and clients",
+	Long: `Rules live in the 'rules' section of the configuration file. Each rule matches
+activity fields (type, source, title, description, url, text) and metadata with
+case-insensitive globs or /regular expressions/, and assigns project, client and
+billable tags to the matching activities. The first matching rule wins.`,
+}
+
+var rulesTestNoCache bool
+
+func init() {
+	rulesCmd.AddCommand(rulesTestCmd)
+	rulesTestCmd.Flags().BoolVar(&rulesTestNoCache, "no-cache", false, "Skip cache (always fetch from connectors, don't store results)")
+}
+
+var rulesTestCmd = &cobra.Command{
+	Use:   "test [date]",
+	Short: "Show which rule matched each activity of a day",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		targetDate := parseDateArg(args)
+
+		configManager, registry := initializeSystem()
+
+		ruleConfigs := configManager.GetConfig().Rules
+		if len(ruleConfigs) == 0 {
+			fmt.Println("No rules configured. Add a 'rules' section to ~/.config/arkeo/config.yaml")
+			return
+		}
+
+		engine, err := rules.New(ruleConfigs)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid rules: %v\n", err)
+			os.Exit(1)
+		}
+
+		var activityCache *cache.Cache
+		if !rulesTestNoCache {
+			activityCache = openActivityCache(configManager)
+			if activityCache != nil {
+				defer activityCache.Close()
+			}
+		}
+
+		enabledConnectors := getEnabledConnectors(configManager, registry)
+		if len(enabledConnectors) == 0 {
+			fmt.Println("No connectors are enabled. Use 'arkeo connectors list' to see available connectors.")
+			return
+		}
+
+		days := buildDays(targetDate, false, 0)
+		activities, _, _ := loadActivities(context.Background(), enabledConnectors, activityCache, days, false)
+		activities, _ = deduplicateActivities(configManager, activities)
+		sort.Slice(activities, func(i, j int) bool {
+			return activities[i].Timestamp.Before(activities[j].Timestamp)
+		})
+
+		engine.Apply(activities)
+
+		fmt.Printf("%s\n\n", colors.Colorize(
+			fmt.Sprintf("Rule matches for %s (%d rules)", targetDate.Format("Monday, January 2, 2006"), engine.Len()),
+			colors.Bold+colors.Blue))
+
+		matched := 0
+		for _, a := range activities {
+			label := colors.SourceLabel(a.Source)
+
+			title := a.Title
+			if len([]rune(title)) > 60 {
+				title = string([]rune(title)[:59]) + "…"
+			}
+
+			ruleName := a.Metadata[rules.RuleKey]
+			result := colors.Colorize("no rule", colors.DarkGray)
+			if ruleName != "" {
+				matched++
+				var tags []string
+				for _, key := range []string{rules.ProjectKey, rules.ClientKey, rules.BillableKey} {
+					if v := a.Metadata[key]; v != "" {
+						tags = append(tags, fmt.Sprintf("%s=%s", key, v))
+					}
+				}
+				result = colors.Colorize(ruleName, colors.Green)
+				if len(tags) > 0 {
+					result += " " + strings.Join(tags, " ")
+				}
+			}
+
+			fmt.Printf("%s  %-4s %-60s  → %s\n",
+				colors.Colorize(a.Timestamp.Format("15:04"), colors.Bold),
+				label, title, result)
+		}
+
+		fmt.Printf("\n%d of %d activities matched a rule.\n", matched, len(activities))
+	},
+}
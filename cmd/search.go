@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/arkeo/arkeo/internal/cache"
+	"github.com/arkeo/arkeo/internal/display/colors"
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search the cached activities",
+	Long: `Search the titles and descriptions of all cached activities, most recent first.
+Every word of the query must match, as a word or the start of one; put words in
+quotes to match them as a phrase.
+
+Only cached days are searched. Run 'arkeo timeline --range 180' or
+'arkeo daemon' to fill the cache.`,
+	Example: `  # When did I last touch the billing service?
+  arkeo search "billing service"
+
+  # GitLab activities since the start of the year
+  arkeo search invoice --since 2024-01-01 --source gitlab`,
+	Args: cobra.ExactArgs(1),
+	Run:  runSearchCommand,
+}
+
+var (
+	searchSince   string
+	searchUntil   string
+	searchSources []string
+	searchLimit   int
+	searchFormat  string
+)
+
+func init() {
+	searchCmd.Flags().StringVar(&searchSince, "since", "", "Only search from this date (YYYY-MM-DD)")
+	searchCmd.Flags().StringVar(&searchUntil, "until", "", "Only search up to this date (YYYY-MM-DD)")
+	searchCmd.Flags().StringSliceVar(&searchSources, "source", nil, "Only search these connectors (repeatable, a type includes its instances)")
+	searchCmd.Flags().IntVar(&searchLimit, "limit", 50, "Maximum number of results (0 = unlimited)")
+	searchCmd.Flags().StringVar(&searchFormat, "format", "table", "Output format (table, json)")
+}
+
+func runSearchCommand(cmd *cobra.Command, args []string) {
+	opts := cache.SearchOptions{Sources: searchSources, Limit: searchLimit}
+	for _, flag := range []struct {
+		value  string
+		target *time.Time
+	}{{searchSince, &opts.Since}, {searchUntil, &opts.Until}} {
+		if flag.value == "" {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", flag.value)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid date format: %s (expected YYYY-MM-DD)\n", flag.value)
+			os.Exit(1)
+		}
+		*flag.target = date
+	}
+
+	configManager, _ := initializeSystem()
+	activityCache := openActivityCache(configManager)
+	if activityCache == nil {
+		fmt.Fprintln(os.Stderr, "Error: search needs the activity cache")
+		os.Exit(1)
+	}
+	defer activityCache.Close()
+
+	results, err := activityCache.Search(args[0], opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if searchFormat == "json" {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+		return
+	}
+
+	if len(results) == 0 {
+		fmt.Printf("No cached activities match %q.\n", args[0])
+		return
+	}
+
+	fmt.Printf("%s\n\n", colors.Colorize(
+		fmt.Sprintf("%d activities matching %q", len(results), args[0]),
+		colors.Bold+colors.Blue))
+
+	for _, a := range results {
+		text := a.Title
+		if a.Description != "" {
+			text += colors.Colorize(" — "+strings.ReplaceAll(a.Description, "\n", " "), colors.DarkGray)
+		}
+		fmt.Printf("%s  %-4s %s\n",
+			colors.Colorize(a.Timestamp.Format("2006-01-02 15:04"), colors.Bold),
+			colors.SourceLabel(a.Source), text)
+	}
+
+	if searchLimit > 0 && len(results) == searchLimit {
+		fmt.Printf("\nShowing the %d most recent matches. Use --limit to see more.\n", searchLimit)
+	}
+}
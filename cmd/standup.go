@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/arkeo/arkeo/internal/cache"
+	"github.com/arkeo/arkeo/internal/config"
+	"github.com/arkeo/arkeo/internal/display"
+)
+
+var standupCmd = &cobra.Command{
+	Use:   "standup [date]",
+	Short: "Write a standup message from the previous working day",
+	Long: `Write a standup message in Markdown: the activities of the previous working day
+grouped by issue, repository or project, with repeated commits, comments and
+browser visits collapsed into one bullet each, followed by today's meetings.
+
+The date is the day of the standup and defaults to today; on Mondays the
+report covers Friday. Set standup.template in the config file, or pass
+--template, to replace the layout with your own Go text/template.`,
+	Example: `  # Today's standup
+  arkeo standup
+
+  # Copy it to the clipboard (macOS)
+  arkeo standup | pbcopy
+
+  # Use the team's Slack layout
+  arkeo standup --template ~/.config/arkeo/slack.tmpl`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runStandupCommand,
+}
+
+var (
+	standupTemplatePath string
+	standupNoCache      bool
+)
+
+func init() {
+	standupCmd.Flags().StringVar(&standupTemplatePath, "template", "", "text/template file or template name for the message (default from config)")
+	standupCmd.Flags().BoolVar(&standupNoCache, "no-cache", false, "Skip cache (always fetch from connectors, don't store results)")
+}
+
+func runStandupCommand(cmd *cobra.Command, args []string) {
+	today := time.Now()
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	if len(args) > 0 {
+		today = parseDateArg(args)
+	}
+	reportDay := previousWorkingDay(today)
+
+	configManager, registry := initializeSystem()
+
+	templateText, err := standupTemplate(configManager, standupTemplatePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	enabledConnectors := getEnabledConnectors(configManager, registry)
+	if len(enabledConnectors) == 0 {
+		fmt.Fprintln(os.Stderr, "No connectors are enabled. Use 'arkeo connectors list' to see available connectors.")
+		os.Exit(1)
+	}
+
+	var activityCache *cache.Cache
+	if !standupNoCache {
+		activityCache = openActivityCache(configManager)
+		if activityCache != nil {
+			defer activityCache.Close()
+		}
+	}
+
+	activities, _, _ := loadActivities(context.Background(), enabledConnectors, activityCache, []time.Time{reportDay, today}, false)
+	activities, _ = deduplicateActivities(configManager, activities)
+	applyRules(configManager, activities)
+
+	if err := display.DisplayStandup(activities, reportDay, today, templateText); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// previousWorkingDay returns the last Monday to Friday before day
+func previousWorkingDay(day time.Time) time.Time {
+	day = day.AddDate(0, 0, -1)
+	for day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day
+}
+
+// standupTemplate reads the standup template from path, a file or the name
+// of a template in the templates directory, or from the file set in the
+// standup section of the config, relative to the config directory. It
+// returns "" for the default layout.
+func standupTemplate(configManager *config.Manager, path string) (string, error) {
+	if path != "" {
+		return readTemplate(configManager, path)
+	}
+
+	path = configManager.GetConfig().Standup.Template
+	if path == "" {
+		return "", nil
+	}
+	if configDir, err := configManager.GetConfigDir(); err == nil && !filepath.IsAbs(path) && !strings.HasPrefix(path, "~") {
+		path = filepath.Join(configDir, path)
+	}
+	return readTemplate(configManager, path)
+}
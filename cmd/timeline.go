@@ -5,14 +5,15 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/arkeo/arkeo/internal/cache"
+	"github.com/arkeo/arkeo/internal/config"
 	"github.com/arkeo/arkeo/internal/display"
-	"github.com/arkeo/arkeo/internal/timeline"
-	"github.com/arkeo/arkeo/internal/utils"
+	"github.com/arkeo/arkeo/internal/display/formatters"
 )
 
 // timelineCmd shows the timeline for a specific date
@@ -27,8 +28,27 @@ If no date is provided, defaults to yesterday. Date format: YYYY-MM-DD
 Use --range N to fetch activities for the last N days ending at the selected date.
 For example, --range 180 fetches ~6 months of history.
 
+Use --format markdown for a standup report of each day, with activities grouped
+by issue, repository or project (see also 'arkeo standup').
+
+Use --template to render the days with your own Go text/template, given as a
+file or as the name of a template in ~/.config/arkeo/templates (weekly for
+templates/weekly.tmpl). See the README for the data and functions available.
+
+Use --format csv or tsv for one row per activity, to paste into a spreadsheet or
+import elsewhere. --columns picks the columns: date, time, end_time, duration
+(in minutes), source, type, title, description, url and metadata.<key>.
+
 Past days are cached in a local SQLite database. Use --reset-cache to force
 re-fetching from connectors.`,
+	Example: `  # Weekly report from ~/.config/arkeo/templates/weekly.tmpl
+  arkeo timeline --week --template weekly
+
+  # Same, with an explicit path
+  arkeo timeline --week --format template --template ~/reports/weekly.tmpl
+
+  # The last month as CSV with the project set by rules
+  arkeo timeline --range 30 --format csv --columns date,time,duration,title,metadata.project > month.csv`,
 	Args: cobra.MaximumNArgs(1),
 	Run:  runTimelineCommand,
 }
@@ -40,48 +60,75 @@ var (
 	rangeDays   int
 	resetCache  bool
 	noCache     bool
+
+	timelineTemplate string
+	timelineColumns  []string
 )
 
 func init() {
-	timelineCmd.Flags().StringVar(&format, "format", "table", "Output format (table, json)")
+	timelineCmd.Flags().StringVar(&format, "format", "table", "Output format (table, json, markdown, template, csv, tsv)")
 	timelineCmd.Flags().IntVar(&maxItems, "max-items", 0, "Maximum number of activities to display (0 = unlimited)")
 	timelineCmd.Flags().BoolVar(&week, "week", false, "Display activities for the entire work week (Monday-Friday) containing the selected date")
 	timelineCmd.Flags().IntVar(&rangeDays, "range", 0, "Fetch activities for the last N days ending at the selected date (e.g. --range 180 for ~6 months)")
 	timelineCmd.Flags().BoolVar(&resetCache, "reset-cache", false, "Clear cached activities for the selected date range before fetching")
 	timelineCmd.Flags().BoolVar(&noCache, "no-cache", false, "Skip cache (always fetch from connectors, don't store results)")
+	timelineCmd.Flags().StringVar(&timelineTemplate, "template", "", "text/template file or template name for the template and markdown formats")
+	timelineCmd.Flags().StringSliceVar(&timelineColumns, "columns", nil, "Columns of the csv and tsv formats (default date,time,end_time,duration,source,type,title,description,url)")
 }
 
 func runTimelineCommand(cmd *cobra.Command, args []string) {
 	// Parse date argument or default to yesterday
-	var dateStr string
-	if len(args) > 0 {
-		dateStr = args[0]
-	} else {
-		yesterday := time.Now().AddDate(0, 0, -1)
-		dateStr = yesterday.Format("2006-01-02")
-	}
+	targetDate := parseDateArg(args)
 
-	parsedDate, err := time.Parse("2006-01-02", dateStr)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Invalid date format. Use YYYY-MM-DD: %v\n", err)
-		os.Exit(1)
+	// A template alone selects the template format
+	if timelineTemplate != "" && !cmd.Flags().Changed("format") {
+		format = "template"
 	}
-	targetDate := parsedDate
 
 	// Initialize configuration and connectors
 	configManager, registry := initializeSystem()
 	_ = configManager.GetConfig()
 
+	// Read the template before fetching anything so that mistakes show at once
+	var templateText string
+	switch format {
+	case "table", "json":
+	case "markdown":
+		text, err := standupTemplate(configManager, timelineTemplate)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		templateText = text
+	case "template":
+		if timelineTemplate == "" {
+			fmt.Fprintln(os.Stderr, "Error: --format template needs --template")
+			os.Exit(1)
+		}
+		text, err := readTemplate(configManager, timelineTemplate)
+		if err == nil {
+			_, err = formatters.ParseTemplate(timelineTemplate, text)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		templateText = text
+	case "csv", "tsv":
+		if err := formatters.ValidateColumns(timelineColumns); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown format %q (expected table, json, markdown, template, csv or tsv)\n", format)
+		os.Exit(1)
+	}
+
 	// Initialize cache (unless --no-cache)
 	var activityCache *cache.Cache
 	if !noCache {
-		configDir, err := configManager.GetConfigDir()
-		if err == nil {
-			cachePath := filepath.Join(configDir, "cache.db")
-			activityCache, err = cache.New(cachePath)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: could not open cache: %v\n", err)
-			}
+		activityCache = openActivityCache(configManager)
+		if activityCache != nil {
 			defer activityCache.Close()
 		}
 	}
@@ -121,35 +168,11 @@ func runTimelineCommand(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	// Convert connectors to utils.Connector interface
-	utilsConnectors := make(map[string]utils.Connector)
-	connectorNames := make([]string, 0, len(enabledConnectors))
-	for name, conn := range enabledConnectors {
-		utilsConnectors[name] = conn
-		connectorNames = append(connectorNames, name)
-	}
-
 	isMachineReadable := isMachineReadableFormat(format)
 	verbose := !isMachineReadable
 
 	// Build the list of days to fetch
-	var daysToFetch []time.Time
-	if rangeDays > 0 {
-		daysToFetch = make([]time.Time, rangeDays)
-		for i := 0; i < rangeDays; i++ {
-			daysToFetch[i] = targetDate.AddDate(0, 0, -(rangeDays - 1 - i)).Truncate(24 * time.Hour)
-		}
-	} else if week {
-		weekday := targetDate.Weekday()
-		daysFromMonday := (int(weekday) - int(time.Monday) + 7) % 7
-		monday := targetDate.AddDate(0, 0, -daysFromMonday).Truncate(24 * time.Hour)
-		daysToFetch = make([]time.Time, 5)
-		for i := 0; i < 5; i++ {
-			daysToFetch[i] = monday.AddDate(0, 0, i)
-		}
-	} else {
-		daysToFetch = []time.Time{targetDate.Truncate(24 * time.Hour)}
-	}
+	daysToFetch := buildDays(targetDate, week, rangeDays)
 
 	if !isMachineReadable {
 		if len(daysToFetch) > 1 {
@@ -163,74 +186,33 @@ func runTimelineCommand(cmd *cobra.Command, args []string) {
 	}
 
 	// Fetch activities for each day, using cache when available
-	var allActivities []timeline.Activity
-	cachedDays := 0
-	fetchedDays := 0
-
-	for _, day := range daysToFetch {
-		// Check cache first (unless --no-cache)
-		if activityCache != nil && activityCache.HasDay(day, connectorNames) {
-			cachedActivities, err := activityCache.LoadDay(day)
-			if err == nil {
-				allActivities = append(allActivities, cachedActivities...)
-				cachedDays++
-				if verbose {
-					fmt.Printf("  %s: %d activities (cached)\n",
-						day.Format("2006-01-02"), len(cachedActivities))
-				}
-				continue
-			}
-		}
+	allActivities, cachedDays, fetchedDays := loadActivities(ctx, enabledConnectors, activityCache, daysToFetch, verbose)
 
-		// Cache miss — fetch from connectors
-		executor := utils.NewParallelExecutor()
-		results := executor.FetchActivitiesParallel(ctx, utilsConnectors, day)
-
-		var dayActivities []timeline.Activity
-		for _, result := range results {
-			if result.Error != nil {
-				if verbose {
-					fmt.Fprintf(os.Stderr, "  Warning: %s: %v\n", result.Name, result.Error)
-				}
-				continue
-			}
+	// Merge the same work reported by several sources
+	allActivities, duplicates := deduplicateActivities(configManager, allActivities)
 
-			if verbose {
-				fmt.Printf("  %s %s: %d activities (took %v)\n",
-					day.Format("2006-01-02"),
-					result.Name, len(result.Activities), result.Duration.Round(time.Millisecond))
-			}
-
-			dayActivities = append(dayActivities, result.Activities...)
-
-			// Store in cache (unless --no-cache) — store even when 0 activities
-			// so HasDay knows this connector was fetched for this day.
-			if activityCache != nil {
-				if err := activityCache.StoreDay(day, result.Name, result.Activities); err != nil {
-					if verbose {
-						fmt.Fprintf(os.Stderr, "  Warning: could not cache %s/%s: %v\n", day.Format("2006-01-02"), result.Name, err)
-					}
-				}
-			}
-		}
-
-		allActivities = append(allActivities, dayActivities...)
-		fetchedDays++
-	}
+	// Tag activities with projects and clients from the rules section
+	applyRules(configManager, allActivities)
 
 	if !isMachineReadable {
 		cacheInfo := ""
 		if cachedDays > 0 {
 			cacheInfo = fmt.Sprintf(" (%d from cache, %d fetched)", cachedDays, fetchedDays)
 		}
-		fmt.Printf("Fetched %d activities from %d connector(s) across %d days%s.\n",
-			len(allActivities), len(enabledConnectors), len(daysToFetch), cacheInfo)
+		dedupInfo := ""
+		if duplicates > 0 {
+			dedupInfo = fmt.Sprintf(", %d duplicates merged", duplicates)
+		}
+		fmt.Printf("Fetched %d activities from %d connector(s) across %d days%s%s.\n",
+			len(allActivities), len(enabledConnectors), len(daysToFetch), cacheInfo, dedupInfo)
 	}
 
 	// Prepare display options
 	opts := display.TimelineOptions{
 		MaxItems: maxItems,
 		Format:   format,
+		Template: templateText,
+		Columns:  timelineColumns,
 	}
 
 	if len(daysToFetch) > 1 {
@@ -250,5 +232,33 @@ func runTimelineCommand(cmd *cobra.Command, args []string) {
 }
 
 func isMachineReadableFormat(format string) bool {
-	return format == "json"
+	switch format {
+	case "json", "markdown", "template", "csv", "tsv":
+		return true
+	}
+	return false
+}
+
+// readTemplate reads a template from a file, or the template of that name
+// in the templates directory of the config directory
+func readTemplate(configManager *config.Manager, ref string) (string, error) {
+	if formatters.IsTemplateName(ref) {
+		configDir, err := configManager.GetConfigDir()
+		if err != nil {
+			return "", err
+		}
+		return formatters.ReadNamedTemplate(filepath.Join(configDir, formatters.TemplatesDir), ref)
+	}
+
+	path := ref
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read template: %w", err)
+	}
+	return string(data), nil
 }
\ No newline at end of file
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/arkeo/arkeo/internal/cache"
+	"github.com/arkeo/arkeo/internal/display"
+	"github.com/arkeo/arkeo/internal/timesheet"
+)
+
+var timesheetCmd = &cobra.Command{
+	Use:   "timesheet [date]",
+	Short: "Turn activities into hours per project and day",
+	Long: `Build a timesheet from your activities. Work blocks are inferred from activity
+density: calendar events and browser sessions count for their duration, commits and
+issue updates are clustered, and idle periods from the macos_system and linux_session
+connectors are left out. Each block is attributed to a project using the activity
+metadata (repository, YouTrack project, calendar title).
+
+The result is a project × day grid of hours, rounded to the increment configured in
+the timesheet section of the config file (or --round). By default the work week
+containing the given date (or yesterday) is shown. Cached days are reused.`,
+	Example: `  # Timesheet for last week's working days
+  arkeo timesheet --week 2024-01-15
+
+  # The last 14 days as CSV, rounded to half hours
+  arkeo timesheet --range 14 --round 30 --format csv`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runTimesheetCommand,
+}
+
+var (
+	timesheetFormat    string
+	timesheetWeek      bool
+	timesheetRangeDays int
+	timesheetRound     int
+	timesheetNoCache   bool
+)
+
+func init() {
+	timesheetCmd.Flags().StringVar(&timesheetFormat, "format", "table", "Output format (table, csv, json)")
+	timesheetCmd.Flags().BoolVar(&timesheetWeek, "week", false, "Use the work week (Monday-Friday) containing the selected date (default)")
+	timesheetCmd.Flags().IntVar(&timesheetRangeDays, "range", 0, "Use the last N days ending at the selected date")
+	timesheetCmd.Flags().IntVar(&timesheetRound, "round", -1, "Rounding increment in minutes (0 = no rounding, default from config)")
+	timesheetCmd.Flags().BoolVar(&timesheetNoCache, "no-cache", false, "Skip cache (always fetch from connectors, don't store results)")
+}
+
+func runTimesheetCommand(cmd *cobra.Command, args []string) {
+	targetDate := parseDateArg(args)
+
+	switch timesheetFormat {
+	case "table", "csv", "json":
+	default:
+		fmt.Fprintf(os.Stderr, "Invalid format %q. Use table, csv or json.\n", timesheetFormat)
+		os.Exit(1)
+	}
+
+	configManager, registry := initializeSystem()
+	tsConfig := configManager.GetConfig().Timesheet
+
+	var activityCache *cache.Cache
+	if !timesheetNoCache {
+		activityCache = openActivityCache(configManager)
+		if activityCache != nil {
+			defer activityCache.Close()
+		}
+	}
+
+	enabledConnectors := getEnabledConnectors(configManager, registry)
+	if len(enabledConnectors) == 0 {
+		fmt.Println("No connectors are enabled. Use 'arkeo connectors list' to see available connectors.")
+		fmt.Println("Enable a connector with: arkeo connectors enable <connector-name>")
+		return
+	}
+
+	// A timesheet always covers several days; default to the work week
+	week := timesheetWeek || timesheetRangeDays <= 0
+	days := buildDays(targetDate, week, timesheetRangeDays)
+
+	verbose := timesheetFormat == "table"
+	if verbose {
+		fmt.Printf("Fetching activities for %d days (%s to %s)...\n",
+			len(days), days[0].Format("2006-01-02"), days[len(days)-1].Format("2006-01-02"))
+	}
+
+	activities, _, _ := loadActivities(context.Background(), enabledConnectors, activityCache, days, verbose)
+	activities, _ = deduplicateActivities(configManager, activities)
+	applyRules(configManager, activities)
+
+	opts := timesheet.Options{
+		Increment:     time.Duration(tsConfig.RoundMinutes) * time.Minute,
+		MaxGap:        time.Duration(tsConfig.GapMinutes) * time.Minute,
+		PointDuration: time.Duration(tsConfig.ActivityMinutes) * time.Minute,
+	}
+	if timesheetRound >= 0 {
+		opts.Increment = time.Duration(timesheetRound) * time.Minute
+	}
+
+	ts := timesheet.Build(activities, days, opts)
+
+	if verbose {
+		fmt.Println()
+	}
+
+	if err := display.DisplayTimesheet(ts, timesheetFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "Error displaying timesheet: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -5,12 +5,10 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
-	"path/filepath"
 	"syscall"
 
 	"github.com/spf13/cobra"
 
-	"github.com/arkeo/arkeo/internal/cache"
 	"github.com/arkeo/arkeo/internal/web"
 )
 
@@ -21,39 +19,46 @@ var webCmd = &cobra.Command{
 for browsing timelines, managing connectors, and configuring browser domain exclusions.
 
 The web UI uses a dark theme and requires no JavaScript frameworks — just vanilla JS
-for API calls and rendering.`,
+for API calls and rendering.
+
+With --prefetch, the web UI also keeps the cache warm like 'arkeo daemon'.`,
 	Args: cobra.NoArgs,
 	Run:  runWebCommand,
 }
 
 func init() {
-	// --addr flag is registered on rootCmd as a persistent flag
+	// --addr and --prefetch flags are registered on rootCmd as persistent flags
 }
 
 func runWebCommand(cmd *cobra.Command, args []string) {
 	configManager, registry := initializeSystem()
 
 	// Initialize cache
-	var activityCache *cache.Cache
-	configDir, err := configManager.GetConfigDir()
-	if err == nil {
-		cachePath := filepath.Join(configDir, "cache.db")
-		activityCache, err = cache.New(cachePath)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: could not open cache: %v\n", err)
-		}
+	activityCache := openActivityCache(configManager)
+	if activityCache != nil {
 		defer activityCache.Close()
 	}
 
 	// Create and start the web server
 	server := web.New(configManager, registry, activityCache)
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The prefetcher gets connectors of its own so that it doesn't share
+	// connector state with requests from the web UI
+	if webPrefetch && activityCache != nil {
+		_, prefetchRegistry := initializeSystem()
+		go newPrefetcher(configManager, prefetchRegistry, activityCache).Run(ctx)
+	}
+
 	// Handle graceful shutdown
 	go func() {
 		sigCh := make(chan os.Signal, 1)
 		signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 		<-sigCh
 		fmt.Fprintln(os.Stderr, "\nShutting down...")
+		cancel()
 		server.Shutdown(context.Background())
 	}()
 
@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/arkeo/arkeo/internal/cache"
+	"github.com/arkeo/arkeo/internal/connectors"
+	"github.com/arkeo/arkeo/internal/display/colors"
+	"github.com/arkeo/arkeo/internal/timeline"
+	"github.com/arkeo/arkeo/internal/timesheet"
+)
+
+// youtrackCmd groups commands that write to YouTrack
+var youtrackCmd = &cobra.Command{
+	Use:   "youtrack",
+	Short: "YouTrack tools",
+	Long:  `Tools for working with YouTrack beyond reading activities.`,
+}
+
+var youtrackLogCmd = &cobra.Command{
+	Use:   "log [date]",
+	Short: "Log reconstructed time to YouTrack issues as work items",
+	Long: `Turn your activities into YouTrack work items. Time is attributed to the issue
+keys found in activity metadata (issue_key) or titles (e.g. ACME-123) and summed
+per issue and day.
+
+With --from blocks (default), the work blocks inferred for the timesheet are
+attributed to the issue most of their activities refer to. With --from activities,
+each activity referring to an issue counts for its own duration, or for the
+timesheet activity_minutes when it has none. Restrict the entries with --issue or
+--activity.
+
+Issues on which you already logged time that day are skipped, so running the
+command twice doesn't book the same time twice. Use --dry-run to preview.`,
+	Example: `  # Preview yesterday's work items
+  arkeo youtrack log --dry-run
+
+  # Log a whole week, one issue only
+  arkeo youtrack log --week 2024-01-15 --issue ACME-123
+
+  # Log the time of two specific activities
+  arkeo youtrack log 2024-01-15 --from activities --activity gitlab-1234 --activity calendar-abc`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runYouTrackLog,
+}
+
+var (
+	youtrackLogFrom       string
+	youtrackLogWeek       bool
+	youtrackLogRangeDays  int
+	youtrackLogRound      int
+	youtrackLogIssues     []string
+	youtrackLogActivities []string
+	youtrackLogDryRun     bool
+	youtrackLogNoCache    bool
+	youtrackLogConnector  string
+)
+
+func init() {
+	youtrackLogCmd.Flags().StringVar(&youtrackLogFrom, "from", "blocks", "Derive time from inferred work blocks or from activities (blocks, activities)")
+	youtrackLogCmd.Flags().BoolVar(&youtrackLogWeek, "week", false, "Use the work week (Monday-Friday) containing the selected date")
+	youtrackLogCmd.Flags().IntVar(&youtrackLogRangeDays, "range", 0, "Use the last N days ending at the selected date")
+	youtrackLogCmd.Flags().IntVar(&youtrackLogRound, "round", -1, "Rounding increment in minutes (0 = no rounding, default from config)")
+	youtrackLogCmd.Flags().StringSliceVar(&youtrackLogIssues, "issue", nil, "Only log time for these issue keys")
+	youtrackLogCmd.Flags().StringSliceVar(&youtrackLogActivities, "activity", nil, "Only use the activities with these IDs")
+	youtrackLogCmd.Flags().BoolVar(&youtrackLogDryRun, "dry-run", false, "Show the work items that would be created without creating them")
+	youtrackLogCmd.Flags().BoolVar(&youtrackLogNoCache, "no-cache", false, "Skip cache (always fetch from connectors, don't store results)")
+	youtrackLogCmd.Flags().StringVar(&youtrackLogConnector, "connector", "youtrack", "YouTrack connector instance to log to (e.g. youtrack:client-a)")
+
+	youtrackCmd.AddCommand(youtrackLogCmd)
+}
+
+func runYouTrackLog(cmd *cobra.Command, args []string) {
+	targetDate := parseDateArg(args)
+
+	if youtrackLogFrom != "blocks" && youtrackLogFrom != "activities" {
+		fmt.Fprintf(os.Stderr, "Invalid --from %q. Use blocks or activities.\n", youtrackLogFrom)
+		os.Exit(1)
+	}
+
+	configManager, registry := initializeSystem()
+
+	enabledConnectors := getEnabledConnectors(configManager, registry)
+	youtrack, ok := enabledConnectors[youtrackLogConnector].(*connectors.YouTrackConnector)
+	if !ok {
+		fmt.Printf("The %s connector is not an enabled YouTrack connector. Enable it with: arkeo connectors enable %s\n",
+			youtrackLogConnector, youtrackLogConnector)
+		return
+	}
+
+	var activityCache *cache.Cache
+	if !youtrackLogNoCache {
+		activityCache = openActivityCache(configManager)
+		if activityCache != nil {
+			defer activityCache.Close()
+		}
+	}
+
+	days := buildDays(targetDate, youtrackLogWeek, youtrackLogRangeDays)
+	fmt.Printf("Fetching activities for %d day(s) (%s to %s)...\n",
+		len(days), days[0].Format("2006-01-02"), days[len(days)-1].Format("2006-01-02"))
+
+	ctx := context.Background()
+	activities, _, _ := loadActivities(ctx, enabledConnectors, activityCache, days, true)
+	activities, _ = deduplicateActivities(configManager, activities)
+	applyRules(configManager, activities)
+
+	tsConfig := configManager.GetConfig().Timesheet
+	opts := timesheet.Options{
+		Increment:     time.Duration(tsConfig.RoundMinutes) * time.Minute,
+		MaxGap:        time.Duration(tsConfig.GapMinutes) * time.Minute,
+		PointDuration: time.Duration(tsConfig.ActivityMinutes) * time.Minute,
+	}
+	if youtrackLogRound >= 0 {
+		opts.Increment = time.Duration(youtrackLogRound) * time.Minute
+	}
+
+	// Only keys of YouTrack projects are logged to, not look-alikes such as
+	// UTF-8 or the keys of other issue trackers
+	projects, err := youtrack.ProjectShortNames(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not list YouTrack projects, only using issues of YouTrack activities: %v\n", err)
+	}
+	opts.IssueProjects = projects
+
+	items := planWorkItems(activities, days, opts, youtrackLogFrom, youtrackLogActivities, youtrackLogIssues)
+	if len(items) == 0 {
+		fmt.Println("\nNo time could be attributed to an issue.")
+		return
+	}
+
+	results, err := youtrack.LogWorkItems(ctx, items, youtrackLogDryRun)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error logging work items: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println()
+	printWorkItemResults(results)
+
+	if youtrackLogDryRun {
+		fmt.Println("\nDry run: nothing was created. Run without --dry-run to create the new work items.")
+	}
+}
+
+// planWorkItems turns activities into one work item per issue and day.
+// activityIDs and issueKeys restrict the activities and issues used when set.
+func planWorkItems(activities []timeline.Activity, days []time.Time, opts timesheet.Options, from string, activityIDs, issueKeys []string) []connectors.YouTrackWorkItem {
+	if len(activityIDs) > 0 {
+		selected := make(map[string]bool, len(activityIDs))
+		for _, id := range activityIDs {
+			selected[id] = true
+		}
+		var filtered []timeline.Activity
+		for _, a := range activities {
+			if selected[a.ID] {
+				filtered = append(filtered, a)
+			}
+		}
+		activities = filtered
+	}
+
+	var entries []timesheet.IssueEntry
+	if from == "activities" {
+		entries = timesheet.IssueEntriesFromActivities(activities, opts)
+	} else {
+		ts := timesheet.Build(activities, days, opts)
+		entries = timesheet.IssueEntriesFromBlocks(ts, activities, opts)
+	}
+
+	wanted := make(map[string]bool, len(issueKeys))
+	for _, key := range issueKeys {
+		wanted[strings.ToUpper(strings.TrimSpace(key))] = true
+	}
+
+	var items []connectors.YouTrackWorkItem
+	for _, e := range entries {
+		if len(wanted) > 0 && !wanted[e.IssueKey] {
+			continue
+		}
+		items = append(items, connectors.YouTrackWorkItem{
+			IssueKey: e.IssueKey,
+			Date:     e.Date,
+			Duration: e.Duration,
+			Text:     e.Text,
+		})
+	}
+	return items
+}
+
+// printWorkItemResults prints one line per work item with its status
+func printWorkItemResults(results []connectors.WorkItemResult) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "DATE\tISSUE\tTIME\tSTATUS\tTEXT")
+
+	counts := make(map[string]int)
+	for _, r := range results {
+		status := r.Status
+		switch r.Status {
+		case connectors.WorkItemStatusExists:
+			status = fmt.Sprintf("skipped (%s already logged)", colors.FormatDuration(r.Existing))
+		case connectors.WorkItemStatusError:
+			status = "error: " + r.Error
+		}
+		counts[r.Status]++
+
+		text := r.Text
+		if runes := []rune(text); len(runes) > 60 {
+			text = string(runes[:57]) + "..."
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			r.Date.Format("2006-01-02"), r.IssueKey, colors.FormatDuration(r.Duration), status, text)
+	}
+	w.Flush()
+
+	fmt.Printf("\n%d new, %d created, %d skipped, %d failed\n",
+		counts[connectors.WorkItemStatusNew], counts[connectors.WorkItemStatusCreated],
+		counts[connectors.WorkItemStatusExists], counts[connectors.WorkItemStatusError])
+}
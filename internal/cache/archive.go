@@ -0,0 +1,245 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/arkeo/arkeo/internal/timeline"
+)
+
+// ArchiveFormat identifies cache archives in their header line
+const ArchiveFormat = "arkeo-cache"
+
+// archiveVersion is the version of the archive format written by Export
+const archiveVersion = 1
+
+// Entry is what the cache holds for one connector on one day
+type Entry struct {
+	Date          string              `json:"date"` // YYYY-MM-DD
+	Connector     string              `json:"connector"`
+	CachedAt      time.Time           `json:"cached_at"`
+	OutputVersion int                 `json:"output_version,omitempty"`
+	Activities    []timeline.Activity `json:"activities"`
+}
+
+// archiveHeader is the first line of an archive
+type archiveHeader struct {
+	Format     string    `json:"format"`
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+}
+
+// MergeMode decides what Import does with an entry the cache already has
+type MergeMode string
+
+const (
+	// MergeSkip keeps the entry in the cache
+	MergeSkip MergeMode = "skip"
+	// MergeOverwrite replaces it with the imported entry
+	MergeOverwrite MergeMode = "overwrite"
+	// MergeNewest keeps whichever entry was cached last
+	MergeNewest MergeMode = "newest"
+)
+
+// ParseMergeMode validates a merge mode given on the command line
+func ParseMergeMode(s string) (MergeMode, error) {
+	switch mode := MergeMode(s); mode {
+	case MergeSkip, MergeOverwrite, MergeNewest:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown merge mode %q (expected skip, overwrite or newest)", s)
+}
+
+// ImportStats counts what Import did
+type ImportStats struct {
+	Imported int
+	Skipped  int
+}
+
+// Export writes the entries between from and to (inclusive, zero =
+// unbounded) to w as a gzip-compressed archive: a header line followed by
+// one JSON entry per line, by date and connector. It returns the number of
+// entries written.
+func (c *Cache) Export(w io.Writer, from, to time.Time) (int, error) {
+	gz := gzip.NewWriter(w)
+	enc := json.NewEncoder(gz)
+
+	if err := enc.Encode(archiveHeader{Format: ArchiveFormat, Version: archiveVersion, ExportedAt: time.Now()}); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err := c.Entries(from, to, func(entry Entry) error {
+		count++
+		return enc.Encode(entry)
+	})
+	if err != nil {
+		return count, err
+	}
+	return count, gz.Close()
+}
+
+// Entries calls fn for every entry between from and to (inclusive, zero =
+// unbounded), by date and connector
+func (c *Cache) Entries(from, to time.Time, fn func(Entry) error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	condition, args := dateRange(from, to)
+	rows, err := c.db.Query(
+		"SELECT date, connector, cached_at, output_version FROM activity_cache WHERE "+condition+" ORDER BY date, connector",
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to query cache: %w", err)
+	}
+	var entries []Entry
+	for rows.Next() {
+		var entry Entry
+		var cachedAt int64
+		if err := rows.Scan(&entry.Date, &entry.Connector, &cachedAt, &entry.OutputVersion); err != nil {
+			rows.Close()
+			return err
+		}
+		entry.CachedAt = time.Unix(cachedAt, 0)
+		entries = append(entries, entry)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		activityRows, err := c.db.Query(
+			"SELECT "+activityColumns+" FROM activities WHERE date = ? AND connector = ? ORDER BY row_id",
+			entry.Date, entry.Connector,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to query cache: %w", err)
+		}
+		entry.Activities, err = scanActivities(activityRows)
+		activityRows.Close()
+		if err != nil {
+			return err
+		}
+		if entry.Activities == nil {
+			entry.Activities = []timeline.Activity{}
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Import reads an archive written by Export, compressed or not, and stores
+// its entries, keeping their connector, date and cached_at. Entries the
+// cache already has are merged according to mode. Nothing is imported when
+// the archive is invalid.
+func (c *Cache) Import(r io.Reader, mode MergeMode) (ImportStats, error) {
+	var stats ImportStats
+
+	reader := bufio.NewReader(r)
+	if magic, _ := reader.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return stats, fmt.Errorf("invalid archive: %w", err)
+		}
+		defer gz.Close()
+		reader = bufio.NewReader(gz)
+	}
+
+	dec := json.NewDecoder(reader)
+	var header archiveHeader
+	if err := dec.Decode(&header); err != nil || header.Format != ArchiveFormat {
+		return stats, fmt.Errorf("not an arkeo cache archive")
+	}
+	if header.Version > archiveVersion {
+		return stats, fmt.Errorf("archive version %d is newer than this arkeo supports (%d)", header.Version, archiveVersion)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tx, err := c.db.Begin()
+	if err != nil {
+		return stats, err
+	}
+	defer tx.Rollback()
+
+	for line := 2; ; line++ {
+		var entry Entry
+		if err := dec.Decode(&entry); err == io.EOF {
+			break
+		} else if err != nil {
+			return ImportStats{}, fmt.Errorf("invalid archive entry %d: %w", line, err)
+		}
+		if _, err := time.Parse("2006-01-02", entry.Date); err != nil || entry.Connector == "" {
+			return ImportStats{}, fmt.Errorf("invalid archive entry %d: missing date or connector", line)
+		}
+
+		replace, err := shouldReplace(tx, entry, mode)
+		if err != nil {
+			return ImportStats{}, err
+		}
+		if !replace {
+			stats.Skipped++
+			continue
+		}
+		if err := storeEntry(tx, entry); err != nil {
+			return ImportStats{}, err
+		}
+		stats.Imported++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ImportStats{}, err
+	}
+	return stats, nil
+}
+
+// shouldReplace decides whether an imported entry is stored
+func shouldReplace(tx *sql.Tx, entry Entry, mode MergeMode) (bool, error) {
+	var cachedAt int64
+	err := tx.QueryRow(
+		"SELECT cached_at FROM activity_cache WHERE date = ? AND connector = ?",
+		entry.Date, entry.Connector,
+	).Scan(&cachedAt)
+	if err == sql.ErrNoRows {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	switch mode {
+	case MergeOverwrite:
+		return true, nil
+	case MergeNewest:
+		return entry.CachedAt.Unix() > cachedAt, nil
+	default:
+		return false, nil
+	}
+}
+
+// dateRange returns a condition on the date column for the days between
+// from and to (inclusive, zero = unbounded)
+func dateRange(from, to time.Time) (string, []interface{}) {
+	condition := "1 = 1"
+	var args []interface{}
+	if !from.IsZero() {
+		condition += " AND date >= ?"
+		args = append(args, from.Format("2006-01-02"))
+	}
+	if !to.IsZero() {
+		condition += " AND date <= ?"
+		args = append(args, to.Format("2006-01-02"))
+	}
+	return condition, args
+}
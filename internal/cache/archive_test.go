@@ -0,0 +1,153 @@
+package cache
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arkeo/arkeo/internal/timeline"
+)
+
+func TestCache_ExportImport(t *testing.T) {
+	source := newTestCache(t)
+	jan15 := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	jan16 := jan15.AddDate(0, 0, 1)
+	feb1 := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	duration := 45 * time.Minute
+
+	source.StoreDay(jan15, "browser_history", []timeline.Activity{
+		{ID: "b1", Type: timeline.ActivityTypeBrowser, Title: "Billing dashboard", Source: "browser_history",
+			Timestamp: jan15.Add(9 * time.Hour), URL: "https://billing.example.com", Metadata: map[string]string{"domain": "billing.example.com"}},
+	})
+	source.StoreDay(jan15, "calendar", []timeline.Activity{
+		{ID: "c1", Type: timeline.ActivityTypeCalendar, Title: "Planning", Source: "calendar", Timestamp: jan15.Add(10 * time.Hour), Duration: &duration},
+	})
+	source.StoreDay(jan16, "calendar", nil)
+	source.StoreDay(feb1, "calendar", nil)
+
+	var archive bytes.Buffer
+	count, err := source.Export(&archive, jan15, jan16)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 entries in January, got %d", count)
+	}
+
+	target := newTestCache(t)
+	stats, err := target.Import(bytes.NewReader(archive.Bytes()), MergeNewest)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if stats.Imported != 3 || stats.Skipped != 0 {
+		t.Errorf("Expected 3 imported entries, got %+v", stats)
+	}
+
+	if !target.HasDay(jan15, []string{"browser_history", "calendar"}) || !target.HasDay(jan16, []string{"calendar"}) {
+		t.Error("Expected the imported days to be cached, including empty ones")
+	}
+	if target.HasDay(feb1, nil) {
+		t.Error("Expected days outside the range not to be exported")
+	}
+
+	loaded, _ := target.LoadDay(jan15)
+	if len(loaded) != 2 {
+		t.Fatalf("Expected 2 activities, got %d", len(loaded))
+	}
+	for _, a := range loaded {
+		if a.ID == "b1" && (a.URL != "https://billing.example.com" || a.Metadata["domain"] != "billing.example.com") {
+			t.Errorf("Browser activity not preserved: %+v", a)
+		}
+		if a.ID == "c1" && (a.Duration == nil || *a.Duration != duration) {
+			t.Errorf("Calendar duration not preserved: %v", a.Duration)
+		}
+	}
+
+	var sourceEntry, targetEntry Entry
+	source.Entries(jan15, jan15, func(e Entry) error { sourceEntry = e; return nil })
+	target.Entries(jan15, jan15, func(e Entry) error { targetEntry = e; return nil })
+	if !targetEntry.CachedAt.Equal(sourceEntry.CachedAt) {
+		t.Errorf("Expected cached_at %v to be preserved, got %v", sourceEntry.CachedAt, targetEntry.CachedAt)
+	}
+}
+
+func TestCache_ImportMergeModes(t *testing.T) {
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	activity := func(title string) []timeline.Activity {
+		return []timeline.Activity{{ID: "1", Title: title, Source: "github", Timestamp: date.Add(9 * time.Hour)}}
+	}
+
+	// An archive entry cached an hour before the existing one, and a new day
+	exported := newTestCache(t)
+	exported.StoreDay(date, "github", activity("From archive"))
+	exported.StoreDay(date.AddDate(0, 0, 1), "github", nil)
+	var archive bytes.Buffer
+	if _, err := exported.Export(&archive, time.Time{}, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	exported.db.Exec("UPDATE activity_cache SET cached_at = cached_at - 3600")
+	var olderArchive bytes.Buffer
+	exported.Export(&olderArchive, time.Time{}, time.Time{})
+
+	tests := []struct {
+		mode     MergeMode
+		archive  []byte
+		expected string
+		imported int
+	}{
+		{MergeSkip, archive.Bytes(), "Existing", 1},
+		{MergeOverwrite, olderArchive.Bytes(), "From archive", 2},
+		{MergeNewest, olderArchive.Bytes(), "Existing", 1},
+	}
+
+	for _, tt := range tests {
+		c := newTestCache(t)
+		c.StoreDay(date, "github", activity("Existing"))
+
+		stats, err := c.Import(bytes.NewReader(tt.archive), tt.mode)
+		if err != nil {
+			t.Fatalf("%s: Import failed: %v", tt.mode, err)
+		}
+		if stats.Imported != tt.imported || stats.Imported+stats.Skipped != 2 {
+			t.Errorf("%s: expected %d imported, got %+v", tt.mode, tt.imported, stats)
+		}
+		loaded, _ := c.LoadDay(date)
+		if len(loaded) != 1 || loaded[0].Title != tt.expected {
+			t.Errorf("%s: expected %q, got %v", tt.mode, tt.expected, loaded)
+		}
+	}
+
+	// newest-wins takes a more recent archive entry
+	c := newTestCache(t)
+	c.StoreDay(date, "github", activity("Existing"))
+	c.db.Exec("UPDATE activity_cache SET cached_at = cached_at - 7200")
+	c.Import(bytes.NewReader(archive.Bytes()), MergeNewest)
+	if loaded, _ := c.LoadDay(date); len(loaded) != 1 || loaded[0].Title != "From archive" {
+		t.Errorf("Expected the newer archive entry to win, got %v", loaded)
+	}
+}
+
+func TestCache_ImportInvalid(t *testing.T) {
+	c := newTestCache(t)
+
+	if _, err := c.Import(strings.NewReader(`{"date":"2024-01-15","connector":"github"}`), MergeNewest); err == nil {
+		t.Error("Expected an error for a file without archive header")
+	}
+
+	// A broken entry imports nothing, not even the valid ones before it
+	archive := `{"format":"arkeo-cache","version":1}
+{"date":"2024-01-15","connector":"github","cached_at":"2024-01-16T08:00:00Z","activities":[]}
+{"date":"bad","connector":"github","cached_at":"2024-01-16T08:00:00Z","activities":[]}
+`
+	if _, err := c.Import(strings.NewReader(archive), MergeNewest); err == nil {
+		t.Error("Expected an error for an invalid entry")
+	}
+	if c.HasDay(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), nil) {
+		t.Error("Expected nothing to be imported from an invalid archive")
+	}
+
+	if _, err := ParseMergeMode("newest-wins"); err == nil {
+		t.Error("Expected an error for an unknown merge mode")
+	}
+}
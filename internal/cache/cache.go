@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -17,9 +18,56 @@ import (
 // Cache stores fetched activities in a SQLite database so that past days
 // don't need to be re-fetched from connectors on every run.
 type Cache struct {
-	db   *sql.DB
-	path string
-	mu   sync.Mutex
+	db        *sql.DB
+	path      string
+	mu        sync.Mutex
+	freshness Freshness
+
+	// Output version of each connector's activities, see SetOutputVersions
+	outputVersions map[string]int
+}
+
+// Freshness decides when a cached day is out of date. A day can still
+// change until HorizonDays days have started since it began (2 = while it
+// is today or yesterday). An entry cached before then is refetched once it
+// is older than its connector's TTL; an entry cached afterwards is final.
+// The zero value treats every entry as final.
+type Freshness struct {
+	HorizonDays int
+
+	// TTL for connectors without an entry in ConnectorTTL (0 = never refetch)
+	TTL          time.Duration
+	ConnectorTTL map[string]time.Duration
+
+	// Now returns the current time; time.Now when nil
+	Now func() time.Time
+}
+
+// settlesAt returns when a day can no longer change
+func (f Freshness) settlesAt(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day()+f.HorizonDays, 0, 0, 0, 0, time.Local)
+}
+
+// IsStale reports whether an entry of connector for date, cached at
+// cachedAt, needs to be refetched
+func (f Freshness) IsStale(date time.Time, connector string, cachedAt time.Time) bool {
+	if f.HorizonDays <= 0 || !cachedAt.Before(f.settlesAt(date)) {
+		return false
+	}
+
+	ttl, ok := f.ConnectorTTL[connector]
+	if !ok {
+		ttl = f.TTL
+	}
+	if ttl <= 0 {
+		return false
+	}
+
+	now := time.Now()
+	if f.Now != nil {
+		now = f.Now()
+	}
+	return now.Sub(cachedAt) > ttl
 }
 
 // New opens (or creates) the cache database at the given path.
@@ -29,29 +77,106 @@ func New(dbPath string) (*Cache, error) {
 		return nil, fmt.Errorf("failed to create cache directory: %w", err)
 	}
 
-	db, err := sql.Open("sqlite", dbPath)
+	// Wait for locks instead of failing when the daemon and another arkeo
+	// process write at the same time
+	db, err := sql.Open("sqlite", dbPath+"?_pragma=busy_timeout(5000)")
 	if err != nil {
 		return nil, fmt.Errorf("failed to open cache database: %w", err)
 	}
 
-	if err := initSchema(db); err != nil {
+	if err := migrate(db); err != nil {
 		db.Close()
-		return nil, fmt.Errorf("failed to init cache schema: %w", err)
+		return nil, fmt.Errorf("failed to migrate cache schema: %w", err)
 	}
 
 	return &Cache{db: db, path: dbPath}, nil
 }
 
-// initSchema creates the cache table if it doesn't exist.
-func initSchema(db *sql.DB) error {
-	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS activity_cache (
-		date        TEXT    NOT NULL,
-		connector   TEXT    NOT NULL,
-		activities  TEXT    NOT NULL,
-		cached_at   INTEGER NOT NULL,
-		PRIMARY KEY (date, connector)
-	)`)
-	return err
+// activityColumns are the columns scanActivities reads, in order
+const activityColumns = "id, type, source, timestamp, duration, title, description, url, metadata"
+
+// insertActivities adds the activities of a (date, connector) pair
+func insertActivities(tx *sql.Tx, dateStr, connector string, activities []timeline.Activity) error {
+	if len(activities) == 0 {
+		return nil
+	}
+
+	stmt, err := tx.Prepare(`INSERT INTO activities
+		(date, connector, id, type, source, timestamp, unix_time, duration, title, description, url, metadata)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, a := range activities {
+		var duration, metadata interface{}
+		if a.Duration != nil {
+			duration = int64(*a.Duration)
+		}
+		if len(a.Metadata) > 0 {
+			metadataJSON, err := json.Marshal(a.Metadata)
+			if err != nil {
+				return fmt.Errorf("failed to marshal metadata: %w", err)
+			}
+			metadata = string(metadataJSON)
+		}
+
+		_, err := stmt.Exec(dateStr, connector, a.ID, string(a.Type), a.Source,
+			a.Timestamp.Format(time.RFC3339Nano), a.Timestamp.Unix(), duration,
+			a.Title, a.Description, a.URL, metadata)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scanActivities reads activities selected with activityColumns
+func scanActivities(rows *sql.Rows) ([]timeline.Activity, error) {
+	var activities []timeline.Activity
+	for rows.Next() {
+		var a timeline.Activity
+		var activityType, timestamp string
+		var duration sql.NullInt64
+		var metadata sql.NullString
+		if err := rows.Scan(&a.ID, &activityType, &a.Source, &timestamp, &duration,
+			&a.Title, &a.Description, &a.URL, &metadata); err != nil {
+			return nil, err
+		}
+
+		a.Type = timeline.ActivityType(activityType)
+		// Parsed like encoding/json does, so times keep their zone
+		a.Timestamp, _ = time.Parse(time.RFC3339Nano, timestamp)
+		if duration.Valid {
+			d := time.Duration(duration.Int64)
+			a.Duration = &d
+		}
+		if metadata.Valid && metadata.String != "" {
+			if err := json.Unmarshal([]byte(metadata.String), &a.Metadata); err != nil {
+				return nil, fmt.Errorf("invalid metadata of activity %s: %w", a.ID, err)
+			}
+		}
+		activities = append(activities, a)
+	}
+	return activities, rows.Err()
+}
+
+// SetFreshness sets the policy HasDay uses to refetch recent days.
+func (c *Cache) SetFreshness(f Freshness) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.freshness = f
+}
+
+// SetOutputVersions sets the current output version of connectors whose
+// activities changed shape between releases. Entries stored with another
+// version are refetched by HasDay and stored with the current one.
+// Connectors that aren't listed are at version 0.
+func (c *Cache) SetOutputVersions(versions map[string]int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.outputVersions = versions
 }
 
 // Close closes the underlying database connection.
@@ -59,9 +184,11 @@ func (c *Cache) Close() error {
 	return c.db.Close()
 }
 
-// HasDay returns true if the cache has entries for all the given connectors
-// for the specified date. If connectors is empty, returns true if any entry
-// exists for that date.
+// HasDay returns true if the cache has up-to-date entries for all the given
+// connectors for the specified date. If connectors is empty, returns true if
+// any entry exists for that date. Entries are up to date unless the
+// freshness policy says they are stale or they were stored by another
+// output version of their connector.
 func (c *Cache) HasDay(date time.Time, connectors []string) bool {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -77,12 +204,16 @@ func (c *Cache) HasDay(date time.Time, connectors []string) bool {
 	}
 
 	for _, conn := range connectors {
-		var count int
+		var cachedAt int64
+		var outputVersion int
 		err := c.db.QueryRow(
-			"SELECT COUNT(*) FROM activity_cache WHERE date = ? AND connector = ?",
+			"SELECT cached_at, output_version FROM activity_cache WHERE date = ? AND connector = ?",
 			dateStr, conn,
-		).Scan(&count)
-		if err != nil || count == 0 {
+		).Scan(&cachedAt, &outputVersion)
+		if err != nil || outputVersion != c.outputVersions[conn] {
+			return false
+		}
+		if c.freshness.IsStale(date, conn, time.Unix(cachedAt, 0)) {
 			return false
 		}
 	}
@@ -97,7 +228,7 @@ func (c *Cache) LoadDay(date time.Time) ([]timeline.Activity, error) {
 	dateStr := date.Format("2006-01-02")
 
 	rows, err := c.db.Query(
-		"SELECT activities FROM activity_cache WHERE date = ?",
+		"SELECT "+activityColumns+" FROM activities WHERE date = ? ORDER BY row_id",
 		dateStr,
 	)
 	if err != nil {
@@ -105,21 +236,27 @@ func (c *Cache) LoadDay(date time.Time) ([]timeline.Activity, error) {
 	}
 	defer rows.Close()
 
-	var allActivities []timeline.Activity
-	for rows.Next() {
-		var activitiesJSON string
-		if err := rows.Scan(&activitiesJSON); err != nil {
-			continue
-		}
+	return scanActivities(rows)
+}
 
-		var activities []timeline.Activity
-		if err := json.Unmarshal([]byte(activitiesJSON), &activities); err != nil {
-			continue
-		}
-		allActivities = append(allActivities, activities...)
+// LoadConnectorDay retrieves the cached activities of one connector for the
+// specified date.
+func (c *Cache) LoadConnectorDay(date time.Time, connector string) ([]timeline.Activity, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	dateStr := date.Format("2006-01-02")
+
+	rows, err := c.db.Query(
+		"SELECT "+activityColumns+" FROM activities WHERE date = ? AND connector = ? ORDER BY row_id",
+		dateStr, connector,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cache: %w", err)
 	}
+	defer rows.Close()
 
-	return allActivities, rows.Err()
+	return scanActivities(rows)
 }
 
 // StoreDay stores activities for a specific date and connector, replacing
@@ -128,28 +265,70 @@ func (c *Cache) StoreDay(date time.Time, connector string, activities []timeline
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	dateStr := date.Format("2006-01-02")
-
-	activitiesJSON, err := json.Marshal(activities)
+	tx, err := c.db.Begin()
 	if err != nil {
-		return fmt.Errorf("failed to marshal activities: %w", err)
+		return fmt.Errorf("failed to store cache entry: %w", err)
+	}
+	defer tx.Rollback()
+
+	entry := Entry{
+		Date:          date.Format("2006-01-02"),
+		Connector:     connector,
+		CachedAt:      time.Now(),
+		OutputVersion: c.outputVersions[connector],
+		Activities:    activities,
+	}
+	if err := storeEntry(tx, entry); err != nil {
+		return err
 	}
 
-	_, err = c.db.Exec(
-		`INSERT INTO activity_cache (date, connector, activities, cached_at)
+	return tx.Commit()
+}
+
+// storeEntry replaces the entry and activities of a (date, connector) pair
+func storeEntry(tx *sql.Tx, entry Entry) error {
+	_, err := tx.Exec(
+		`INSERT INTO activity_cache (date, connector, cached_at, output_version)
 		 VALUES (?, ?, ?, ?)
 		 ON CONFLICT(date, connector) DO UPDATE SET
-		   activities = excluded.activities,
-		   cached_at   = excluded.cached_at`,
-		dateStr, connector, string(activitiesJSON), time.Now().Unix(),
+		   cached_at      = excluded.cached_at,
+		   output_version = excluded.output_version`,
+		entry.Date, entry.Connector, entry.CachedAt.Unix(), entry.OutputVersion,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to store cache entry: %w", err)
 	}
 
+	if _, err := tx.Exec("DELETE FROM activities WHERE date = ? AND connector = ?", entry.Date, entry.Connector); err != nil {
+		return fmt.Errorf("failed to store cache entry: %w", err)
+	}
+	if err := insertActivities(tx, entry.Date, entry.Connector, entry.Activities); err != nil {
+		return fmt.Errorf("failed to store activities: %w", err)
+	}
 	return nil
 }
 
+// deleteWhere removes the cache entries and activities matching a
+// condition on their date and connector columns, and returns the number of
+// entries removed
+func (c *Cache) deleteWhere(condition string, args ...interface{}) (int, error) {
+	tx, err := c.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("DELETE FROM activity_cache WHERE "+condition, args...)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec("DELETE FROM activities WHERE "+condition, args...); err != nil {
+		return 0, err
+	}
+	removed, _ := result.RowsAffected()
+	return int(removed), tx.Commit()
+}
+
 // ResetDay removes all cache entries for the specified date.
 func (c *Cache) ResetDay(date time.Time) error {
 	c.mu.Lock()
@@ -157,8 +336,7 @@ func (c *Cache) ResetDay(date time.Time) error {
 
 	dateStr := date.Format("2006-01-02")
 
-	_, err := c.db.Exec("DELETE FROM activity_cache WHERE date = ?", dateStr)
-	if err != nil {
+	if _, err := c.deleteWhere("date = ?", dateStr); err != nil {
 		return fmt.Errorf("failed to reset cache for %s: %w", dateStr, err)
 	}
 	return nil
@@ -169,7 +347,7 @@ func (c *Cache) ResetAll() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	_, err := c.db.Exec("DELETE FROM activity_cache")
+	_, err := c.deleteWhere("1 = 1")
 	return err
 }
 
@@ -181,14 +359,116 @@ func (c *Cache) ResetRange(start, end time.Time) error {
 	startStr := start.Format("2006-01-02")
 	endStr := end.Format("2006-01-02")
 
-	_, err := c.db.Exec(
-		"DELETE FROM activity_cache WHERE date >= ? AND date <= ?",
-		startStr, endStr,
-	)
+	_, err := c.deleteWhere("date >= ? AND date <= ?", startStr, endStr)
 	return err
 }
 
-// Stats returns basic statistics about the cache.
+// Reset removes the cache entries of the given connectors between start
+// and end (inclusive, zero = unbounded), leaving the other connectors
+// cached, or of all connectors when none are given. Connector names are
+// matched exactly. It returns the number of entries removed.
+func (c *Cache) Reset(connectors []string, start, end time.Time) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	condition, args := dateRange(start, end)
+	if len(connectors) > 0 {
+		condition += " AND connector IN (?" + strings.Repeat(", ?", len(connectors)-1) + ")"
+		for _, connector := range connectors {
+			args = append(args, connector)
+		}
+	}
+
+	removed, err := c.deleteWhere(condition, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reset cache: %w", err)
+	}
+	return removed, nil
+}
+
+// Prune removes the cache entries of days before the given date and
+// returns the number of entries removed.
+func (c *Cache) Prune(before time.Time) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed, err := c.deleteWhere("date < ?", before.Format("2006-01-02"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune cache: %w", err)
+	}
+	return removed, nil
+}
+
+// Vacuum compacts the full-text index and the database file, giving the
+// space of removed entries back to the file system.
+func (c *Cache) Vacuum() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := c.db.Exec("INSERT INTO activities_fts (activities_fts) VALUES ('optimize')"); err != nil {
+		return fmt.Errorf("failed to optimize search index: %w", err)
+	}
+	if _, err := c.db.Exec("VACUUM"); err != nil {
+		return fmt.Errorf("failed to vacuum cache: %w", err)
+	}
+	return nil
+}
+
+// Size returns the size of the database file in bytes.
+func (c *Cache) Size() (int64, error) {
+	info, err := os.Stat(c.path)
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// EntryInfo describes a cache entry without its activities
+type EntryInfo struct {
+	Date          string // YYYY-MM-DD
+	Connector     string
+	CachedAt      time.Time
+	OutputVersion int
+	Activities    int
+
+	// Stale is set when the freshness policy says the day is refetched on
+	// next use
+	Stale bool
+}
+
+// List returns the entries between from and to (inclusive, zero =
+// unbounded), by date and connector
+func (c *Cache) List(from, to time.Time) ([]EntryInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	condition, args := dateRange(from, to)
+	rows, err := c.db.Query(`SELECT date, connector, cached_at, output_version,
+		(SELECT COUNT(*) FROM activities a WHERE a.date = activity_cache.date AND a.connector = activity_cache.connector)
+		FROM activity_cache WHERE `+condition+` ORDER BY date, connector`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cache: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []EntryInfo
+	for rows.Next() {
+		var entry EntryInfo
+		var cachedAt int64
+		if err := rows.Scan(&entry.Date, &entry.Connector, &cachedAt, &entry.OutputVersion, &entry.Activities); err != nil {
+			return nil, err
+		}
+		entry.CachedAt = time.Unix(cachedAt, 0)
+		if date, err := time.ParseInLocation("2006-01-02", entry.Date, time.Local); err == nil {
+			entry.Stale = c.freshness.IsStale(date, entry.Connector, entry.CachedAt)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// Stats returns statistics about the cache, broken down by connector and
+// by month.
 func (c *Cache) Stats() (CacheStats, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -196,19 +476,75 @@ func (c *Cache) Stats() (CacheStats, error) {
 	var stats CacheStats
 
 	err := c.db.QueryRow(
-		"SELECT COUNT(*), COUNT(DISTINCT date), MIN(cached_at), MAX(cached_at) FROM activity_cache",
-	).Scan(&stats.TotalEntries, &stats.UniqueDates, &stats.OldestCachedAt, &stats.NewestCachedAt)
+		`SELECT COUNT(*), COUNT(DISTINCT date), COALESCE(MIN(cached_at), 0), COALESCE(MAX(cached_at), 0),
+		 COALESCE(MIN(date), ''), COALESCE(MAX(date), '') FROM activity_cache`,
+	).Scan(&stats.TotalEntries, &stats.UniqueDates, &stats.OldestCachedAt, &stats.NewestCachedAt,
+		&stats.FirstDate, &stats.LastDate)
 	if err != nil {
 		return stats, err
 	}
+	if err := c.db.QueryRow("SELECT COUNT(*) FROM activities").Scan(&stats.TotalActivities); err != nil {
+		return stats, err
+	}
+
+	if stats.Connectors, err = c.groupStats("c.connector"); err != nil {
+		return stats, err
+	}
+	if stats.Months, err = c.groupStats("substr(c.date, 1, 7)"); err != nil {
+		return stats, err
+	}
+
+	if info, err := os.Stat(c.path); err == nil {
+		stats.SizeBytes = info.Size()
+	}
 
 	return stats, nil
 }
 
+// groupStats counts the cached days and activities per value of key, an
+// expression on the activity_cache columns aliased as c
+func (c *Cache) groupStats(key string) ([]GroupStats, error) {
+	rows, err := c.db.Query(`SELECT ` + key + ` AS name, COUNT(DISTINCT c.date), COALESCE(SUM(a.count), 0), MIN(c.date), MAX(c.date)
+		FROM activity_cache c
+		LEFT JOIN (SELECT date, connector, COUNT(*) AS count FROM activities GROUP BY date, connector) a
+		  ON a.date = c.date AND a.connector = c.connector
+		GROUP BY name ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groups []GroupStats
+	for rows.Next() {
+		var g GroupStats
+		if err := rows.Scan(&g.Name, &g.Days, &g.Activities, &g.FirstDate, &g.LastDate); err != nil {
+			return nil, err
+		}
+		groups = append(groups, g)
+	}
+	return groups, rows.Err()
+}
+
 // CacheStats contains statistics about the cache.
 type CacheStats struct {
-	TotalEntries    int   // total (date, connector) pairs
-	UniqueDates     int   // number of distinct dates cached
-	OldestCachedAt  int64 // unix timestamp of oldest cache entry
-	NewestCachedAt  int64 // unix timestamp of newest cache entry
-}
\ No newline at end of file
+	TotalEntries    int    // total (date, connector) pairs
+	TotalActivities int    // number of cached activities
+	UniqueDates     int    // number of distinct dates cached
+	FirstDate       string // earliest cached date (YYYY-MM-DD)
+	LastDate        string // latest cached date (YYYY-MM-DD)
+	OldestCachedAt  int64  // unix timestamp of oldest cache entry
+	NewestCachedAt  int64  // unix timestamp of newest cache entry
+	SizeBytes       int64  // size of the database file
+
+	Connectors []GroupStats // per connector, by name
+	Months     []GroupStats // per month (YYYY-MM), in order
+}
+
+// GroupStats counts what the cache holds for one connector or month.
+type GroupStats struct {
+	Name       string
+	Days       int // distinct dates cached
+	Activities int
+	FirstDate  string
+	LastDate   string
+}
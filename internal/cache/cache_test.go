@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"database/sql"
 	"path/filepath"
 	"testing"
 	"time"
@@ -171,6 +172,14 @@ func TestCache_MultipleConnectorsSameDay(t *testing.T) {
 	if len(loaded) != 2 {
 		t.Fatalf("Expected 2 activities from 2 connectors, got %d", len(loaded))
 	}
+
+	loaded, err := c.LoadConnectorDay(date, "calendar")
+	if err != nil {
+		t.Fatalf("LoadConnectorDay failed: %v", err)
+	}
+	if len(loaded) != 1 || loaded[0].Title != "Cal Activity" {
+		t.Errorf("Expected only the calendar activity, got %v", loaded)
+	}
 }
 
 func TestCache_Stats(t *testing.T) {
@@ -191,4 +200,309 @@ func TestCache_Stats(t *testing.T) {
 	if stats.UniqueDates != 1 {
 		t.Errorf("Expected 1 unique date, got %d", stats.UniqueDates)
 	}
-}
\ No newline at end of file
+}
+
+func TestCache_StatsBreakdown(t *testing.T) {
+	c := newTestCache(t)
+	jan15 := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	feb1 := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	if stats, err := c.Stats(); err != nil || stats.TotalEntries != 0 {
+		t.Fatalf("Expected empty stats for a new cache, got %+v, %v", stats, err)
+	}
+
+	c.StoreDay(jan15, "github", []timeline.Activity{
+		{ID: "1", Title: "A", Source: "github", Timestamp: jan15},
+		{ID: "2", Title: "B", Source: "github", Timestamp: jan15},
+	})
+	c.StoreDay(jan15, "calendar", nil)
+	c.StoreDay(feb1, "github", []timeline.Activity{{ID: "3", Title: "C", Source: "github", Timestamp: feb1}})
+
+	stats, err := c.Stats()
+	if err != nil {
+		t.Fatalf("Stats failed: %v", err)
+	}
+	if stats.TotalActivities != 3 || stats.FirstDate != "2024-01-15" || stats.LastDate != "2024-02-01" {
+		t.Errorf("Unexpected totals: %+v", stats)
+	}
+	if stats.SizeBytes == 0 {
+		t.Error("Expected the database size")
+	}
+
+	expectedConnectors := []GroupStats{
+		{Name: "calendar", Days: 1, Activities: 0, FirstDate: "2024-01-15", LastDate: "2024-01-15"},
+		{Name: "github", Days: 2, Activities: 3, FirstDate: "2024-01-15", LastDate: "2024-02-01"},
+	}
+	if len(stats.Connectors) != len(expectedConnectors) {
+		t.Fatalf("Expected %d connectors, got %+v", len(expectedConnectors), stats.Connectors)
+	}
+	for i, expected := range expectedConnectors {
+		if stats.Connectors[i] != expected {
+			t.Errorf("Expected %+v, got %+v", expected, stats.Connectors[i])
+		}
+	}
+
+	if len(stats.Months) != 2 || stats.Months[0].Name != "2024-01" || stats.Months[0].Days != 1 || stats.Months[0].Activities != 2 {
+		t.Errorf("Unexpected months: %+v", stats.Months)
+	}
+}
+
+func TestCache_Reset(t *testing.T) {
+	c := newTestCache(t)
+	date1 := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	date2 := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC)
+	date3 := time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC)
+
+	for _, date := range []time.Time{date1, date2, date3} {
+		c.StoreDay(date, "gitlab", []timeline.Activity{{ID: "1", Title: "A", Source: "gitlab", Timestamp: date}})
+		c.StoreDay(date, "gitlab:client-a", nil)
+		c.StoreDay(date, "github", []timeline.Activity{{ID: "2", Title: "B", Source: "github", Timestamp: date}})
+	}
+
+	removed, err := c.Reset([]string{"gitlab"}, date1, date2)
+	if err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("Expected 2 entries removed, got %d", removed)
+	}
+	if c.HasDay(date1, []string{"gitlab"}) || !c.HasDay(date1, []string{"gitlab:client-a"}) {
+		t.Error("Expected only gitlab itself cleared, not its instances")
+	}
+
+	// Instances are removed when named
+	removed, _ = c.Reset([]string{"gitlab", "gitlab:client-a"}, date1, date2)
+	if removed != 2 || c.HasDay(date2, []string{"gitlab:client-a"}) {
+		t.Errorf("Expected the instance cleared in the range, got %d removed", removed)
+	}
+	if !c.HasDay(date1, []string{"github"}) {
+		t.Error("Expected other connectors to stay cached")
+	}
+	if !c.HasDay(date3, []string{"gitlab", "gitlab:client-a"}) {
+		t.Error("Expected days outside the range to stay cached")
+	}
+	if loaded, _ := c.LoadDay(date1); len(loaded) != 1 || loaded[0].Source != "github" {
+		t.Errorf("Expected only the github activity left, got %v", loaded)
+	}
+
+	// Names are not patterns
+	if removed, _ = c.Reset([]string{"gitlab*", "git?ab", "[g]itlab"}, time.Time{}, time.Time{}); removed != 0 {
+		t.Errorf("Expected no entries removed for pattern-like names, got %d", removed)
+	}
+
+	// Without a range, every day of the connector goes
+	removed, _ = c.Reset([]string{"github"}, time.Time{}, time.Time{})
+	if removed != 3 || c.HasDay(date3, []string{"github"}) {
+		t.Errorf("Expected all github days removed, got %d", removed)
+	}
+
+	// Without a connector, every connector goes
+	removed, _ = c.Reset(nil, date3, time.Time{})
+	if removed != 2 || c.HasDay(date3, nil) {
+		t.Errorf("Expected the last day removed, got %d", removed)
+	}
+}
+
+func TestCache_Prune(t *testing.T) {
+	c := newTestCache(t)
+	old := time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC)
+	recent := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	c.StoreDay(old, "github", []timeline.Activity{{ID: "1", Title: "A", Source: "github", Timestamp: old}})
+	c.StoreDay(old, "calendar", nil)
+	c.StoreDay(recent, "github", []timeline.Activity{{ID: "2", Title: "B", Source: "github", Timestamp: recent}})
+
+	removed, err := c.Prune(recent)
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("Expected 2 entries removed, got %d", removed)
+	}
+	if c.HasDay(old, nil) || !c.HasDay(recent, nil) {
+		t.Error("Expected only the days before the cutoff removed")
+	}
+
+	if err := c.Vacuum(); err != nil {
+		t.Fatalf("Vacuum failed: %v", err)
+	}
+	if results, _ := c.Search("b", SearchOptions{}); len(results) != 1 {
+		t.Errorf("Expected the search index to survive a vacuum, got %v", results)
+	}
+}
+
+func TestCache_List(t *testing.T) {
+	c := newTestCache(t)
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	c.StoreDay(date, "github", []timeline.Activity{
+		{ID: "1", Title: "A", Source: "github", Timestamp: date},
+		{ID: "2", Title: "B", Source: "github", Timestamp: date},
+	})
+	c.StoreDay(date, "calendar", nil)
+	c.StoreDay(date.AddDate(0, 0, 1), "github", nil)
+
+	entries, err := c.List(date, date)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %+v", entries)
+	}
+	if entries[0].Connector != "calendar" || entries[0].Activities != 0 {
+		t.Errorf("Unexpected first entry: %+v", entries[0])
+	}
+	if entries[1].Connector != "github" || entries[1].Activities != 2 || entries[1].CachedAt.IsZero() {
+		t.Errorf("Unexpected second entry: %+v", entries[1])
+	}
+	if entries[1].Stale {
+		t.Error("Expected entries to be final without a freshness policy")
+	}
+}
+func TestFreshness_IsStale(t *testing.T) {
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	cachedAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.Local)
+	at := func(day, hour int) func() time.Time {
+		return func() time.Time { return time.Date(2024, 1, day, hour, 0, 0, 0, time.Local) }
+	}
+
+	f := Freshness{
+		HorizonDays:  2,
+		TTL:          time.Hour,
+		ConnectorTTL: map[string]time.Duration{"github": 4 * time.Hour, "notes": 0},
+		Now:          at(15, 12),
+	}
+
+	if !f.IsStale(date, "calendar", cachedAt) {
+		t.Error("Expected an entry cached this morning to be stale after the TTL")
+	}
+	if f.IsStale(date, "github", cachedAt) {
+		t.Error("Expected the github TTL to override the default")
+	}
+	if f.IsStale(date, "notes", cachedAt) {
+		t.Error("Expected a TTL of 0 to never refetch")
+	}
+
+	// A day cached while it could still change stays stale after it settles,
+	// but a refetch after that is final
+	f.Now = at(25, 12)
+	if !f.IsStale(date, "github", cachedAt) {
+		t.Error("Expected an entry cached before the day settled to be refetched")
+	}
+	if f.IsStale(date, "github", time.Date(2024, 1, 17, 1, 0, 0, 0, time.Local)) {
+		t.Error("Expected an entry cached after the day settled to be final")
+	}
+
+	if (Freshness{}).IsStale(date, "calendar", cachedAt) {
+		t.Error("Expected the zero Freshness to treat every entry as final")
+	}
+}
+
+func TestCache_HasDayFreshness(t *testing.T) {
+	c := newTestCache(t)
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	lastMonth := today.AddDate(0, -1, 0)
+
+	c.StoreDay(today, "calendar", nil)
+	c.StoreDay(lastMonth, "calendar", nil)
+
+	c.SetFreshness(Freshness{
+		HorizonDays: 2,
+		TTL:         time.Hour,
+		Now:         func() time.Time { return now.Add(2 * time.Hour) },
+	})
+
+	if c.HasDay(today, []string{"calendar"}) {
+		t.Error("Expected today's entry to be stale two hours later")
+	}
+	if !c.HasDay(lastMonth, []string{"calendar"}) {
+		t.Error("Expected a settled day to stay cached")
+	}
+
+	c.SetFreshness(Freshness{
+		HorizonDays:  2,
+		TTL:          time.Hour,
+		ConnectorTTL: map[string]time.Duration{"calendar": 3 * time.Hour},
+		Now:          func() time.Time { return now.Add(2 * time.Hour) },
+	})
+	if !c.HasDay(today, []string{"calendar"}) {
+		t.Error("Expected the connector TTL to keep today's entry fresh")
+	}
+}
+
+func TestCache_MigrateBlobs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "old_cache.db")
+
+	// The schema of older versions, with one JSON blob per day and connector
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec(`CREATE TABLE activity_cache (
+		date TEXT NOT NULL, connector TEXT NOT NULL, activities TEXT NOT NULL,
+		cached_at INTEGER NOT NULL, PRIMARY KEY (date, connector));
+	INSERT INTO activity_cache VALUES
+		('2024-01-15', 'github', '[{"id":"1","type":"git_commit","title":"Fix billing","description":"","timestamp":"2024-01-15T10:30:00+02:00","source":"github","duration":1800000000000,"metadata":{"sha":"abc"}}]', 1705312200),
+		('2024-01-15', 'calendar', '[]', 1705312200),
+		('2024-01-16', 'github', 'not json', 1705312200)`)
+	db.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := New(path)
+	if err != nil {
+		t.Fatalf("Failed to open old cache: %v", err)
+	}
+	defer c.Close()
+
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	if !c.HasDay(date, []string{"github", "calendar"}) {
+		t.Error("Expected the migrated day to stay cached")
+	}
+	if c.HasDay(date.AddDate(0, 0, 1), []string{"github"}) {
+		t.Error("Expected an unreadable entry to be dropped")
+	}
+
+	loaded, err := c.LoadDay(date)
+	if err != nil || len(loaded) != 1 {
+		t.Fatalf("Expected 1 migrated activity, got %d (%v)", len(loaded), err)
+	}
+	a := loaded[0]
+	if a.Title != "Fix billing" || a.Type != timeline.ActivityTypeGitCommit || a.Metadata["sha"] != "abc" {
+		t.Errorf("Unexpected migrated activity: %+v", a)
+	}
+	if a.Timestamp.Format(time.RFC3339) != "2024-01-15T10:30:00+02:00" {
+		t.Errorf("Expected the timestamp to keep its offset, got %s", a.Timestamp.Format(time.RFC3339))
+	}
+	if a.Duration == nil || *a.Duration != 30*time.Minute {
+		t.Errorf("Expected a 30 minute duration, got %v", a.Duration)
+	}
+
+	if results, _ := c.Search("billing", SearchOptions{}); len(results) != 1 {
+		t.Errorf("Expected migrated activities to be searchable, got %d results", len(results))
+	}
+}
+
+func TestCache_OutputVersions(t *testing.T) {
+	c := newTestCache(t)
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	c.StoreDay(date, "youtrack", nil)
+	c.StoreDay(date, "github", nil)
+
+	// youtrack changed how it builds activities
+	c.SetOutputVersions(map[string]int{"youtrack": 2})
+	if c.HasDay(date, []string{"youtrack"}) {
+		t.Error("Expected the entry of the old youtrack version to be stale")
+	}
+	if !c.HasDay(date, []string{"github"}) {
+		t.Error("Expected other connectors to stay cached")
+	}
+
+	c.StoreDay(date, "youtrack", nil)
+	if !c.HasDay(date, []string{"youtrack", "github"}) {
+		t.Error("Expected the refetched entry to be current")
+	}
+}
@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"fmt"
+	"time"
+)
+
+// exportDate returns the date column for the local day of day
+func exportDate(day time.Time) string {
+	return day.In(time.Local).Format("2006-01-02")
+}
+
+// ExportedDuration returns how much time was exported to the remote project
+// of sink on the local day of day.
+func (c *Cache) ExportedDuration(sink, project string, day time.Time) (time.Duration, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var seconds int64
+	err := c.db.QueryRow(
+		"SELECT COALESCE(SUM(seconds), 0) FROM exports WHERE sink = ? AND project = ? AND date = ?",
+		sink, project, exportDate(day),
+	).Scan(&seconds)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query exports: %w", err)
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// AddExported records that d more was exported to the remote project of
+// sink on the local day of day.
+func (c *Cache) AddExported(sink, project string, day time.Time, d time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, err := c.db.Exec(
+		`INSERT INTO exports (sink, project, date, seconds, exported_at) VALUES (?, ?, ?, ?, ?)
+		 ON CONFLICT (sink, project, date) DO UPDATE SET
+		   seconds = seconds + excluded.seconds,
+		   exported_at = excluded.exported_at`,
+		sink, project, exportDate(day), int64(d/time.Second), time.Now().Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record export: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package cache
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/arkeo/arkeo/internal/timeline"
+)
+
+// migration changes the schema from the previous version to version
+type migration struct {
+	version     int
+	description string
+	apply       func(tx *sql.Tx) error
+}
+
+// migrations are applied in order to bring a cache up to date. Never change
+// a released migration; add a new one instead. Caches created before the
+// schema_version table existed are at version 0, so the first migrations
+// must also work on tables they find already there.
+var migrations = []migration{
+	{1, "activity cache with one JSON blob per day and connector", func(tx *sql.Tx) error {
+		_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS activity_cache (
+			date        TEXT    NOT NULL,
+			connector   TEXT    NOT NULL,
+			activities  TEXT    NOT NULL,
+			cached_at   INTEGER NOT NULL,
+			PRIMARY KEY (date, connector)
+		)`)
+		return err
+	}},
+	{2, "one row per activity with a full-text index", func(tx *sql.Tx) error {
+		if _, err := tx.Exec(activitiesSchema); err != nil {
+			return err
+		}
+		return moveBlobs(tx)
+	}},
+	{3, "connector output version of each entry", func(tx *sql.Tx) error {
+		_, err := tx.Exec("ALTER TABLE activity_cache ADD COLUMN output_version INTEGER NOT NULL DEFAULT 0")
+		return err
+	}},
+	{4, "time exported to time tracking tools per day", func(tx *sql.Tx) error {
+		_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS exports (
+			sink        TEXT    NOT NULL,
+			project     TEXT    NOT NULL,
+			date        TEXT    NOT NULL,
+			seconds     INTEGER NOT NULL,
+			exported_at INTEGER NOT NULL,
+			PRIMARY KEY (sink, project, date)
+		)`)
+		return err
+	}},
+}
+
+// activitiesSchema stores the activities one row each, with a full-text
+// index on their titles and descriptions kept in sync by triggers
+const activitiesSchema = `
+CREATE TABLE IF NOT EXISTS activities (
+	row_id      INTEGER PRIMARY KEY,
+	date        TEXT    NOT NULL,
+	connector   TEXT    NOT NULL,
+	id          TEXT    NOT NULL,
+	type        TEXT    NOT NULL,
+	source      TEXT    NOT NULL,
+	timestamp   TEXT    NOT NULL,
+	unix_time   INTEGER NOT NULL,
+	duration    INTEGER,
+	title       TEXT    NOT NULL,
+	description TEXT    NOT NULL,
+	url         TEXT    NOT NULL,
+	metadata    TEXT
+);
+CREATE INDEX IF NOT EXISTS activities_day ON activities (date, connector);
+CREATE INDEX IF NOT EXISTS activities_time ON activities (unix_time);
+
+CREATE VIRTUAL TABLE IF NOT EXISTS activities_fts USING fts5(
+	title, description, content='activities', content_rowid='row_id'
+);
+CREATE TRIGGER IF NOT EXISTS activities_fts_insert AFTER INSERT ON activities BEGIN
+	INSERT INTO activities_fts (rowid, title, description) VALUES (new.row_id, new.title, new.description);
+END;
+CREATE TRIGGER IF NOT EXISTS activities_fts_delete AFTER DELETE ON activities BEGIN
+	INSERT INTO activities_fts (activities_fts, rowid, title, description) VALUES ('delete', old.row_id, old.title, old.description);
+END;
+CREATE TRIGGER IF NOT EXISTS activities_fts_update AFTER UPDATE ON activities BEGIN
+	INSERT INTO activities_fts (activities_fts, rowid, title, description) VALUES ('delete', old.row_id, old.title, old.description);
+	INSERT INTO activities_fts (rowid, title, description) VALUES (new.row_id, new.title, new.description);
+END;
+`
+
+// SchemaVersion returns the schema version of the cache database
+func (c *Cache) SchemaVersion() (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return schemaVersion(c.db)
+}
+
+// schemaVersion returns the version recorded in schema_version, or 0 for a
+// new cache or one created before versioning
+func schemaVersion(db *sql.DB) (int, error) {
+	var version int
+	err := db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_version").Scan(&version)
+	return version, err
+}
+
+// migrate applies the migrations the cache hasn't seen yet, each in its own
+// transaction together with the version bump
+func migrate(db *sql.DB) error {
+	if _, err := db.Exec("CREATE TABLE IF NOT EXISTS schema_version (version INTEGER NOT NULL)"); err != nil {
+		return err
+	}
+
+	current, err := schemaVersion(db)
+	if err != nil {
+		return err
+	}
+	latest := migrations[len(migrations)-1].version
+	if current > latest {
+		return fmt.Errorf("cache schema version %d is newer than this arkeo supports (%d)", current, latest)
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		if err := applyMigration(db, m); err != nil {
+			return fmt.Errorf("migration %d (%s): %w", m.version, m.description, err)
+		}
+	}
+	return nil
+}
+
+// applyMigration runs one migration and records its version
+func applyMigration(db *sql.DB, m migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Another arkeo process may have applied it in the meantime
+	var current int
+	if err := tx.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_version").Scan(&current); err != nil {
+		return err
+	}
+	if current >= m.version {
+		return nil
+	}
+
+	if err := m.apply(tx); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM schema_version"); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("INSERT INTO schema_version (version) VALUES (?)", m.version); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// moveBlobs moves the activities kept as one JSON blob per (date,
+// connector) into the activities table and drops the blob column
+func moveBlobs(tx *sql.Tx) error {
+	var hasBlobs int
+	err := tx.QueryRow(
+		"SELECT COUNT(*) FROM pragma_table_info('activity_cache') WHERE name = 'activities'",
+	).Scan(&hasBlobs)
+	if err != nil || hasBlobs == 0 {
+		return err
+	}
+
+	type blob struct {
+		date, connector, activities string
+	}
+	rows, err := tx.Query("SELECT date, connector, activities FROM activity_cache")
+	if err != nil {
+		return err
+	}
+	var blobs []blob
+	for rows.Next() {
+		var b blob
+		if err := rows.Scan(&b.date, &b.connector, &b.activities); err != nil {
+			rows.Close()
+			return err
+		}
+		blobs = append(blobs, b)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, b := range blobs {
+		var activities []timeline.Activity
+		if err := json.Unmarshal([]byte(b.activities), &activities); err != nil {
+			// Forget the day so that it is fetched again
+			if _, err := tx.Exec("DELETE FROM activity_cache WHERE date = ? AND connector = ?", b.date, b.connector); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := insertActivities(tx, b.date, b.connector, activities); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec("ALTER TABLE activity_cache DROP COLUMN activities")
+	return err
+}
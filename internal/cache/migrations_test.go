@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arkeo/arkeo/internal/timeline"
+)
+
+func TestMigrate_NewCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.db")
+	c, err := New(path)
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+
+	latest := migrations[len(migrations)-1].version
+	if version, err := c.SchemaVersion(); err != nil || version != latest {
+		t.Errorf("Expected schema version %d, got %d (%v)", latest, version, err)
+	}
+
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	c.StoreDay(date, "github", []timeline.Activity{{ID: "1", Title: "Commit", Source: "github", Timestamp: date}})
+	c.Close()
+
+	// Opening again applies nothing and keeps the data
+	c, err = New(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen cache: %v", err)
+	}
+	defer c.Close()
+	if loaded, _ := c.LoadDay(date); len(loaded) != 1 {
+		t.Errorf("Expected 1 activity after reopening, got %d", len(loaded))
+	}
+}
+
+func TestMigrate_UnversionedCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.db")
+
+	// A cache with activities rows but no schema_version table yet
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec(`CREATE TABLE activity_cache (
+		date TEXT NOT NULL, connector TEXT NOT NULL, cached_at INTEGER NOT NULL,
+		PRIMARY KEY (date, connector));
+	INSERT INTO activity_cache VALUES ('2024-01-15', 'github', 1705312200);` + activitiesSchema + `
+	INSERT INTO activities (date, connector, id, type, source, timestamp, unix_time, title, description, url)
+		VALUES ('2024-01-15', 'github', '1', 'git_commit', 'github', '2024-01-15T10:30:00Z', 1705314600, 'Fix billing', '', '')`)
+	db.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := New(path)
+	if err != nil {
+		t.Fatalf("Failed to migrate cache: %v", err)
+	}
+	defer c.Close()
+
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	if !c.HasDay(date, []string{"github"}) {
+		t.Error("Expected the existing entry to stay cached")
+	}
+	if results, _ := c.Search("billing", SearchOptions{}); len(results) != 1 {
+		t.Errorf("Expected the existing activity to stay searchable, got %d results", len(results))
+	}
+}
+
+func TestMigrate_NewerVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec("CREATE TABLE schema_version (version INTEGER NOT NULL); INSERT INTO schema_version VALUES (999)")
+	db.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := New(path); err == nil || !strings.Contains(err.Error(), "newer") {
+		t.Errorf("Expected an error for a cache from a newer version, got %v", err)
+	}
+}
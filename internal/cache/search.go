@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/arkeo/arkeo/internal/timeline"
+)
+
+// SearchOptions narrows down a search of the cached activities
+type SearchOptions struct {
+	// First and last day to search, inclusive (zero = unbounded)
+	Since time.Time
+	Until time.Time
+
+	// Connectors to search. A connector type also matches its named
+	// instances: gitlab matches gitlab:client-a.
+	Sources []string
+
+	// Most activities returned (0 = no limit)
+	Limit int
+}
+
+// Search returns the cached activities whose title or description contain
+// every word of query, most recent first. Words match as prefixes, so
+// "bill" finds "billing"; "quoted phrases" match as a whole.
+func (c *Cache) Search(query string, opts SearchOptions) ([]timeline.Activity, error) {
+	match := ftsQuery(query)
+	if match == "" {
+		return nil, fmt.Errorf("empty search query")
+	}
+
+	conditions := []string{"activities_fts MATCH ?"}
+	args := []interface{}{match}
+
+	if !opts.Since.IsZero() {
+		conditions = append(conditions, "a.date >= ?")
+		args = append(args, opts.Since.Format("2006-01-02"))
+	}
+	if !opts.Until.IsZero() {
+		conditions = append(conditions, "a.date <= ?")
+		args = append(args, opts.Until.Format("2006-01-02"))
+	}
+	if len(opts.Sources) > 0 {
+		var sources []string
+		for _, source := range opts.Sources {
+			source = strings.ToLower(strings.TrimSpace(source))
+			sources = append(sources, "a.connector = ? OR a.connector GLOB ?")
+			args = append(args, source, source+":*")
+		}
+		conditions = append(conditions, "("+strings.Join(sources, " OR ")+")")
+	}
+
+	sqlQuery := "SELECT " + prefixColumns("a.", activityColumns) + `
+		FROM activities_fts
+		JOIN activities a ON a.row_id = activities_fts.rowid
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY a.unix_time DESC, a.row_id DESC`
+	if opts.Limit > 0 {
+		sqlQuery += " LIMIT ?"
+		args = append(args, opts.Limit)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	rows, err := c.db.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+	defer rows.Close()
+
+	return scanActivities(rows)
+}
+
+// ftsQuery turns what the user typed into an FTS5 query: every word or
+// quoted phrase is quoted, so punctuation and FTS5 operators in it have no
+// special meaning, and matches as a prefix
+func ftsQuery(query string) string {
+	var terms []string
+	for i, part := range strings.Split(query, `"`) {
+		var words []string
+		if i%2 == 1 {
+			// Inside quotes
+			words = []string{strings.TrimSpace(part)}
+		} else {
+			words = strings.Fields(part)
+		}
+		for _, word := range words {
+			if word != "" {
+				terms = append(terms, `"`+word+`"*`)
+			}
+		}
+	}
+	return strings.Join(terms, " ")
+}
+
+// prefixColumns qualifies a comma-separated list of columns with a table alias
+func prefixColumns(prefix, columns string) string {
+	parts := strings.Split(columns, ", ")
+	for i, column := range parts {
+		parts[i] = prefix + column
+	}
+	return strings.Join(parts, ", ")
+}
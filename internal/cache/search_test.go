@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arkeo/arkeo/internal/timeline"
+)
+
+func TestFtsQuery(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{"billing", `"billing"*`},
+		{"billing  service", `"billing"* "service"*`},
+		{`"billing service" deploy`, `"billing service"* "deploy"*`},
+		{"billing-service OR NOT", `"billing-service"* "OR"* "NOT"*`},
+		{"   ", ""},
+	}
+
+	for _, tt := range tests {
+		if got := ftsQuery(tt.query); got != tt.expected {
+			t.Errorf("ftsQuery(%q) = %q, expected %q", tt.query, got, tt.expected)
+		}
+	}
+}
+
+func TestCache_Search(t *testing.T) {
+	c := newTestCache(t)
+	jan15 := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	feb1 := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	c.StoreDay(jan15, "gitlab", []timeline.Activity{
+		{ID: "1", Title: "Fix invoice rounding", Description: "billing-service", Source: "gitlab", Timestamp: jan15.Add(9 * time.Hour)},
+		{ID: "2", Title: "Update README", Source: "gitlab", Timestamp: jan15.Add(10 * time.Hour)},
+	})
+	c.StoreDay(feb1, "gitlab:client-a", []timeline.Activity{
+		{ID: "3", Title: "Billing service retries", Source: "gitlab:client-a", Timestamp: feb1.Add(11 * time.Hour),
+			Metadata: map[string]string{"repository": "acme/billing"}},
+	})
+	c.StoreDay(feb1, "calendar", []timeline.Activity{
+		{ID: "4", Title: "Billing sync", Source: "calendar", Timestamp: feb1.Add(14 * time.Hour)},
+	})
+
+	results, err := c.Search("billing", SearchOptions{})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+	if results[0].ID != "4" || results[1].ID != "3" || results[2].ID != "1" {
+		t.Errorf("Expected the most recent first, got %s, %s, %s", results[0].ID, results[1].ID, results[2].ID)
+	}
+	if results[1].Metadata["repository"] != "acme/billing" {
+		t.Errorf("Expected metadata to be loaded, got %v", results[1].Metadata)
+	}
+
+	results, _ = c.Search("bill serv", SearchOptions{Sources: []string{"GitLab"}})
+	if len(results) != 2 {
+		t.Errorf("Expected gitlab and its instance to match, got %d results", len(results))
+	}
+
+	results, _ = c.Search("billing", SearchOptions{Since: feb1, Sources: []string{"gitlab"}})
+	if len(results) != 1 || results[0].ID != "3" {
+		t.Errorf("Expected only the February gitlab activity, got %v", results)
+	}
+
+	results, _ = c.Search("billing", SearchOptions{Limit: 1})
+	if len(results) != 1 {
+		t.Errorf("Expected the limit to apply, got %d results", len(results))
+	}
+
+	// Replaced and reset days disappear from the index
+	c.StoreDay(jan15, "gitlab", nil)
+	c.ResetDay(feb1)
+	results, _ = c.Search("billing", SearchOptions{})
+	if len(results) != 0 {
+		t.Errorf("Expected no results after replacing and resetting, got %d", len(results))
+	}
+
+	if _, err := c.Search(" ", SearchOptions{}); err == nil {
+		t.Error("Expected an error for an empty query")
+	}
+}
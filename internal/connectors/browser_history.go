@@ -142,6 +142,28 @@ func (b *BrowserHistoryConnector) GetActivities(ctx context.Context, date time.T
 		log.Printf("BrowserHistory Debug: Fetching activities for date %s", date.Format("2006-01-02"))
 	}
 
+	// Normalize to local day (consistent with H3 fix).
+	localDate := date.In(time.Local)
+	startOfDay := time.Date(localDate.Year(), localDate.Month(), localDate.Day(), 0, 0, 0, 0, localDate.Location())
+	endOfDay := startOfDay.Add(24 * time.Hour)
+
+	return b.fetchActivities(ctx, startOfDay, endOfDay)
+}
+
+// GetActivitiesRange retrieves browser history activities for every day from
+// start to end (inclusive), opening each history database only once.
+func (b *BrowserHistoryConnector) GetActivitiesRange(ctx context.Context, start, end time.Time) ([]timeline.Activity, error) {
+	if b.IsDebugMode() {
+		log.Printf("BrowserHistory Debug: Fetching activities from %s to %s", start.Format("2006-01-02"), end.Format("2006-01-02"))
+	}
+
+	from, to := localDayRange(start, end)
+	return b.fetchActivities(ctx, from, to)
+}
+
+// fetchActivities queries all detected browser databases for visits in
+// [start, end) and groups them into activities.
+func (b *BrowserHistoryConnector) fetchActivities(ctx context.Context, start, end time.Time) ([]timeline.Activity, error) {
 	browsers := b.getConfigBrowsers()
 	paths := detectBrowserDBPaths(browsers)
 	if len(paths) == 0 {
@@ -160,11 +182,6 @@ func (b *BrowserHistoryConnector) GetActivities(ctx context.Context, date time.T
 	}
 	minVisits := b.GetConfigInt("min_visits")
 
-	// Normalize to local day (consistent with H3 fix).
-	localDate := date.In(time.Local)
-	startOfDay := time.Date(localDate.Year(), localDate.Month(), localDate.Day(), 0, 0, 0, 0, localDate.Location())
-	endOfDay := startOfDay.Add(24 * time.Hour)
-
 	var allVisits []browserVisit
 
 	for _, p := range paths {
@@ -172,7 +189,7 @@ func (b *BrowserHistoryConnector) GetActivities(ctx context.Context, date time.T
 			log.Printf("BrowserHistory Debug: Scanning %s database at %s", p.browser, p.dbPath)
 		}
 
-		visits, err := b.queryBrowserHistory(ctx, p, startOfDay, endOfDay)
+		visits, err := b.queryBrowserHistory(ctx, p, start, end)
 		if err != nil {
 			if b.IsDebugMode() {
 				log.Printf("BrowserHistory Debug: Failed to query %s: %v", p.browser, err)
@@ -202,8 +219,12 @@ func (b *BrowserHistoryConnector) GetActivities(ctx context.Context, date time.T
 		return filtered[i].timestamp.Before(filtered[j].timestamp)
 	})
 
-	// Group visits by domain within the time window.
-	activities := groupVisitsByDomain(filtered, groupWindow, minVisits)
+	// Group visits by domain within the time window. Groups never span
+	// midnight so a range fetch yields the same activities as single days.
+	var activities []timeline.Activity
+	for _, dayVisits := range splitVisitsByDay(filtered) {
+		activities = append(activities, groupVisitsByDomain(dayVisits, groupWindow, minVisits)...)
+	}
 
 	if b.IsDebugMode() {
 		log.Printf("BrowserHistory Debug: Total activities after grouping: %d", len(activities))
@@ -644,6 +665,20 @@ func normalizeDomain(hostname string) string {
 	return strings.Join(parts[len(parts)-2:], ".")
 }
 
+// splitVisitsByDay splits time-sorted visits into runs sharing the same local
+// calendar day.
+func splitVisitsByDay(visits []browserVisit) [][]browserVisit {
+	var days [][]browserVisit
+	start := 0
+	for i := 1; i <= len(visits); i++ {
+		if i == len(visits) || visits[i].timestamp.In(time.Local).Format("2006-01-02") != visits[start].timestamp.In(time.Local).Format("2006-01-02") {
+			days = append(days, visits[start:i])
+			start = i
+		}
+	}
+	return days
+}
+
 // groupVisitsByDomain groups visits to the same domain within the given time
 // window into a single timeline activity.
 func groupVisitsByDomain(visits []browserVisit, window time.Duration, minVisits int) []timeline.Activity {
@@ -0,0 +1,161 @@
+package connectors
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// calendarQueryTemplate is a CalDAV calendar-query REPORT (RFC 4791 section
+// 7.8) asking for the events overlapping a UTC time range
+const calendarQueryTemplate = `<?xml version="1.0" encoding="utf-8"?>
+<C:calendar-query xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop>
+    <C:calendar-data/>
+  </D:prop>
+  <C:filter>
+    <C:comp-filter name="VCALENDAR">
+      <C:comp-filter name="VEVENT">
+        <C:time-range start="%s" end="%s"/>
+      </C:comp-filter>
+    </C:comp-filter>
+  </C:filter>
+</C:calendar-query>`
+
+// resourceTypeQuery is a PROPFIND body asking for the type of a collection
+const resourceTypeQuery = `<?xml version="1.0" encoding="utf-8"?>
+<D:propfind xmlns:D="DAV:">
+  <D:prop>
+    <D:resourcetype/>
+  </D:prop>
+</D:propfind>`
+
+// caldavMultistatus is a WebDAV multistatus response. Elements are matched
+// by local name, whatever namespace prefix the server uses.
+type caldavMultistatus struct {
+	Responses []struct {
+		Href      string `xml:"href"`
+		Propstats []struct {
+			Status       string    `xml:"status"`
+			CalendarData string    `xml:"prop>calendar-data"`
+			Calendar     *struct{} `xml:"prop>resourcetype>calendar"`
+		} `xml:"propstat"`
+	} `xml:"response"`
+}
+
+// newCalDAVRequest creates a WebDAV request with the configured bearer token
+// or basic authentication
+func (c *CalendarConnector) newCalDAVRequest(ctx context.Context, method, url, depth, body string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, strings.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	req.Header.Set("Depth", depth)
+
+	if token := c.GetConfigString("caldav_token"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	} else if username := c.GetConfigString("caldav_username"); username != "" {
+		req.SetBasicAuth(username, c.GetConfigString("caldav_password"))
+	}
+
+	return req, nil
+}
+
+// doCalDAVRequest sends a WebDAV request and decodes its multistatus response
+func (c *CalendarConnector) doCalDAVRequest(req *http.Request) (*caldavMultistatus, error) {
+	resp, err := c.GetHTTPClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if c.isDebugMode() {
+		log.Printf("Calendar Debug: CalDAV %s response: %d %s", req.Method, resp.StatusCode, resp.Status)
+	}
+
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, fmt.Errorf("HTTP %d: CalDAV %s failed", resp.StatusCode, req.Method)
+	}
+
+	var multistatus caldavMultistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&multistatus); err != nil {
+		return nil, fmt.Errorf("failed to parse CalDAV response: %w", err)
+	}
+	return &multistatus, nil
+}
+
+// testCalDAVURL checks that a URL is a CalDAV calendar collection we can read
+func (c *CalendarConnector) testCalDAVURL(ctx context.Context, url string) error {
+	if c.isDebugMode() {
+		log.Printf("Calendar Debug: Making CalDAV PROPFIND request to %s", c.maskURL(url))
+	}
+
+	req, err := c.newCalDAVRequest(ctx, "PROPFIND", url, "0", resourceTypeQuery)
+	if err != nil {
+		return err
+	}
+
+	multistatus, err := c.doCalDAVRequest(req)
+	if err != nil {
+		return err
+	}
+
+	for _, response := range multistatus.Responses {
+		for _, propstat := range response.Propstats {
+			if propstat.Calendar != nil {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("not a CalDAV calendar collection")
+}
+
+// fetchCalDAVEvents queries a CalDAV collection for the events around the
+// days from first to last (inclusive) and parses them. Recurring events are
+// returned whole by the server and expanded by the parser.
+func (c *CalendarConnector) fetchCalDAVEvents(ctx context.Context, url string, first, last time.Time) ([]ICalEvent, error) {
+	start, end := eventWindow(first, last)
+	body := fmt.Sprintf(calendarQueryTemplate,
+		start.UTC().Format("20060102T150405Z"), end.UTC().Format("20060102T150405Z"))
+
+	if c.isDebugMode() {
+		log.Printf("Calendar Debug: Querying CalDAV collection %s from %s to %s",
+			c.maskURL(url), start.Format(time.RFC3339), end.Format(time.RFC3339))
+	}
+
+	req, err := c.newCalDAVRequest(ctx, "REPORT", url, "1", body)
+	if err != nil {
+		return nil, err
+	}
+
+	multistatus, err := c.doCalDAVRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each calendar object is a complete VCALENDAR; the parser reads them
+	// one after the other
+	var data strings.Builder
+	objects := 0
+	for _, response := range multistatus.Responses {
+		for _, propstat := range response.Propstats {
+			if propstat.CalendarData == "" || (propstat.Status != "" && !strings.Contains(propstat.Status, " 200 ")) {
+				continue
+			}
+			data.WriteString(propstat.CalendarData)
+			data.WriteString("\r\n")
+			objects++
+		}
+	}
+
+	if c.isDebugMode() {
+		log.Printf("Calendar Debug: CalDAV returned %d calendar object(s)", objects)
+	}
+
+	return c.parseICalBody(strings.NewReader(data.String()), first, last)
+}
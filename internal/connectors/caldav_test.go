@@ -0,0 +1,144 @@
+package connectors
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newCalDAVServer is a minimal CalDAV stand-in serving one calendar
+// collection at /calendars/me/work/
+func newCalDAVServer(t *testing.T, objects ...string) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/calendars/me/work/" {
+			http.NotFound(w, r)
+			return
+		}
+		if user, pass, ok := r.BasicAuth(); !ok || user != "me" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+
+		switch r.Method {
+		case "PROPFIND":
+			w.WriteHeader(http.StatusMultiStatus)
+			fmt.Fprint(w, `<?xml version="1.0"?>
+<d:multistatus xmlns:d="DAV:" xmlns:cal="urn:ietf:params:xml:ns:caldav">
+  <d:response>
+    <d:href>/calendars/me/work/</d:href>
+    <d:propstat>
+      <d:prop><d:resourcetype><d:collection/><cal:calendar/></d:resourcetype></d:prop>
+      <d:status>HTTP/1.1 200 OK</d:status>
+    </d:propstat>
+  </d:response>
+</d:multistatus>`)
+		case "REPORT":
+			if r.Header.Get("Depth") != "1" || !strings.Contains(string(body), `<C:time-range start="`) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusMultiStatus)
+			fmt.Fprint(w, `<?xml version="1.0"?><d:multistatus xmlns:d="DAV:" xmlns:cal="urn:ietf:params:xml:ns:caldav">`)
+			for i, object := range objects {
+				fmt.Fprintf(w, `<d:response><d:href>/calendars/me/work/%d.ics</d:href><d:propstat><d:prop><cal:calendar-data>%s</cal:calendar-data></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`,
+					i, object)
+			}
+			fmt.Fprint(w, `</d:multistatus>`)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+}
+
+func TestCalendarConnector_CalDAV(t *testing.T) {
+	server := newCalDAVServer(t,
+		icalFeed(`
+UID:caldav-1
+SUMMARY:Design review
+DTSTART:20240115T100000Z
+DTEND:20240115T110000Z`),
+		icalFeed(`
+UID:caldav-2
+SUMMARY:Daily standup
+DTSTART:20240108T090000Z
+DTEND:20240108T091500Z
+RRULE:FREQ=DAILY;COUNT=10`),
+	)
+	defer server.Close()
+
+	connector := NewCalendarConnector()
+	connector.Configure(map[string]interface{}{
+		"caldav_urls":     server.URL + "/calendars/me/work/",
+		"caldav_username": "me",
+		"caldav_password": "secret",
+	})
+
+	if err := connector.TestConnection(context.Background()); err != nil {
+		t.Fatalf("TestConnection failed: %v", err)
+	}
+
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	activities, err := connector.GetActivities(context.Background(), day)
+	if err != nil {
+		t.Fatalf("GetActivities failed: %v", err)
+	}
+
+	titles := make(map[string]bool)
+	for _, a := range activities {
+		titles[a.Title] = true
+	}
+	if len(activities) != 2 || !titles["Design review"] || !titles["Daily standup"] {
+		t.Errorf("Expected the event and the standup occurrence, got %+v", activities)
+	}
+}
+
+func TestCalendarConnector_CalDAVAuth(t *testing.T) {
+	server := newCalDAVServer(t)
+	defer server.Close()
+
+	connector := NewCalendarConnector()
+	connector.Configure(map[string]interface{}{
+		"caldav_urls":     server.URL + "/calendars/me/work/",
+		"caldav_username": "me",
+		"caldav_password": "wrong",
+	})
+
+	err := connector.TestConnection(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("Expected authentication failure, got %v", err)
+	}
+}
+
+func TestCalendarConnector_CalDAVBearerToken(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusMultiStatus)
+		fmt.Fprint(w, `<multistatus xmlns="DAV:"/>`)
+	}))
+	defer server.Close()
+
+	connector := NewCalendarConnector()
+	connector.Configure(map[string]interface{}{"caldav_token": "abc123"})
+
+	events, err := connector.fetchCalDAVEvents(context.Background(), server.URL, time.Now(), time.Now())
+	if err != nil {
+		t.Fatalf("fetchCalDAVEvents failed: %v", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("Expected no events, got %d", len(events))
+	}
+	if authorization != "Bearer abc123" {
+		t.Errorf("Expected bearer authorization, got %q", authorization)
+	}
+}
@@ -1,12 +1,14 @@
 package connectors
 
 import (
-	"bufio"
 	"context"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
@@ -18,9 +20,14 @@ import (
 var (
 	// maskURLRegex masks the secret part of a Google Calendar iCal URL for logging.
 	maskURLRegex = regexp.MustCompile(`(https://calendar\.google\.com/calendar/ical/[^/]+/)([^/]+)(/basic\.ics)`)
+
+	// calendarIDRegex extracts the calendar ID from a Google Calendar iCal URL
+	calendarIDRegex = regexp.MustCompile(`^https://calendar\.google\.com/calendar/ical/([^/]+)/`)
 )
 
-// CalendarConnector implements the Connector interface for Google Calendar using iCal feeds
+// CalendarConnector implements the Connector interface for calendars published
+// as iCal feeds (Google, Nextcloud, Fastmail, Outlook...), local .ics files and
+// CalDAV collections
 type CalendarConnector struct {
 	*BaseConnector
 }
@@ -30,7 +37,7 @@ func NewCalendarConnector() *CalendarConnector {
 	return &CalendarConnector{
 		BaseConnector: NewBaseConnector(
 			"calendar",
-			"Fetches Google Calendar events using secret iCal URLs",
+			"Fetches calendar events from iCal feeds, .ics files and CalDAV servers",
 		),
 	}
 }
@@ -43,8 +50,32 @@ func (c *CalendarConnector) GetRequiredConfig() []ConfigField {
 		{
 			Key:         "ical_urls",
 			Type:        "string",
-			Required:    true,
-			Description: "Comma-separated list of Google Calendar secret iCal URLs",
+			Required:    false,
+			Description: "Comma-separated list of iCal feed URLs (http, https or file://)",
+		},
+		{
+			Key:         "caldav_urls",
+			Type:        "string",
+			Required:    false,
+			Description: "Comma-separated list of CalDAV calendar collection URLs",
+		},
+		{
+			Key:         "caldav_username",
+			Type:        "string",
+			Required:    false,
+			Description: "CalDAV username for basic authentication",
+		},
+		{
+			Key:         "caldav_password",
+			Type:        "secret",
+			Required:    false,
+			Description: "CalDAV password or app password for basic authentication",
+		},
+		{
+			Key:         "caldav_token",
+			Type:        "secret",
+			Required:    false,
+			Description: "CalDAV bearer token (instead of username and password)",
 		},
 		{
 			Key:         "include_declined",
@@ -53,6 +84,12 @@ func (c *CalendarConnector) GetRequiredConfig() []ConfigField {
 			Description: "Include declined events",
 			Default:     false,
 		},
+		{
+			Key:         "email",
+			Type:        "string",
+			Required:    false,
+			Description: "Comma-separated email addresses used to find your own attendee entry (defaults to the Google calendar ID or the CalDAV username)",
+		},
 	}
 
 	// Merge with common fields
@@ -67,19 +104,48 @@ func (c *CalendarConnector) ValidateConfig(config map[string]interface{}) error
 	}
 
 	// Additional calendar-specific validation
-	icalURLs := config["ical_urls"].(string)
+	icalURLs, _ := config["ical_urls"].(string)
+	caldavURLs, _ := config["caldav_urls"].(string)
 
-	// Validate that URLs look like Google Calendar iCal URLs
-	urls := c.parseICalURLs(icalURLs)
-	for _, url := range urls {
-		if !c.isValidGoogleCalendarURL(url) {
-			return fmt.Errorf("invalid Google Calendar iCal URL: %s", url)
+	feeds := c.parseICalURLs(icalURLs)
+	collections := c.parseICalURLs(caldavURLs)
+	if len(feeds) == 0 && len(collections) == 0 {
+		return fmt.Errorf("at least one of ical_urls or caldav_urls is required")
+	}
+
+	for _, feed := range feeds {
+		if !c.isValidICalURL(feed, true) {
+			return fmt.Errorf("invalid iCal URL (expected http, https or file://): %s", c.maskURL(feed))
+		}
+	}
+	for _, collection := range collections {
+		if !c.isValidICalURL(collection, false) {
+			return fmt.Errorf("invalid CalDAV URL (expected http or https): %s", c.maskURL(collection))
 		}
 	}
 
 	return nil
 }
 
+// calendarSource is a calendar to read events from
+type calendarSource struct {
+	url    string
+	caldav bool
+}
+
+// getSources returns the configured iCal feeds followed by the CalDAV
+// collections
+func (c *CalendarConnector) getSources() []calendarSource {
+	var sources []calendarSource
+	for _, u := range c.parseICalURLs(c.GetConfigString("ical_urls")) {
+		sources = append(sources, calendarSource{url: u})
+	}
+	for _, u := range c.parseICalURLs(c.GetConfigString("caldav_urls")) {
+		sources = append(sources, calendarSource{url: u, caldav: true})
+	}
+	return sources
+}
+
 // isDebugMode checks if debug logging is enabled
 func (c *CalendarConnector) isDebugMode() bool {
 	return c.BaseConnector.IsDebugMode()
@@ -87,21 +153,27 @@ func (c *CalendarConnector) isDebugMode() bool {
 
 // TestConnection tests the calendar connection
 func (c *CalendarConnector) TestConnection(ctx context.Context) error {
-	urls := c.parseICalURLs(c.GetConfigString("ical_urls"))
+	sources := c.getSources()
 
 	if c.isDebugMode() {
-		log.Printf("Calendar Debug: Testing connection to %d calendar(s)", len(urls))
+		log.Printf("Calendar Debug: Testing connection to %d calendar(s)", len(sources))
 	}
 
-	for i, url := range urls {
+	for i, source := range sources {
 		if c.isDebugMode() {
-			log.Printf("Calendar Debug: Testing calendar %d: %s", i+1, c.maskURL(url))
+			log.Printf("Calendar Debug: Testing calendar %d: %s", i+1, c.maskURL(source.url))
+		}
+		var err error
+		if source.caldav {
+			err = c.testCalDAVURL(ctx, source.url)
+		} else {
+			err = c.testICalURL(ctx, source.url)
 		}
-		if err := c.testICalURL(ctx, url); err != nil {
+		if err != nil {
 			if c.isDebugMode() {
 				log.Printf("Calendar Debug: Failed to connect to calendar %d: %v", i+1, err)
 			}
-			return fmt.Errorf("failed to connect to calendar %d (%s): %v", i+1, c.maskURL(url), err)
+			return fmt.Errorf("failed to connect to calendar %d (%s): %v", i+1, c.maskURL(source.url), err)
 		}
 		if c.isDebugMode() {
 			log.Printf("Calendar Debug: Successfully connected to calendar %d", i+1)
@@ -109,7 +181,7 @@ func (c *CalendarConnector) TestConnection(ctx context.Context) error {
 	}
 
 	if c.isDebugMode() {
-		log.Printf("Calendar Debug: All %d calendar(s) connected successfully", len(urls))
+		log.Printf("Calendar Debug: All %d calendar(s) connected successfully", len(sources))
 	}
 
 	return nil
@@ -117,7 +189,27 @@ func (c *CalendarConnector) TestConnection(ctx context.Context) error {
 
 // GetActivities retrieves calendar activities for the specified date
 func (c *CalendarConnector) GetActivities(ctx context.Context, date time.Time) ([]timeline.Activity, error) {
-	urls := c.parseICalURLs(c.GetConfigString("ical_urls"))
+	if c.isDebugMode() {
+		log.Printf("Calendar Debug: Fetching events for date %s", date.Format("2006-01-02"))
+	}
+
+	return c.fetchActivities(ctx, date, date)
+}
+
+// GetActivitiesRange retrieves calendar activities for every day from start
+// to end (inclusive), downloading each calendar only once.
+func (c *CalendarConnector) GetActivitiesRange(ctx context.Context, start, end time.Time) ([]timeline.Activity, error) {
+	if c.isDebugMode() {
+		log.Printf("Calendar Debug: Fetching events from %s to %s", start.Format("2006-01-02"), end.Format("2006-01-02"))
+	}
+
+	return c.fetchActivities(ctx, start, end)
+}
+
+// fetchActivities retrieves events starting on the days from first to last
+// (inclusive) from all configured calendars
+func (c *CalendarConnector) fetchActivities(ctx context.Context, first, last time.Time) ([]timeline.Activity, error) {
+	sources := c.getSources()
 	var allActivities []timeline.Activity
 	seenEventUIDs := make(map[string]bool) // Track seen event UIDs to prevent duplicates
 
@@ -130,14 +222,14 @@ func (c *CalendarConnector) GetActivities(ctx context.Context, date time.Time) (
 	}
 
 	if c.isDebugMode() {
-		log.Printf("Calendar Debug: Fetching events for date %s from %d calendar(s)", date.Format("2006-01-02"), len(urls))
+		log.Printf("Calendar Debug: Fetching events from %d calendar(s)", len(sources))
 	}
 
-	for i, url := range urls {
+	for i, source := range sources {
 		if c.isDebugMode() {
-			log.Printf("Calendar Debug: Processing calendar %d: %s", i+1, c.maskURL(url))
+			log.Printf("Calendar Debug: Processing calendar %d: %s", i+1, c.maskURL(source.url))
 		}
-		activities, err := c.fetchCalendarEvents(ctx, url, date)
+		activities, err := c.fetchCalendarEvents(ctx, source, first, last)
 		if err != nil {
 			if c.isDebugMode() {
 				log.Printf("Calendar Debug: Failed to fetch events from calendar %d: %v", i+1, err)
@@ -149,19 +241,20 @@ func (c *CalendarConnector) GetActivities(ctx context.Context, date time.Time) (
 			log.Printf("Calendar Debug: Found %d events from calendar %d", len(activities), i+1)
 		}
 
-		// Add activities, filtering out duplicates based on event UID
+		// Add activities, filtering out duplicates based on event UID and day
 		duplicatesSkipped := 0
 		for _, activity := range activities {
 			// Extract event UID from the activity metadata
 			if eventUID, exists := activity.Metadata["event_id"]; exists && eventUID != "" {
-				if seenEventUIDs[eventUID] {
+				key := eventUID + "@" + activity.Timestamp.Format("2006-01-02")
+				if seenEventUIDs[key] {
 					duplicatesSkipped++
 					if c.isDebugMode() {
 						log.Printf("Calendar Debug: Skipping duplicate event '%s' with UID: %s", activity.Title, eventUID)
 					}
 					continue
 				}
-				seenEventUIDs[eventUID] = true
+				seenEventUIDs[key] = true
 				allActivities = append(allActivities, activity)
 			} else {
 				// Handle activities without event_id or with empty UID (shouldn't happen with valid iCal data)
@@ -206,23 +299,103 @@ func (c *CalendarConnector) parseICalURLs(urls string) []string {
 	return result
 }
 
-// isValidGoogleCalendarURL checks if the URL looks like a valid Google Calendar iCal URL
-func (c *CalendarConnector) isValidGoogleCalendarURL(url string) bool {
-	// Google Calendar iCal URLs follow this pattern:
-	// https://calendar.google.com/calendar/ical/[calendar-id]/[secret]/basic.ics
-	pattern := `^https://calendar\.google\.com/calendar/ical/[^/]+/[^/]+/basic\.ics$`
-	matched, _ := regexp.MatchString(pattern, url)
-	return matched
+// isValidICalURL checks that a calendar URL is an http(s) URL, or a file://
+// URL when allowFile is set
+func (c *CalendarConnector) isValidICalURL(raw string, allowFile bool) bool {
+	if _, ok := icalFilePath(raw); ok {
+		return allowFile
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
 }
 
-// maskURL masks the secret part of the URL for logging
-func (c *CalendarConnector) maskURL(url string) string {
-	// Replace the secret part with asterisks
-	return maskURLRegex.ReplaceAllString(url, "${1}***${3}")
+// icalFilePath returns the local path of a file:// URL. A leading ~/ is
+// expanded to the home directory.
+func icalFilePath(raw string) (string, bool) {
+	if !strings.HasPrefix(strings.ToLower(raw), "file://") {
+		return "", false
+	}
+	path, err := url.PathUnescape(raw[len("file://"):])
+	if err != nil || path == "" {
+		return "", false
+	}
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[2:])
+		}
+	}
+	return path, true
+}
+
+// ownerEmails returns the addresses identifying the user among the
+// attendees of a calendar: the configured ones, or else the calendar ID of
+// a Google Calendar URL, which is the owner's address for primary calendars
+func (c *CalendarConnector) ownerEmails(calendarURL string) []string {
+	var emails []string
+	for _, email := range strings.Split(c.GetConfigString("email"), ",") {
+		if email = strings.TrimSpace(email); email != "" {
+			emails = append(emails, email)
+		}
+	}
+	if len(emails) > 0 {
+		return emails
+	}
+
+	// CalDAV accounts are usually named after the user's address
+	if username := c.GetConfigString("caldav_username"); strings.Contains(username, "@") {
+		emails = append(emails, username)
+	}
+	if m := calendarIDRegex.FindStringSubmatch(calendarURL); m != nil {
+		if id, err := url.PathUnescape(m[1]); err == nil && strings.Contains(id, "@") {
+			emails = append(emails, id)
+		}
+	}
+	return emails
+}
+
+// maskURL masks the secret part of the URL for logging. Published calendar
+// URLs of other services (Outlook, Fastmail, Nextcloud) embed their token
+// in the path, so only the host and the file name are kept.
+func (c *CalendarConnector) maskURL(raw string) string {
+	if maskURLRegex.MatchString(raw) {
+		// Replace the secret part with asterisks
+		return maskURLRegex.ReplaceAllString(raw, "${1}***${3}")
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "file" || u.Host == "" {
+		return raw
+	}
+
+	masked := u.Scheme + "://" + u.Host
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) > 1 {
+		masked += "/***/" + segments[len(segments)-1]
+	} else if u.Path != "" {
+		masked += u.Path
+	}
+	if u.RawQuery != "" {
+		masked += "?***"
+	}
+	return masked
 }
 
 // testICalURL tests connectivity to a single iCal URL
 func (c *CalendarConnector) testICalURL(ctx context.Context, url string) error {
+	if path, ok := icalFilePath(url); ok {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return fmt.Errorf("%s is a directory", path)
+		}
+		return nil
+	}
+
 	if c.isDebugMode() {
 		log.Printf("Calendar Debug: Making HTTP request to %s", c.maskURL(url))
 	}
@@ -263,36 +436,17 @@ func (c *CalendarConnector) testICalURL(ctx context.Context, url string) error {
 	return nil
 }
 
-// fetchCalendarEvents fetches and parses events from an iCal URL for a specific date
-func (c *CalendarConnector) fetchCalendarEvents(ctx context.Context, url string, date time.Time) ([]timeline.Activity, error) {
-	if c.isDebugMode() {
-		log.Printf("Calendar Debug: Fetching calendar data from %s", c.maskURL(url))
-	}
-
-	req, err := c.CreateRequest(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := c.GetHTTPClient().Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if c.isDebugMode() {
-		log.Printf("Calendar Debug: HTTP response: %d %s", resp.StatusCode, resp.Status)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP %d: failed to fetch calendar data", resp.StatusCode)
+// fetchCalendarEvents fetches and parses events from a calendar starting on
+// the days from first to last (inclusive)
+func (c *CalendarConnector) fetchCalendarEvents(ctx context.Context, source calendarSource, first, last time.Time) ([]timeline.Activity, error) {
+	var events []ICalEvent
+	var err error
+	if source.caldav {
+		events, err = c.fetchCalDAVEvents(ctx, source.url, first, last)
+	} else {
+		events, err = c.fetchICalEvents(ctx, source.url, first, last)
 	}
-
-	events, err := c.parseICalData(resp.Body)
 	if err != nil {
-		if c.isDebugMode() {
-			log.Printf("Calendar Debug: Failed to parse iCal data: %v", err)
-		}
 		return nil, err
 	}
 
@@ -300,27 +454,38 @@ func (c *CalendarConnector) fetchCalendarEvents(ctx context.Context, url string,
 		log.Printf("Calendar Debug: Parsed %d total events from iCal data", len(events))
 	}
 
-	// Filter events for the specific date
+	// Filter events for the requested days. Dates in YYYY-MM-DD form
+	// compare correctly as strings.
 	var activities []timeline.Activity
-	targetDate := date.Format("2006-01-02")
+	firstDate := first.Format("2006-01-02")
+	lastDate := last.Format("2006-01-02")
 	includeDeclined := c.GetConfigBool("include_declined")
 	filteredCount := 0
 	declinedSkipped := 0
 
 	if c.isDebugMode() {
-		log.Printf("Calendar Debug: Filtering events for target date: %s", targetDate)
+		log.Printf("Calendar Debug: Filtering events from %s to %s", firstDate, lastDate)
 		log.Printf("Calendar Debug: Include declined events: %t", includeDeclined)
 	}
 
+	emails := c.ownerEmails(source.url)
+	if c.isDebugMode() {
+		log.Printf("Calendar Debug: Looking up own attendee entries as %v", emails)
+	}
+
 	for _, event := range events {
 		eventDate := event.StartTime.Format("2006-01-02")
-		if eventDate != targetDate {
+		if eventDate < firstDate || eventDate > lastDate {
 			continue
 		}
 		filteredCount++
 
+		// My response lives in my attendee entry, not in the event STATUS
+		attendee, isAttendee := event.Attendee(emails)
+		declined := event.Status == "DECLINED" || (isAttendee && attendee.PartStat == "DECLINED")
+
 		// Skip declined events if configured to do so
-		if !includeDeclined && event.Status == "DECLINED" {
+		if !includeDeclined && declined {
 			declinedSkipped++
 			if c.isDebugMode() {
 				log.Printf("Calendar Debug: Skipping declined event: %s", event.Summary)
@@ -341,9 +506,26 @@ func (c *CalendarConnector) fetchCalendarEvents(ctx context.Context, url string,
 		if event.Organizer != "" {
 			metadata["organizer"] = event.Organizer
 		}
+		if event.AllDay {
+			metadata["all_day"] = "true"
+		}
+		if len(event.Attendees) > 0 {
+			metadata["attendees"] = fmt.Sprintf("%d", len(event.Attendees))
+		}
+		if isAttendee {
+			metadata["response"] = strings.ToLower(attendee.PartStat)
+		}
+
+		// Occurrences of a recurring event share their UID, so the
+		// original start time keeps their IDs apart
+		activityID := fmt.Sprintf("calendar-google-%s", event.UID)
+		if !event.RecurrenceID.IsZero() {
+			metadata["recurrence_id"] = event.RecurrenceID.Format(time.RFC3339)
+			activityID += "-" + event.RecurrenceID.UTC().Format("20060102T150405Z")
+		}
 
 		activity := timeline.Activity{
-			ID:          fmt.Sprintf("calendar-google-%s", event.UID),
+			ID:          activityID,
 			Type:        timeline.ActivityTypeCalendar,
 			Title:       event.Summary,
 			Description: event.Description,
@@ -363,7 +545,7 @@ func (c *CalendarConnector) fetchCalendarEvents(ctx context.Context, url string,
 	}
 
 	if c.isDebugMode() {
-		log.Printf("Calendar Debug: Events for target date: %d", filteredCount)
+		log.Printf("Calendar Debug: Events in requested days: %d", filteredCount)
 		log.Printf("Calendar Debug: Declined events skipped: %d", declinedSkipped)
 		log.Printf("Calendar Debug: Final activities created: %d", len(activities))
 	}
@@ -371,235 +553,57 @@ func (c *CalendarConnector) fetchCalendarEvents(ctx context.Context, url string,
 	return activities, nil
 }
 
-// ICalEvent represents a parsed iCal event
-type ICalEvent struct {
-	UID         string
-	Summary     string
-	Description string
-	Location    string
-	StartTime   time.Time
-	EndTime     time.Time
-	Status      string
-	Organizer   string
-	URL         string
-}
-
-// parseICalData parses iCal format data and extracts events
-func (c *CalendarConnector) parseICalData(r io.Reader) ([]ICalEvent, error) {
-	var events []ICalEvent
-	var currentEvent *ICalEvent
-	lineCount := 0
-	eventCount := 0
-
-	if c.isDebugMode() {
-		log.Printf("Calendar Debug: Starting to parse iCal data")
-	}
-
-	// Use a larger buffer than the default 64KB to handle long iCal lines
-	// (e.g. base64-encoded DESCRIPTION or ATTACH values).
-	scanner := bufio.NewScanner(r)
-	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
-
-	for scanner.Scan() {
-		lineCount++
-		line := strings.TrimSpace(scanner.Text())
-
-		if line == "BEGIN:VEVENT" {
-			currentEvent = &ICalEvent{}
-			eventCount++
-			if c.isDebugMode() {
-				log.Printf("Calendar Debug: Found event %d at line %d", eventCount, lineCount)
-			}
-		} else if line == "END:VEVENT" && currentEvent != nil {
-			if currentEvent.Summary != "" {
-				events = append(events, *currentEvent)
-				if c.isDebugMode() {
-					log.Printf("Calendar Debug: Completed parsing event: %s (Start: %s)",
-						currentEvent.Summary, currentEvent.StartTime.Format("2006-01-02 15:04"))
-				}
-			} else {
-				if c.isDebugMode() {
-					log.Printf("Calendar Debug: Skipping event with empty summary")
-				}
-			}
-			currentEvent = nil
-		} else if currentEvent != nil {
-			c.parseICalLine(line, currentEvent)
+// fetchICalEvents downloads an iCal feed, or reads it for file:// URLs, and
+// parses its events
+func (c *CalendarConnector) fetchICalEvents(ctx context.Context, url string, first, last time.Time) ([]ICalEvent, error) {
+	if path, ok := icalFilePath(url); ok {
+		if c.isDebugMode() {
+			log.Printf("Calendar Debug: Reading calendar file %s", path)
 		}
-	}
 
-	if err := scanner.Err(); err != nil {
-		if c.isDebugMode() {
-			log.Printf("Calendar Debug: Scanner error: %v", err)
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
 		}
-		return nil, err
+		defer file.Close()
+
+		return c.parseICalBody(file, first, last)
 	}
 
 	if c.isDebugMode() {
-		log.Printf("Calendar Debug: Parsed %d lines, found %d events, extracted %d valid events",
-			lineCount, eventCount, len(events))
+		log.Printf("Calendar Debug: Fetching calendar data from %s", c.maskURL(url))
 	}
 
-	return events, nil
-}
-
-// parseICalLine parses a single line from iCal data
-func (c *CalendarConnector) parseICalLine(line string, event *ICalEvent) {
-	// Handle line folding (lines starting with space or tab are continuations)
-	if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
-		return // Skip folded lines for simplicity
-	}
-
-	// Split property and value
-	parts := strings.SplitN(line, ":", 2)
-	if len(parts) != 2 {
-		return
-	}
-
-	property := parts[0]
-	value := parts[1]
-
-	// Handle properties with parameters (e.g., "DTSTART;TZID=America/New_York")
-	propParts := strings.SplitN(property, ";", 2)
-	propName := propParts[0]
-
-	switch propName {
-	case "UID":
-		event.UID = value
-	case "SUMMARY":
-		event.Summary = c.unescapeICalValue(value)
-	case "DESCRIPTION":
-		event.Description = c.unescapeICalValue(value)
-	case "LOCATION":
-		event.Location = c.unescapeICalValue(value)
-	case "DTSTART":
-		// Check for timezone parameter
-		var tzid string
-		if len(propParts) > 1 {
-			params := strings.Split(propParts[1], ";")
-			for _, param := range params {
-				if strings.HasPrefix(param, "TZID=") {
-					tzid = strings.TrimPrefix(param, "TZID=")
-					break
-				}
-			}
-		}
-		if t, err := c.parseICalDateTimeWithTZ(value, tzid); err == nil {
-			event.StartTime = t
-		} else if c.isDebugMode() {
-			log.Printf("Calendar Debug: Failed to parse DTSTART '%s' with TZID '%s': %v", value, tzid, err)
-		}
-	case "DTEND":
-		// Check for timezone parameter
-		var tzid string
-		if len(propParts) > 1 {
-			params := strings.Split(propParts[1], ";")
-			for _, param := range params {
-				if strings.HasPrefix(param, "TZID=") {
-					tzid = strings.TrimPrefix(param, "TZID=")
-					break
-				}
-			}
-		}
-		if t, err := c.parseICalDateTimeWithTZ(value, tzid); err == nil {
-			event.EndTime = t
-		} else if c.isDebugMode() {
-			log.Printf("Calendar Debug: Failed to parse DTEND '%s' with TZID '%s': %v", value, tzid, err)
-		}
-	case "STATUS":
-		event.Status = value
-	case "ORGANIZER":
-		// Extract email from ORGANIZER field (format: "ORGANIZER:mailto:email@example.com")
-		if strings.HasPrefix(value, "mailto:") {
-			event.Organizer = strings.TrimPrefix(value, "mailto:")
-		} else {
-			event.Organizer = value
-		}
-	case "URL":
-		event.URL = value
+	req, err := c.CreateRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
 	}
-}
 
-// parseICalDateTimeWithTZ parses iCal date/time formats with timezone support
-func (c *CalendarConnector) parseICalDateTimeWithTZ(value, tzid string) (time.Time, error) {
-	// Handle different iCal date/time formats
-	formats := []string{
-		"20060102T150405Z", // UTC format
-		"20060102T150405",  // Local/timezone format
-		"20060102",         // Date only
+	resp, err := c.GetHTTPClient().Do(req)
+	if err != nil {
+		return nil, err
 	}
+	defer resp.Body.Close()
 
-	var parsedTime time.Time
-	var err error
-
-	// Try to parse with each format
-	for _, format := range formats {
-		if parsedTime, err = time.Parse(format, value); err == nil {
-			break
-		}
+	if c.isDebugMode() {
+		log.Printf("Calendar Debug: HTTP response: %d %s", resp.StatusCode, resp.Status)
 	}
 
-	if err != nil {
-		if c.isDebugMode() {
-			log.Printf("Calendar Debug: Failed to parse date/time with all formats: %s", value)
-		}
-		return time.Time{}, fmt.Errorf("unable to parse date/time: %s", value)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d: failed to fetch calendar data", resp.StatusCode)
 	}
 
-	// Handle timezone conversion
-	if tzid != "" && !strings.HasSuffix(value, "Z") {
-		// Load the specified timezone
-		loc, err := time.LoadLocation(tzid)
-		if err != nil {
-			if c.isDebugMode() {
-				log.Printf("Calendar Debug: Failed to load timezone '%s', falling back to local time: %v", tzid, err)
-			}
-			// If timezone loading fails, treat as local time
-			loc = time.Local
-		}
-
-		// If the parsed time doesn't have timezone info, assume it's in the specified timezone
-		if parsedTime.Location() == time.UTC && !strings.HasSuffix(value, "Z") {
-			parsedTime = time.Date(
-				parsedTime.Year(), parsedTime.Month(), parsedTime.Day(),
-				parsedTime.Hour(), parsedTime.Minute(), parsedTime.Second(),
-				parsedTime.Nanosecond(), loc,
-			)
-		}
-
-		// Convert to local timezone for display
-		parsedTime = parsedTime.In(time.Local)
+	return c.parseICalBody(resp.Body, first, last)
+}
 
+// parseICalBody parses iCal data, logging parse failures in debug mode
+func (c *CalendarConnector) parseICalBody(r io.Reader, first, last time.Time) ([]ICalEvent, error) {
+	events, err := c.parseICalData(r, first, last)
+	if err != nil {
 		if c.isDebugMode() {
-			log.Printf("Calendar Debug: Parsed '%s' with TZID '%s' -> %s (local: %s)",
-				value, tzid, parsedTime.Format("2006-01-02 15:04:05 MST"),
-				parsedTime.In(time.Local).Format("2006-01-02 15:04:05 MST"))
-		}
-	} else if strings.HasSuffix(value, "Z") {
-		// UTC time, convert to local
-		parsedTime = parsedTime.In(time.Local)
-		if c.isDebugMode() {
-			log.Printf("Calendar Debug: Parsed UTC time '%s' -> %s (local: %s)",
-				value, parsedTime.UTC().Format("2006-01-02 15:04:05 UTC"),
-				parsedTime.Format("2006-01-02 15:04:05 MST"))
-		}
-	} else {
-		// No timezone specified, assume local time
-		if c.isDebugMode() {
-			log.Printf("Calendar Debug: Parsed local time '%s' -> %s",
-				value, parsedTime.Format("2006-01-02 15:04:05"))
+			log.Printf("Calendar Debug: Failed to parse iCal data: %v", err)
 		}
+		return nil, err
 	}
-
-	return parsedTime, nil
-}
-
-// unescapeICalValue unescapes iCal text values
-func (c *CalendarConnector) unescapeICalValue(value string) string {
-	// Unescape common iCal escape sequences
-	value = strings.ReplaceAll(value, "\\n", "\n")
-	value = strings.ReplaceAll(value, "\\,", ",")
-	value = strings.ReplaceAll(value, "\\;", ";")
-	value = strings.ReplaceAll(value, "\\\\", "\\")
-	return value
+	return events, nil
 }
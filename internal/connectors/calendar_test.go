@@ -0,0 +1,203 @@
+package connectors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewCalendarConnector(t *testing.T) {
+	connector := NewCalendarConnector()
+
+	if connector.Name() != "calendar" {
+		t.Errorf("Expected name 'calendar', got %s", connector.Name())
+	}
+
+	if connector.IsEnabled() {
+		t.Error("Connector should be disabled by default")
+	}
+}
+
+func TestCalendarConnector_RecurringEventsInRange(t *testing.T) {
+	feed := icalFeed(`
+UID:standup
+SUMMARY:Standup
+DTSTART:20230102T090000Z
+DTEND:20230102T091500Z
+RRULE:FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR`)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/calendar")
+		fmt.Fprint(w, feed)
+	}))
+	defer server.Close()
+
+	connector := NewCalendarConnector()
+	first := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	last := time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)
+
+	activities, err := connector.fetchCalendarEvents(context.Background(), calendarSource{url: server.URL}, first, last)
+	if err != nil {
+		t.Fatalf("fetchCalendarEvents failed: %v", err)
+	}
+
+	if len(activities) != 5 {
+		t.Fatalf("Expected 5 standups in the week, got %d", len(activities))
+	}
+
+	ids := make(map[string]bool)
+	for _, a := range activities {
+		if ids[a.ID] {
+			t.Errorf("Expected unique activity IDs, got duplicate %s", a.ID)
+		}
+		ids[a.ID] = true
+		if a.Metadata["event_id"] != "standup" || a.Metadata["recurrence_id"] == "" {
+			t.Errorf("Unexpected metadata: %v", a.Metadata)
+		}
+	}
+}
+
+func TestCalendarConnector_DeclinedByOwnAttendeeEntry(t *testing.T) {
+	feed := icalFeed(`
+UID:declined
+SUMMARY:Optional sync
+DTSTART:20240115T100000Z
+DTEND:20240115T110000Z
+STATUS:CONFIRMED
+ATTENDEE;CN=Someone;PARTSTAT=ACCEPTED:mailto:someone@example.com
+ATTENDEE;CN=Me;PARTSTAT=DECLINED:mailto:me@example.com`, `
+UID:accepted
+SUMMARY:Planning
+DTSTART:20240115T140000Z
+DTEND:20240115T150000Z
+ATTENDEE;PARTSTAT=ACCEPTED:mailto:ME@example.com`)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/calendar")
+		fmt.Fprint(w, feed)
+	}))
+	defer server.Close()
+
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	connector := NewCalendarConnector()
+	connector.Configure(map[string]interface{}{"email": "me@example.com"})
+
+	activities, err := connector.fetchCalendarEvents(context.Background(), calendarSource{url: server.URL}, day, day)
+	if err != nil {
+		t.Fatalf("fetchCalendarEvents failed: %v", err)
+	}
+	if len(activities) != 1 || activities[0].Title != "Planning" {
+		t.Fatalf("Expected only the accepted event, got %+v", activities)
+	}
+	if activities[0].Metadata["response"] != "accepted" {
+		t.Errorf("Expected response 'accepted', got %q", activities[0].Metadata["response"])
+	}
+
+	connector.Configure(map[string]interface{}{"email": "me@example.com", "include_declined": true})
+	activities, err = connector.fetchCalendarEvents(context.Background(), calendarSource{url: server.URL}, day, day)
+	if err != nil {
+		t.Fatalf("fetchCalendarEvents failed: %v", err)
+	}
+	if len(activities) != 2 {
+		t.Errorf("Expected declined event with include_declined, got %d activities", len(activities))
+	}
+}
+
+func TestCalendarConnector_OwnerEmails(t *testing.T) {
+	connector := NewCalendarConnector()
+
+	emails := connector.ownerEmails("https://calendar.google.com/calendar/ical/jane.doe%40example.com/private-abc/basic.ics")
+	if len(emails) != 1 || emails[0] != "jane.doe@example.com" {
+		t.Errorf("Expected calendar ID as owner email, got %v", emails)
+	}
+
+	if emails := connector.ownerEmails("https://calendar.google.com/calendar/ical/abc123%40group.calendar.google.com/private-abc/basic.ics"); len(emails) != 1 {
+		t.Errorf("Expected group calendar ID to be used, got %v", emails)
+	}
+
+	connector.Configure(map[string]interface{}{"email": "me@example.com, me@work.example.com"})
+	emails = connector.ownerEmails("https://calendar.google.com/calendar/ical/jane.doe%40example.com/private-abc/basic.ics")
+	if len(emails) != 2 || emails[1] != "me@work.example.com" {
+		t.Errorf("Expected configured emails, got %v", emails)
+	}
+}
+
+func TestCalendarConnector_ValidateConfig(t *testing.T) {
+	connector := NewCalendarConnector()
+
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr bool
+	}{
+		{"google feed", map[string]interface{}{"ical_urls": "https://calendar.google.com/calendar/ical/me%40example.com/private-abc/basic.ics"}, false},
+		{"nextcloud and local file", map[string]interface{}{"ical_urls": "https://cloud.example.com/remote.php/dav/public-calendars/abc?export, file:///home/me/work.ics"}, false},
+		{"caldav only", map[string]interface{}{"caldav_urls": "https://caldav.fastmail.com/dav/calendars/user/me@fastmail.com/Default/"}, false},
+		{"nothing configured", map[string]interface{}{"ical_urls": ""}, true},
+		{"unsupported scheme", map[string]interface{}{"ical_urls": "webcal://example.com/cal.ics"}, true},
+		{"file url for caldav", map[string]interface{}{"caldav_urls": "file:///home/me/work.ics"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := connector.ValidateConfig(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCalendarConnector_MaskURL(t *testing.T) {
+	connector := NewCalendarConnector()
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"https://calendar.google.com/calendar/ical/me%40example.com/private-abc/basic.ics", "https://calendar.google.com/calendar/ical/me%40example.com/***/basic.ics"},
+		{"https://outlook.office365.com/owa/calendar/abc@example.com/secret123/calendar.ics", "https://outlook.office365.com/***/calendar.ics"},
+		{"https://cloud.example.com/cal.ics?token=secret", "https://cloud.example.com/cal.ics?***"},
+		{"file:///home/me/work.ics", "file:///home/me/work.ics"},
+	}
+
+	for _, tt := range tests {
+		if got := connector.maskURL(tt.input); got != tt.expected {
+			t.Errorf("maskURL(%q) = %q, expected %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestCalendarConnector_LocalFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.ics")
+	feed := icalFeed(`
+UID:local
+SUMMARY:Exported meeting
+DTSTART:20240115T100000Z
+DTEND:20240115T110000Z`)
+	if err := os.WriteFile(path, []byte(feed), 0o644); err != nil {
+		t.Fatalf("Failed to write calendar file: %v", err)
+	}
+
+	connector := NewCalendarConnector()
+	connector.Configure(map[string]interface{}{"ical_urls": "file://" + path})
+
+	if err := connector.TestConnection(context.Background()); err != nil {
+		t.Errorf("TestConnection failed: %v", err)
+	}
+
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	activities, err := connector.GetActivities(context.Background(), day)
+	if err != nil {
+		t.Fatalf("GetActivities failed: %v", err)
+	}
+	if len(activities) != 1 || activities[0].Title != "Exported meeting" {
+		t.Errorf("Expected the event from the file, got %+v", activities)
+	}
+}
@@ -61,6 +61,40 @@ type Connector interface {
 	TestConnection(ctx context.Context) error
 }
 
+// NamedConnector is a connector that can run as several named instances of
+// its type. Connectors embedding BaseConnector implement it.
+type NamedConnector interface {
+	Connector
+
+	// SetName sets the instance name, which replaces the type name in the
+	// registry, the config file, the cache and activity sources
+	SetName(name string)
+}
+
+// VersionedConnector is a connector whose activities have changed shape
+// between releases, e.g. a new title format. Its version is stored with
+// each cached day, and days cached by another version are fetched again.
+// Connectors that don't implement it are at version 0.
+type VersionedConnector interface {
+	Connector
+
+	// OutputVersion returns the version of the activities the connector
+	// builds; increase it whenever they change
+	OutputVersion() int
+}
+
+// OutputVersions returns the output version of every versioned connector,
+// by connector name, for cache.Cache.SetOutputVersions
+func OutputVersions(connectors map[string]Connector) map[string]int {
+	versions := make(map[string]int)
+	for name, connector := range connectors {
+		if versioned, ok := connector.(VersionedConnector); ok {
+			versions[name] = versioned.OutputVersion()
+		}
+	}
+	return versions
+}
+
 // ConfigField represents a configuration field required by a connector
 type ConfigField struct {
 	Key         string      `json:"key"`
@@ -119,7 +153,7 @@ func CommonConfigFields() map[string]ConfigField {
 			Key:         CommonConfigKeys.CacheTTL,
 			Type:        "int",
 			Required:    false,
-			Description: "Cache TTL in minutes",
+			Description: "Minutes after which recent cached days are refetched (overrides cache.ttl_minutes)",
 			Default:     60,
 		},
 	}
@@ -266,6 +300,12 @@ func (b *BaseConnector) Name() string {
 	return b.name
 }
 
+// SetName renames the connector, to run it as an additional instance of its
+// type (e.g. gitlab:client-a)
+func (b *BaseConnector) SetName(name string) {
+	b.name = name
+}
+
 // Description returns the connector description
 func (b *BaseConnector) Description() string {
 	return b.description
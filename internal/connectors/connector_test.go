@@ -648,3 +648,24 @@ func BenchmarkBaseConnector_GetConfigString(b *testing.B) {
 		connector.GetConfigString("test_key")
 	}
 }
+
+// versionedMockConnector is a MockConnector with an output version
+type versionedMockConnector struct {
+	*MockConnector
+	version int
+}
+
+func (v *versionedMockConnector) OutputVersion() int {
+	return v.version
+}
+
+func TestOutputVersions(t *testing.T) {
+	versions := OutputVersions(map[string]Connector{
+		"plain":    NewMockConnector("plain", "Unversioned"),
+		"youtrack": &versionedMockConnector{MockConnector: NewMockConnector("youtrack", "Versioned"), version: 2},
+	})
+
+	if len(versions) != 1 || versions["youtrack"] != 2 {
+		t.Errorf("Expected only youtrack at version 2, got %v", versions)
+	}
+}
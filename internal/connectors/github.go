@@ -103,130 +103,161 @@ func (g *GitHubConnector) TestConnection(ctx context.Context) error {
 
 // GetActivities retrieves GitHub activities for the specified date
 func (g *GitHubConnector) GetActivities(ctx context.Context, date time.Time) ([]timeline.Activity, error) {
-	var activities []timeline.Activity
-
-	// HTTP client timeout is already configured in BaseConnector
-
 	// Enable debug logging if configured
 	if g.IsDebugMode() {
 		log.Printf("GitHub Debug: Fetching activities for date %s", date.Format("2006-01-02"))
 	}
 
+	from, to := localDayRange(date, date)
+	activities, err := g.fetchActivities(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// Limit the number of returned activities if max_items is set
+	return limitPerDay(activities, g.GetConfigInt(CommonConfigKeys.MaxItems)), nil
+}
+
+// GetActivitiesRange retrieves GitHub activities for every day from start to
+// end (inclusive) with one set of search queries for the whole range.
+func (g *GitHubConnector) GetActivitiesRange(ctx context.Context, start, end time.Time) ([]timeline.Activity, error) {
+	if g.IsDebugMode() {
+		log.Printf("GitHub Debug: Fetching activities from %s to %s", start.Format("2006-01-02"), end.Format("2006-01-02"))
+	}
+
+	from, to := localDayRange(start, end)
+	activities, err := g.fetchActivities(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// max_items applies per day, as it does for GetActivities
+	return limitPerDay(activities, g.GetConfigInt(CommonConfigKeys.MaxItems)), nil
+}
+
+// fetchActivities retrieves commits, issues and PRs in [start, end)
+func (g *GitHubConnector) fetchActivities(ctx context.Context, start, end time.Time) ([]timeline.Activity, error) {
+	var activities []timeline.Activity
+
+	// HTTP client timeout is already configured in BaseConnector
+
 	// Get commits
-	commits, err := g.getCommits(ctx, date)
+	commits, err := g.getCommits(ctx, start, end)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get commits: %w", err)
 	}
 	activities = append(activities, commits...)
 
 	// Get issues and PRs
-	issuesAndPRs, err := g.getIssuesAndPRs(ctx, date)
+	issuesAndPRs, err := g.getIssuesAndPRs(ctx, start, end)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get issues and PRs: %w", err)
 	}
 	activities = append(activities, issuesAndPRs...)
 
-	// Limit the number of returned activities if max_items is set
-	maxItems := g.GetConfigInt(CommonConfigKeys.MaxItems)
-	if maxItems > 0 && len(activities) > maxItems {
-		if g.IsDebugMode() {
-			log.Printf("GitHub Debug: Limiting activities from %d to %d", len(activities), maxItems)
-		}
-		activities = activities[:maxItems]
-	}
-
 	return activities, nil
 }
 
-// getCommits retrieves commits for the specified date
-func (g *GitHubConnector) getCommits(ctx context.Context, date time.Time) ([]timeline.Activity, error) {
+// getCommits retrieves commits authored in [start, end)
+func (g *GitHubConnector) getCommits(ctx context.Context, start, end time.Time) ([]timeline.Activity, error) {
 	username := g.GetConfigString("username")
 	token := g.GetConfigString("token")
 
-	// Format date for GitHub API (ISO 8601)
-	since := date.Format("2006-01-02T15:04:05Z")
-	until := date.Add(24 * time.Hour).Format("2006-01-02T15:04:05Z")
+	// Format window for GitHub API (ISO 8601)
+	since := start.UTC().Format("2006-01-02T15:04:05Z")
+	until := end.UTC().Format("2006-01-02T15:04:05Z")
 
-	url := fmt.Sprintf("https://api.github.com/search/commits?q=committer:%s+author-date:%s..%s&sort=author-date&order=desc",
-		username, since, until)
+	var activities []timeline.Activity
 
-	req, err := g.CreateBearerRequest(ctx, "GET", url, token)
-	if err != nil {
-		return nil, err
-	}
+	// The search API returns at most 100 items per page and 1000 in total
+	for page := 1; page <= 10; page++ {
+		url := fmt.Sprintf("https://api.github.com/search/commits?q=committer:%s+author-date:%s..%s&sort=author-date&order=desc&per_page=100&page=%d",
+			username, since, until, page)
 
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
+		req, err := g.CreateBearerRequest(ctx, "GET", url, token)
+		if err != nil {
+			return nil, err
+		}
 
-	resp, err := g.GetHTTPClient().Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+		req.Header.Set("Accept", "application/vnd.github.v3+json")
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("github commits API returned status %d", resp.StatusCode)
-	}
+		resp, err := g.GetHTTPClient().Do(req)
+		if err != nil {
+			return nil, err
+		}
 
-	var searchResult struct {
-		Items []struct {
-			SHA    string `json:"sha"`
-			Commit struct {
-				Message string `json:"message"`
-				Author  struct {
-					Date string `json:"date"`
-				} `json:"author"`
-			} `json:"commit"`
-			Repository struct {
-				Name     string `json:"name"`
-				FullName string `json:"full_name"`
-				HTMLURL  string `json:"html_url"`
-			} `json:"repository"`
-			HTMLURL string `json:"html_url"`
-		} `json:"items"`
-	}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("github commits API returned status %d", resp.StatusCode)
+		}
 
-	if err := json.NewDecoder(resp.Body).Decode(&searchResult); err != nil {
-		return nil, err
-	}
+		var searchResult struct {
+			Items []struct {
+				SHA    string `json:"sha"`
+				Commit struct {
+					Message string `json:"message"`
+					Author  struct {
+						Date string `json:"date"`
+					} `json:"author"`
+				} `json:"commit"`
+				Repository struct {
+					Name     string `json:"name"`
+					FullName string `json:"full_name"`
+					HTMLURL  string `json:"html_url"`
+				} `json:"repository"`
+				HTMLURL string `json:"html_url"`
+			} `json:"items"`
+		}
 
-	var activities []timeline.Activity
-	for _, item := range searchResult.Items {
-		commitTime, err := time.Parse(time.RFC3339, item.Commit.Author.Date)
+		err = json.NewDecoder(resp.Body).Decode(&searchResult)
+		resp.Body.Close()
 		if err != nil {
-			continue
+			return nil, err
 		}
 
-		activity := timeline.Activity{
-			ID:          fmt.Sprintf("github-commit-%s", item.SHA[:8]),
-			Type:        timeline.ActivityTypeGitCommit,
-			Title:       fmt.Sprintf("%s on %s", item.Commit.Message, item.Repository.FullName),
-			Description: fmt.Sprintf("Commit to %s", item.Repository.FullName),
-			Timestamp:   commitTime,
-			Source:      "github",
-			URL:         item.HTMLURL,
-			Metadata: map[string]string{
-				"repository": item.Repository.FullName,
-				"sha":        item.SHA,
-			},
+		for _, item := range searchResult.Items {
+			commitTime, err := time.Parse(time.RFC3339, item.Commit.Author.Date)
+			if err != nil {
+				continue
+			}
+
+			activity := timeline.Activity{
+				ID:          fmt.Sprintf("github-commit-%s", item.SHA[:8]),
+				Type:        timeline.ActivityTypeGitCommit,
+				Title:       fmt.Sprintf("%s on %s", item.Commit.Message, item.Repository.FullName),
+				Description: fmt.Sprintf("Commit to %s", item.Repository.FullName),
+				Timestamp:   commitTime,
+				Source:      "github",
+				URL:         item.HTMLURL,
+				Metadata: map[string]string{
+					"repository": item.Repository.FullName,
+					"sha":        item.SHA,
+				},
+			}
+
+			activities = append(activities, activity)
 		}
 
-		activities = append(activities, activity)
+		if len(searchResult.Items) < 100 {
+			break
+		}
 	}
 
 	return activities, nil
 }
 
-// getIssuesAndPRs retrieves issues and pull requests for the specified date
-func (g *GitHubConnector) getIssuesAndPRs(ctx context.Context, date time.Time) ([]timeline.Activity, error) {
+// getIssuesAndPRs retrieves issues and pull requests updated in [start, end)
+func (g *GitHubConnector) getIssuesAndPRs(ctx context.Context, start, end time.Time) ([]timeline.Activity, error) {
 	username := g.GetConfigString("username")
 	token := g.GetConfigString("token")
 
-	since := date.Format("2006-01-02")
-	until := date.Add(24 * time.Hour).Format("2006-01-02")
+	since := start.Format("2006-01-02")
+	until := end.Format("2006-01-02")
+	lastDay := end.Add(-time.Nanosecond).Format("2006-01-02")
 
-	// Search for issues and PRs created or updated on this date
+	// Search for issues and PRs created or updated in this window
 	queries := []string{
-		fmt.Sprintf("author:%s+created:%s", username, since),
+		fmt.Sprintf("author:%s+created:%s..%s", username, since, lastDay),
 		fmt.Sprintf("assignee:%s+updated:%s..%s", username, since, until),
 		fmt.Sprintf("mentions:%s+updated:%s..%s", username, since, until),
 	}
@@ -285,8 +316,8 @@ func (g *GitHubConnector) getIssuesAndPRs(ctx context.Context, date time.Time) (
 				continue
 			}
 
-			// Check if the update was on the target date
-			if !isSameDay(updatedTime, date) {
+			// Check if the update was within the requested window
+			if updatedTime.Before(start) || !updatedTime.Before(end) {
 				continue
 			}
 
@@ -319,10 +350,3 @@ func (g *GitHubConnector) getIssuesAndPRs(ctx context.Context, date time.Time) (
 
 	return allActivities, nil
 }
-
-// isSameDay checks if two times are on the same day
-func isSameDay(t1, t2 time.Time) bool {
-	y1, m1, d1 := t1.Date()
-	y2, m2, d2 := t2.Date()
-	return y1 == y2 && m1 == m2 && d1 == d2
-}
@@ -240,6 +240,25 @@ func (g *GitLabConnector) TestConnection(ctx context.Context) error {
 // GetActivities retrieves GitLab activities for the specified date
 // GetActivities gets activities from GitLab for the given date
 func (g *GitLabConnector) GetActivities(ctx context.Context, date time.Time) ([]timeline.Activity, error) {
+	// Use the user's local day, like GetActivitiesRange, so that day
+	// boundaries match the user's working day rather than UTC
+	return g.GetActivitiesRange(ctx, date, date)
+}
+
+// GetActivitiesRange retrieves GitLab activities for every day from start to
+// end (inclusive) while paginating through /api/v4/events only once.
+func (g *GitLabConnector) GetActivitiesRange(ctx context.Context, start, end time.Time) ([]timeline.Activity, error) {
+	from, to := localDayRange(start, end)
+	activities, err := g.fetchActivities(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	// max_items applies per day
+	return limitPerDay(activities, g.GetConfigInt(CommonConfigKeys.MaxItems)), nil
+}
+
+// fetchActivities retrieves GitLab activities created in [start, end)
+func (g *GitLabConnector) fetchActivities(ctx context.Context, start, end time.Time) ([]timeline.Activity, error) {
 	gitlabURL := g.GetConfigString("gitlab_url")
 	if gitlabURL == "" {
 		gitlabURL = "https://gitlab.com"
@@ -249,17 +268,18 @@ func (g *GitLabConnector) GetActivities(ctx context.Context, date time.Time) ([]
 	accessToken := g.GetConfigString("access_token")
 
 	if g.isDebugMode() {
-		log.Printf("GitLab Debug: Fetching events for user %s on date %s", username, date.Format("2006-01-02"))
+		log.Printf("GitLab Debug: Fetching events for user %s from %s to %s", username,
+			start.Format("2006-01-02 15:04"), end.Format("2006-01-02 15:04"))
 	}
 
 	// Get events from GitLab API
-	events, err := g.getEvents(ctx, gitlabURL, accessToken, date)
+	events, err := g.getEvents(ctx, gitlabURL, accessToken, start, end)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get events: %w", err)
 	}
 
 	if g.isDebugMode() {
-		log.Printf("GitLab Debug: Found %d events for date %s", len(events), date.Format("2006-01-02"))
+		log.Printf("GitLab Debug: Found %d events", len(events))
 	}
 
 	var allActivities []timeline.Activity
@@ -268,6 +288,14 @@ func (g *GitLabConnector) GetActivities(ctx context.Context, date time.Time) ([]
 	for _, event := range events {
 		activity := g.convertEventToActivity(event)
 		if activity != nil {
+			// Record the project path so activities can be grouped by
+			// repository like the GitHub and local git ones
+			if event.Project != nil && event.Project.PathWithNamespace != "" {
+				if activity.Metadata == nil {
+					activity.Metadata = make(map[string]string)
+				}
+				activity.Metadata["repository"] = event.Project.PathWithNamespace
+			}
 			allActivities = append(allActivities, *activity)
 		}
 	}
@@ -279,24 +307,23 @@ func (g *GitLabConnector) GetActivities(ctx context.Context, date time.Time) ([]
 	return allActivities, nil
 }
 
-// getEvents fetches user events from GitLab for the specified date with pagination
-func (g *GitLabConnector) getEvents(ctx context.Context, gitlabURL, accessToken string, date time.Time) ([]GitLabEvent, error) {
+// getEvents fetches user events from GitLab created in [start, end) with pagination
+func (g *GitLabConnector) getEvents(ctx context.Context, gitlabURL, accessToken string, start, end time.Time) ([]GitLabEvent, error) {
 	var allDayEvents []GitLabEvent
 	page := 1
 	perPage := 100
-	maxPages := 10 // Prevent infinite loops
-	// Normalize to the user's local day so that day boundaries match the
-	// user's working day rather than UTC.
-	targetDate := date.In(time.Local).Truncate(24 * time.Hour)
-	nextDay := targetDate.Add(24 * time.Hour)
+	// Prevent infinite loops, allowing more pages for longer ranges
+	days := int(end.Sub(start).Hours()/24) + 1
+	maxPages := 10 * days
 
-	if g.isDebugMode() {
-		log.Printf("GitLab Debug: Fetching events for date %s", date.Format("2006-01-02"))
-	}
+	// The after/before parameters are whole dates (exclusive) interpreted by
+	// GitLab in UTC, so widen them by a day and filter precisely below.
+	after := start.AddDate(0, 0, -1).Format("2006-01-02")
+	before := end.AddDate(0, 0, 1).Format("2006-01-02")
 
 	for page <= maxPages {
-		apiURL := fmt.Sprintf("%s/api/v4/events?per_page=%d&page=%d",
-			strings.TrimSuffix(gitlabURL, "/"), perPage, page)
+		apiURL := fmt.Sprintf("%s/api/v4/events?per_page=%d&page=%d&after=%s&before=%s",
+			strings.TrimSuffix(gitlabURL, "/"), perPage, page, after, before)
 
 		if g.isDebugMode() {
 			log.Printf("GitLab Debug: Fetching page %d from %s", page, apiURL)
@@ -351,26 +378,26 @@ func (g *GitLabConnector) getEvents(ctx context.Context, gitlabURL, accessToken
 				continue
 			}
 
-			// If event is from target date, include it
-			if eventTime.After(targetDate) && eventTime.Before(nextDay) {
+			// If event is within the requested window, include it
+			if !eventTime.Before(start) && eventTime.Before(end) {
 				allDayEvents = append(allDayEvents, event)
-			} else if eventTime.Before(targetDate) {
+			} else if eventTime.Before(start) {
 				// Events are typically in reverse chronological order
-				// If we find events older than our target date, we can stop
+				// If we find events older than our window, we can stop
 				tooOldEvents = true
 				break
 			}
 		}
 
 		if g.isDebugMode() {
-			log.Printf("GitLab Debug: Page %d: found %d events, %d from target date",
+			log.Printf("GitLab Debug: Page %d: found %d events, %d in requested window",
 				page, len(pageEvents), len(allDayEvents))
 		}
 
-		// Stop pagination if we've gone past our target date
+		// Stop pagination if we've gone past our window
 		if tooOldEvents {
 			if g.isDebugMode() {
-				log.Printf("GitLab Debug: Found events older than target date, stopping pagination")
+				log.Printf("GitLab Debug: Found events older than requested window, stopping pagination")
 			}
 			break
 		}
@@ -387,7 +414,7 @@ func (g *GitLabConnector) getEvents(ctx context.Context, gitlabURL, accessToken
 	}
 
 	if g.isDebugMode() {
-		log.Printf("GitLab Debug: Total events found for target date: %d", len(allDayEvents))
+		log.Printf("GitLab Debug: Total events found in requested window: %d", len(allDayEvents))
 	}
 
 	return allDayEvents, nil
@@ -0,0 +1,1140 @@
+package connectors
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ICalEvent represents a parsed iCal event
+type ICalEvent struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	StartTime   time.Time
+	EndTime     time.Time
+	Status      string
+	Organizer   string
+	URL         string
+
+	// AllDay is set for events with date-only (VALUE=DATE) start times.
+	// Their start and end are local midnights.
+	AllDay bool
+	// Attendees lists the invited participants and their responses
+	Attendees []ICalAttendee
+
+	// RRule is the recurrence rule of a recurring event
+	RRule string
+	// ExDates lists the start times of occurrences removed from the recurrence
+	ExDates []time.Time
+	// RecurrenceID is the original start time of the occurrence this event
+	// overrides, or of the occurrence it was expanded into
+	RecurrenceID time.Time
+
+	// startLocation is the time zone occurrences are computed in, so that
+	// they keep their wall clock time across daylight saving changes
+	startLocation *time.Location
+}
+
+// ICalAttendee is a participant listed in an ATTENDEE property
+type ICalAttendee struct {
+	Email string
+	Name  string
+	// PartStat is the participation status: NEEDS-ACTION, ACCEPTED,
+	// DECLINED, TENTATIVE or DELEGATED
+	PartStat string
+	Role     string
+}
+
+// Attendee returns the attendee entry matching one of the email addresses
+func (e ICalEvent) Attendee(emails []string) (ICalAttendee, bool) {
+	for _, attendee := range e.Attendees {
+		for _, email := range emails {
+			if strings.EqualFold(attendee.Email, email) {
+				return attendee, true
+			}
+		}
+	}
+	return ICalAttendee{}, false
+}
+
+// icalProperty is an unfolded content line: NAME;PARAM=value:VALUE
+type icalProperty struct {
+	Name string
+	// Params holds the parameters by upper-case name; quotes are removed
+	// and multiple values are joined with commas
+	Params map[string]string
+	Value  string
+}
+
+// icalComponent is a BEGIN/END block with its properties and sub-components
+type icalComponent struct {
+	Name       string
+	Properties []icalProperty
+	Components []*icalComponent
+}
+
+// property returns the value of the first property with the given name
+func (c *icalComponent) property(name string) string {
+	for _, prop := range c.Properties {
+		if prop.Name == name {
+			return prop.Value
+		}
+	}
+	return ""
+}
+
+// readICalComponents reads iCal data into a tree of components. Folded
+// lines (continuations starting with a space or tab) are joined first, as
+// described in RFC 5545 section 3.1.
+func readICalComponents(r io.Reader) (*icalComponent, int, error) {
+	// Use a larger buffer than the default 64KB to handle long iCal lines
+	// (e.g. base64-encoded DESCRIPTION or ATTACH values).
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	root := &icalComponent{}
+	stack := []*icalComponent{root}
+	var pending strings.Builder
+	lineCount := 0
+
+	flush := func() {
+		if pending.Len() == 0 {
+			return
+		}
+		prop, ok := parseContentLine(pending.String())
+		pending.Reset()
+		if !ok {
+			return
+		}
+
+		top := stack[len(stack)-1]
+		switch prop.Name {
+		case "BEGIN":
+			component := &icalComponent{Name: strings.ToUpper(prop.Value)}
+			top.Components = append(top.Components, component)
+			stack = append(stack, component)
+		case "END":
+			// Close up to the matching component, tolerating missing END lines
+			name := strings.ToUpper(prop.Value)
+			for i := len(stack) - 1; i > 0; i-- {
+				if stack[i].Name == name {
+					stack = stack[:i]
+					break
+				}
+			}
+		default:
+			top.Properties = append(top.Properties, prop)
+		}
+	}
+
+	for scanner.Scan() {
+		lineCount++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			pending.WriteString(line[1:])
+			continue
+		}
+		flush()
+		pending.WriteString(line)
+	}
+	flush()
+
+	if err := scanner.Err(); err != nil {
+		return nil, lineCount, err
+	}
+	return root, lineCount, nil
+}
+
+// parseContentLine splits a content line into name, parameters and value.
+// Parameter values may be quoted to contain ';', ':' or ','.
+func parseContentLine(line string) (icalProperty, bool) {
+	var prop icalProperty
+
+	i := strings.IndexAny(line, ";:")
+	if i <= 0 {
+		return prop, false
+	}
+	prop.Name = strings.ToUpper(strings.TrimSpace(line[:i]))
+
+	for i < len(line) && line[i] == ';' {
+		j := i + 1
+		eq := strings.IndexByte(line[j:], '=')
+		if eq < 0 {
+			return prop, false
+		}
+		paramName := strings.ToUpper(line[j : j+eq])
+		j += eq + 1
+
+		var values []string
+		for {
+			if j < len(line) && line[j] == '"' {
+				end := strings.IndexByte(line[j+1:], '"')
+				if end < 0 {
+					return prop, false
+				}
+				values = append(values, line[j+1:j+1+end])
+				j += end + 2
+			} else {
+				end := strings.IndexAny(line[j:], ",;:")
+				if end < 0 {
+					return prop, false
+				}
+				values = append(values, line[j:j+end])
+				j += end
+			}
+			if j < len(line) && line[j] == ',' {
+				j++
+				continue
+			}
+			break
+		}
+
+		if prop.Params == nil {
+			prop.Params = make(map[string]string)
+		}
+		prop.Params[paramName] = strings.Join(values, ",")
+		i = j
+	}
+
+	if i >= len(line) || line[i] != ':' {
+		return prop, false
+	}
+	prop.Value = line[i+1:]
+	return prop, true
+}
+
+// parseICalData parses iCal format data and extracts events. Recurring
+// events are expanded into their occurrences around the days from first to
+// last (inclusive).
+func (c *CalendarConnector) parseICalData(r io.Reader, first, last time.Time) ([]ICalEvent, error) {
+	if c.isDebugMode() {
+		log.Printf("Calendar Debug: Starting to parse iCal data")
+	}
+
+	root, lineCount, err := readICalComponents(r)
+	if err != nil {
+		if c.isDebugMode() {
+			log.Printf("Calendar Debug: Scanner error: %v", err)
+		}
+		return nil, err
+	}
+
+	// Events normally live in a VCALENDAR, but bare components are accepted
+	var eventComponents, timezoneComponents []*icalComponent
+	var collect func(components []*icalComponent)
+	collect = func(components []*icalComponent) {
+		for _, component := range components {
+			switch component.Name {
+			case "VCALENDAR":
+				collect(component.Components)
+			case "VTIMEZONE":
+				timezoneComponents = append(timezoneComponents, component)
+			case "VEVENT":
+				eventComponents = append(eventComponents, component)
+			}
+		}
+	}
+	collect(root.Components)
+
+	zones := c.parseTimezones(timezoneComponents)
+
+	var events []ICalEvent
+	for _, component := range eventComponents {
+		event := c.parseICalEvent(component, zones)
+		if event.Summary == "" {
+			if c.isDebugMode() {
+				log.Printf("Calendar Debug: Skipping event with empty summary")
+			}
+			continue
+		}
+		events = append(events, event)
+		if c.isDebugMode() {
+			log.Printf("Calendar Debug: Completed parsing event: %s (Start: %s)",
+				event.Summary, event.StartTime.Format("2006-01-02 15:04"))
+		}
+	}
+
+	if c.isDebugMode() {
+		log.Printf("Calendar Debug: Parsed %d lines, found %d events and %d time zones, extracted %d valid events",
+			lineCount, len(eventComponents), len(timezoneComponents), len(events))
+	}
+
+	return c.expandRecurrences(events, first, last), nil
+}
+
+// parseICalEvent builds an event from a VEVENT component
+func (c *CalendarConnector) parseICalEvent(component *icalComponent, zones map[string]*time.Location) ICalEvent {
+	var event ICalEvent
+	var duration time.Duration
+	hasDuration := false
+
+	for _, prop := range component.Properties {
+		switch prop.Name {
+		case "UID":
+			event.UID = prop.Value
+		case "SUMMARY":
+			event.Summary = unescapeICalValue(prop.Value)
+		case "DESCRIPTION":
+			event.Description = unescapeICalValue(prop.Value)
+		case "LOCATION":
+			event.Location = unescapeICalValue(prop.Value)
+		case "DTSTART":
+			if t, loc, allDay, err := c.parseICalTime(prop, zones); err == nil {
+				event.StartTime = t
+				event.startLocation = loc
+				event.AllDay = allDay
+			} else if c.isDebugMode() {
+				log.Printf("Calendar Debug: Failed to parse DTSTART '%s': %v", prop.Value, err)
+			}
+		case "DTEND":
+			if t, _, _, err := c.parseICalTime(prop, zones); err == nil {
+				event.EndTime = t
+			} else if c.isDebugMode() {
+				log.Printf("Calendar Debug: Failed to parse DTEND '%s': %v", prop.Value, err)
+			}
+		case "DURATION":
+			if d, err := parseICalDuration(prop.Value); err == nil {
+				duration = d
+				hasDuration = true
+			} else if c.isDebugMode() {
+				log.Printf("Calendar Debug: Failed to parse DURATION '%s': %v", prop.Value, err)
+			}
+		case "STATUS":
+			event.Status = strings.ToUpper(prop.Value)
+		case "ORGANIZER":
+			event.Organizer = icalEmail(prop.Value)
+		case "ATTENDEE":
+			partStat := strings.ToUpper(prop.Params["PARTSTAT"])
+			if partStat == "" {
+				partStat = "NEEDS-ACTION"
+			}
+			event.Attendees = append(event.Attendees, ICalAttendee{
+				Email:    icalEmail(prop.Value),
+				Name:     prop.Params["CN"],
+				PartStat: partStat,
+				Role:     strings.ToUpper(prop.Params["ROLE"]),
+			})
+		case "URL":
+			event.URL = prop.Value
+		case "RRULE":
+			event.RRule = prop.Value
+		case "EXDATE":
+			// EXDATE may list several comma-separated dates
+			for _, value := range strings.Split(prop.Value, ",") {
+				single := prop
+				single.Value = value
+				if t, _, _, err := c.parseICalTime(single, zones); err == nil {
+					event.ExDates = append(event.ExDates, t)
+				} else if c.isDebugMode() {
+					log.Printf("Calendar Debug: Failed to parse EXDATE '%s': %v", value, err)
+				}
+			}
+		case "RECURRENCE-ID":
+			if t, _, _, err := c.parseICalTime(prop, zones); err == nil {
+				event.RecurrenceID = t
+			} else if c.isDebugMode() {
+				log.Printf("Calendar Debug: Failed to parse RECURRENCE-ID '%s': %v", prop.Value, err)
+			}
+		}
+	}
+
+	// Without DTEND, the event lasts DURATION, a whole day for all-day
+	// events, or nothing (RFC 5545 section 3.6.1)
+	if event.EndTime.IsZero() && !event.StartTime.IsZero() {
+		switch {
+		case hasDuration:
+			event.EndTime = event.StartTime.Add(duration)
+		case event.AllDay:
+			event.EndTime = event.StartTime.AddDate(0, 0, 1)
+		default:
+			event.EndTime = event.StartTime
+		}
+	}
+
+	return event
+}
+
+// parseICalTime parses a DATE or DATE-TIME property value. It returns the
+// time in the local time zone, the time zone the value was expressed in and
+// whether it was a date without a time. Dates are local midnights.
+func (c *CalendarConnector) parseICalTime(prop icalProperty, zones map[string]*time.Location) (time.Time, *time.Location, bool, error) {
+	value := strings.TrimSpace(prop.Value)
+
+	if strings.EqualFold(prop.Params["VALUE"], "DATE") || len(value) == len("20060102") {
+		d, err := time.Parse("20060102", value)
+		if err != nil {
+			return time.Time{}, nil, false, fmt.Errorf("unable to parse date: %s", value)
+		}
+		return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.Local), time.Local, true, nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		if err != nil {
+			return time.Time{}, nil, false, fmt.Errorf("unable to parse date/time: %s", value)
+		}
+		return t.In(time.Local), time.UTC, false, nil
+	}
+
+	// Times without a time zone are floating: they happen at that wall
+	// clock time wherever the user is
+	loc := time.Local
+	if tzid := prop.Params["TZID"]; tzid != "" {
+		loc = c.resolveTimezone(tzid, zones)
+	}
+
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	if err != nil {
+		return time.Time{}, nil, false, fmt.Errorf("unable to parse date/time: %s", value)
+	}
+	return t.In(time.Local), loc, false, nil
+}
+
+// resolveTimezone returns the time zone for a TZID: an IANA name, or one
+// defined by a VTIMEZONE block of the calendar. Unknown zones fall back to
+// local time. Resolved zones are remembered in zones.
+func (c *CalendarConnector) resolveTimezone(tzid string, zones map[string]*time.Location) *time.Location {
+	if loc, ok := zones[tzid]; ok {
+		return loc
+	}
+
+	loc, err := time.LoadLocation(tzid)
+	if err != nil {
+		if c.isDebugMode() {
+			log.Printf("Calendar Debug: Unknown timezone '%s', falling back to local time: %v", tzid, err)
+		}
+		loc = time.Local
+	}
+	zones[tzid] = loc
+	return loc
+}
+
+// parseTimezones resolves the VTIMEZONE blocks of a calendar. IANA names
+// are loaded from the system time zone database; other TZIDs (such as
+// Outlook's "W. Europe Standard Time") are built from their definition.
+func (c *CalendarConnector) parseTimezones(components []*icalComponent) map[string]*time.Location {
+	zones := make(map[string]*time.Location)
+
+	for _, component := range components {
+		tzid := component.property("TZID")
+		if tzid == "" {
+			continue
+		}
+		if loc, err := time.LoadLocation(tzid); err == nil {
+			zones[tzid] = loc
+			continue
+		}
+
+		loc, err := vtimezoneLocation(tzid, component)
+		if err != nil {
+			if c.isDebugMode() {
+				log.Printf("Calendar Debug: Failed to build timezone '%s' from VTIMEZONE: %v", tzid, err)
+			}
+			continue
+		}
+		zones[tzid] = loc
+		if c.isDebugMode() {
+			log.Printf("Calendar Debug: Built timezone '%s' from VTIMEZONE", tzid)
+		}
+	}
+
+	return zones
+}
+
+// zoneType is an offset from UTC in effect for a period of time
+type zoneType struct {
+	offset int
+	isDST  bool
+	name   string
+}
+
+// zoneTransition is the instant (Unix seconds) a zone type takes effect
+type zoneTransition struct {
+	at   int64
+	from int
+	zone zoneType
+}
+
+// vtimezoneHorizon is the last year for which VTIMEZONE transitions are
+// computed; 32-bit TZif data can't go further
+var vtimezoneHorizon = time.Date(2038, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// vtimezoneLocation builds a time zone from the STANDARD and DAYLIGHT
+// observances of a VTIMEZONE
+func vtimezoneLocation(tzid string, component *icalComponent) (*time.Location, error) {
+	var transitions []zoneTransition
+
+	for _, observance := range component.Components {
+		if observance.Name != "STANDARD" && observance.Name != "DAYLIGHT" {
+			continue
+		}
+
+		from, err := parseUTCOffset(observance.property("TZOFFSETFROM"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid TZOFFSETFROM: %v", err)
+		}
+		to, err := parseUTCOffset(observance.property("TZOFFSETTO"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid TZOFFSETTO: %v", err)
+		}
+
+		// Onsets are wall clock times in the offset in effect before them
+		start, err := time.Parse("20060102T150405", observance.property("DTSTART"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid DTSTART: %v", err)
+		}
+
+		onsets := []time.Time{start}
+		for _, prop := range observance.Properties {
+			switch prop.Name {
+			case "RRULE":
+				rule, err := parseRecurrenceRule(prop.Value, time.UTC)
+				if err != nil {
+					return nil, fmt.Errorf("invalid RRULE: %v", err)
+				}
+				onsets = rule.occurrences(start, vtimezoneHorizon)
+			case "RDATE":
+				for _, value := range strings.Split(prop.Value, ",") {
+					if t, err := time.Parse("20060102T150405", value); err == nil {
+						onsets = append(onsets, t)
+					}
+				}
+			}
+		}
+
+		zone := zoneType{offset: to, isDST: observance.Name == "DAYLIGHT", name: observance.property("TZNAME")}
+		for _, onset := range onsets {
+			transitions = append(transitions, zoneTransition{at: onset.Unix() - int64(from), from: from, zone: zone})
+		}
+	}
+
+	if len(transitions) == 0 {
+		return nil, fmt.Errorf("no STANDARD or DAYLIGHT observance")
+	}
+	sort.Slice(transitions, func(i, j int) bool { return transitions[i].at < transitions[j].at })
+
+	// Before the first transition, the offset it changes from applies
+	initial := zoneType{offset: transitions[0].from, isDST: !transitions[0].zone.isDST}
+	for _, tr := range transitions {
+		if tr.zone.offset == initial.offset {
+			initial = tr.zone
+			break
+		}
+	}
+
+	return tzifLocation(tzid, initial, transitions)
+}
+
+// tzifLocation encodes zone transitions as version 1 TZif data (RFC 8536)
+// so that the standard library takes care of the conversions
+func tzifLocation(name string, initial zoneType, transitions []zoneTransition) (*time.Location, error) {
+	types := []zoneType{initial}
+	typeIndex := func(zone zoneType) byte {
+		for i, t := range types {
+			if t == zone {
+				return byte(i)
+			}
+		}
+		types = append(types, zone)
+		return byte(len(types) - 1)
+	}
+
+	var times []int32
+	var indexes []byte
+	for _, tr := range transitions {
+		if tr.at < math.MinInt32 || tr.at > math.MaxInt32 {
+			continue
+		}
+		if len(times) > 0 && int32(tr.at) == times[len(times)-1] {
+			continue
+		}
+		times = append(times, int32(tr.at))
+		indexes = append(indexes, typeIndex(tr.zone))
+	}
+
+	var abbreviations []byte
+	abbreviationIndex := make(map[string]int)
+	for _, t := range types {
+		if _, ok := abbreviationIndex[t.name]; !ok {
+			abbreviationIndex[t.name] = len(abbreviations)
+			abbreviations = append(abbreviations, t.name...)
+			abbreviations = append(abbreviations, 0)
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("TZif")
+	buf.Write(make([]byte, 16)) // version 1 and reserved bytes
+	for _, count := range []int{0, 0, 0, len(times), len(types), len(abbreviations)} {
+		binary.Write(&buf, binary.BigEndian, uint32(count))
+	}
+	binary.Write(&buf, binary.BigEndian, times)
+	buf.Write(indexes)
+	for _, t := range types {
+		binary.Write(&buf, binary.BigEndian, int32(t.offset))
+		isDST := byte(0)
+		if t.isDST {
+			isDST = 1
+		}
+		buf.WriteByte(isDST)
+		buf.WriteByte(byte(abbreviationIndex[t.name]))
+	}
+	buf.Write(abbreviations)
+
+	return time.LoadLocationFromTZData(name, buf.Bytes())
+}
+
+// parseUTCOffset parses a UTC offset such as +0100, -0530 or +013000 into
+// seconds
+func parseUTCOffset(value string) (int, error) {
+	if len(value) != 5 && len(value) != 7 {
+		return 0, fmt.Errorf("invalid offset %q", value)
+	}
+	sign := 1
+	switch value[0] {
+	case '+':
+	case '-':
+		sign = -1
+	default:
+		return 0, fmt.Errorf("invalid offset %q", value)
+	}
+
+	hours, err1 := strconv.Atoi(value[1:3])
+	minutes, err2 := strconv.Atoi(value[3:5])
+	seconds := 0
+	var err3 error
+	if len(value) == 7 {
+		seconds, err3 = strconv.Atoi(value[5:7])
+	}
+	if err1 != nil || err2 != nil || err3 != nil {
+		return 0, fmt.Errorf("invalid offset %q", value)
+	}
+	return sign * (hours*3600 + minutes*60 + seconds), nil
+}
+
+// parseICalDuration parses a DURATION value such as PT1H30M, P1D or -P1W
+func parseICalDuration(value string) (time.Duration, error) {
+	s := strings.ToUpper(strings.TrimSpace(value))
+	sign := time.Duration(1)
+	if strings.HasPrefix(s, "-") {
+		sign = -1
+		s = s[1:]
+	} else {
+		s = strings.TrimPrefix(s, "+")
+	}
+	if !strings.HasPrefix(s, "P") || len(s) < 3 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	var total time.Duration
+	inTime := false
+	number := ""
+	for _, r := range s[1:] {
+		switch {
+		case r >= '0' && r <= '9':
+			number += string(r)
+		case r == 'T':
+			inTime = true
+		default:
+			n, err := strconv.Atoi(number)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+			number = ""
+
+			switch {
+			case r == 'W' && !inTime:
+				total += time.Duration(n) * 7 * 24 * time.Hour
+			case r == 'D' && !inTime:
+				total += time.Duration(n) * 24 * time.Hour
+			case r == 'H' && inTime:
+				total += time.Duration(n) * time.Hour
+			case r == 'M' && inTime:
+				total += time.Duration(n) * time.Minute
+			case r == 'S' && inTime:
+				total += time.Duration(n) * time.Second
+			default:
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+		}
+	}
+	if number != "" {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	return sign * total, nil
+}
+
+// icalEmail strips the mailto: scheme from a CAL-ADDRESS value
+func icalEmail(value string) string {
+	if len(value) >= len("mailto:") && strings.EqualFold(value[:len("mailto:")], "mailto:") {
+		return value[len("mailto:"):]
+	}
+	return value
+}
+
+// unescapeICalValue unescapes iCal text values (RFC 5545 section 3.3.11)
+func unescapeICalValue(value string) string {
+	if !strings.Contains(value, "\\") {
+		return value
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i+1 == len(value) {
+			b.WriteByte(value[i])
+			continue
+		}
+		i++
+		switch value[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			// \\, \; and \, stand for the character itself
+			b.WriteByte(value[i])
+		}
+	}
+	return b.String()
+}
+
+// maxRecurrencePeriods bounds the expansion of a recurrence rule, so that a
+// rule that never produces an occurrence can't loop forever
+const maxRecurrencePeriods = 50000
+
+// expandRecurrences replaces recurring events by their occurrences starting
+// around the days from first to last. Occurrences listed in EXDATE are
+// dropped, and occurrences overridden by an event with a matching
+// RECURRENCE-ID are replaced by that event (or removed when it's cancelled).
+func (c *CalendarConnector) expandRecurrences(events []ICalEvent, first, last time.Time) []ICalEvent {
+	windowStart, windowEnd := eventWindow(first, last)
+
+	overridden := make(map[string][]time.Time)
+	for _, event := range events {
+		if event.RRule == "" && !event.RecurrenceID.IsZero() {
+			overridden[event.UID] = append(overridden[event.UID], event.RecurrenceID)
+		}
+	}
+
+	var result []ICalEvent
+	for _, event := range events {
+		if event.RRule == "" {
+			if !event.RecurrenceID.IsZero() && event.Status == "CANCELLED" {
+				if c.isDebugMode() {
+					log.Printf("Calendar Debug: Dropping cancelled occurrence of '%s' at %s",
+						event.Summary, event.RecurrenceID.Format("2006-01-02 15:04"))
+				}
+				continue
+			}
+			result = append(result, event)
+			continue
+		}
+
+		rule, err := parseRecurrenceRule(event.RRule, event.startLocation)
+		if err != nil {
+			// Keep the first occurrence rather than losing the event
+			if c.isDebugMode() {
+				log.Printf("Calendar Debug: Not expanding '%s' (RRULE '%s'): %v", event.Summary, event.RRule, err)
+			}
+			result = append(result, event)
+			continue
+		}
+
+		duration := event.EndTime.Sub(event.StartTime)
+		expanded := 0
+		for _, start := range rule.occurrences(event.StartTime, windowEnd) {
+			if start.Before(windowStart) || containsTime(event.ExDates, start) || containsTime(overridden[event.UID], start) {
+				continue
+			}
+
+			occurrence := event
+			occurrence.StartTime = start.In(event.StartTime.Location())
+			occurrence.EndTime = occurrence.StartTime.Add(duration)
+			occurrence.RecurrenceID = occurrence.StartTime
+			result = append(result, occurrence)
+			expanded++
+		}
+
+		if c.isDebugMode() {
+			log.Printf("Calendar Debug: Expanded '%s' (RRULE '%s') into %d occurrence(s) in range",
+				event.Summary, event.RRule, expanded)
+		}
+	}
+
+	return result
+}
+
+// eventWindow returns the time span in which events are looked for when
+// asked for the days from first to last. A day of margin on both sides
+// covers any offset between the time zone of the requested dates and the
+// local one; callers filter the exact days.
+func eventWindow(first, last time.Time) (time.Time, time.Time) {
+	return time.Date(first.Year(), first.Month(), first.Day()-1, 0, 0, 0, 0, time.Local),
+		time.Date(last.Year(), last.Month(), last.Day()+2, 0, 0, 0, 0, time.Local)
+}
+
+// containsTime reports whether list holds the instant t
+func containsTime(list []time.Time, t time.Time) bool {
+	for _, v := range list {
+		if v.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// recurrenceDay is a BYDAY entry such as MO, 2TU or -1FR
+type recurrenceDay struct {
+	ordinal int
+	weekday time.Weekday
+}
+
+// recurrenceRule is a parsed RRULE (RFC 5545 section 3.3.10)
+type recurrenceRule struct {
+	freq       string
+	interval   int
+	count      int
+	until      time.Time
+	byDay      []recurrenceDay
+	byMonthDay []int
+	byMonth    []int
+	bySetPos   []int
+	weekStart  time.Weekday
+	location   *time.Location
+}
+
+var icalWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// parseRecurrenceRule parses an RRULE value. Occurrences are computed in
+// loc; rule parts that can't be honoured are reported as errors.
+func parseRecurrenceRule(value string, loc *time.Location) (*recurrenceRule, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	rule := &recurrenceRule{interval: 1, weekStart: time.Monday, location: loc}
+
+	for _, part := range strings.Split(value, ";") {
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.freq = strings.ToUpper(val)
+		case "INTERVAL":
+			rule.interval, err = strconv.Atoi(val)
+			if err == nil && rule.interval < 1 {
+				err = fmt.Errorf("must be positive")
+			}
+		case "COUNT":
+			rule.count, err = strconv.Atoi(val)
+		case "UNTIL":
+			rule.until, err = parseRecurrenceUntil(val, loc)
+		case "BYDAY":
+			for _, v := range strings.Split(val, ",") {
+				v = strings.ToUpper(strings.TrimSpace(v))
+				if len(v) < 2 {
+					err = fmt.Errorf("invalid day %q", v)
+					break
+				}
+				weekday, known := icalWeekdays[v[len(v)-2:]]
+				if !known {
+					err = fmt.Errorf("invalid day %q", v)
+					break
+				}
+				day := recurrenceDay{weekday: weekday}
+				if prefix := v[:len(v)-2]; prefix != "" {
+					if day.ordinal, err = strconv.Atoi(prefix); err != nil {
+						break
+					}
+				}
+				rule.byDay = append(rule.byDay, day)
+			}
+		case "BYMONTHDAY":
+			rule.byMonthDay, err = parseIntList(val)
+		case "BYMONTH":
+			rule.byMonth, err = parseIntList(val)
+		case "BYSETPOS":
+			rule.bySetPos, err = parseIntList(val)
+		case "WKST":
+			weekday, known := icalWeekdays[strings.ToUpper(val)]
+			if !known {
+				err = fmt.Errorf("invalid day %q", val)
+			}
+			rule.weekStart = weekday
+		case "BYYEARDAY", "BYWEEKNO", "BYHOUR", "BYMINUTE", "BYSECOND":
+			return nil, fmt.Errorf("unsupported rule part %s", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", key, err)
+		}
+	}
+
+	switch rule.freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	case "":
+		return nil, fmt.Errorf("missing FREQ")
+	default:
+		return nil, fmt.Errorf("unsupported frequency %s", rule.freq)
+	}
+
+	return rule, nil
+}
+
+// parseRecurrenceUntil parses the UNTIL date of a rule. A date without a
+// time includes the whole day.
+func parseRecurrenceUntil(value string, loc *time.Location) (time.Time, error) {
+	if len(value) == len("20060102") {
+		t, err := time.Parse("20060102", value)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Date(t.Year(), t.Month(), t.Day(), 23, 59, 59, 0, loc), nil
+	}
+	if strings.HasSuffix(value, "Z") {
+		return time.Parse("20060102T150405Z", value)
+	}
+	return time.ParseInLocation("20060102T150405", value, loc)
+}
+
+// parseIntList parses a comma-separated list of integers
+func parseIntList(value string) ([]int, error) {
+	var result []int
+	for _, v := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, n)
+	}
+	return result, nil
+}
+
+// occurrences returns the start times of the occurrences of a rule beginning
+// at dtstart, up to (excluding) end. DTSTART is always the first occurrence
+// and counts towards COUNT, even when it doesn't match the rule (RFC 5545
+// section 3.8.5.3).
+func (r *recurrenceRule) occurrences(dtstart, end time.Time) []time.Time {
+	start := dtstart.In(r.location)
+	if !start.Before(end) {
+		return nil
+	}
+	result := []time.Time{start}
+	emitted := 1
+
+	for period := 0; period < maxRecurrencePeriods; period++ {
+		periodStart, days := r.periodDays(start, period)
+		if !time.Date(periodStart.Year(), periodStart.Month(), periodStart.Day(), 0, 0, 0, 0, r.location).Before(end) {
+			break
+		}
+		if len(r.bySetPos) > 0 {
+			days = selectPositions(days, r.bySetPos)
+		}
+
+		for _, day := range days {
+			t := time.Date(day.Year(), day.Month(), day.Day(), start.Hour(), start.Minute(), start.Second(), 0, r.location)
+			if !t.After(start) {
+				continue
+			}
+			if !t.Before(end) || (!r.until.IsZero() && t.After(r.until)) || (r.count > 0 && emitted >= r.count) {
+				return result
+			}
+			emitted++
+			result = append(result, t)
+		}
+	}
+
+	return result
+}
+
+// periodDays returns the first day of the given period of the rule (counted
+// from the one holding start, in steps of INTERVAL) and the candidate days
+// in it, in chronological order. Days are dates at midnight UTC.
+func (r *recurrenceRule) periodDays(start time.Time, period int) (time.Time, []time.Time) {
+	base := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch r.freq {
+	case "DAILY":
+		day := base.AddDate(0, 0, period*r.interval)
+		daysInMonth := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+		if (len(r.byMonth) > 0 && !containsInt(r.byMonth, int(day.Month()))) ||
+			(len(r.byMonthDay) > 0 && !r.matchesMonthDay(day.Day(), daysInMonth)) ||
+			(len(r.byDay) > 0 && !r.hasWeekday(day.Weekday())) {
+			return day, nil
+		}
+		return day, []time.Time{day}
+
+	case "WEEKLY":
+		offset := (int(base.Weekday()) - int(r.weekStart) + 7) % 7
+		weekBegin := base.AddDate(0, 0, -offset+7*period*r.interval)
+		var days []time.Time
+		for i := 0; i < 7; i++ {
+			day := weekBegin.AddDate(0, 0, i)
+			if len(r.byDay) > 0 {
+				if !r.hasWeekday(day.Weekday()) {
+					continue
+				}
+			} else if day.Weekday() != base.Weekday() {
+				continue
+			}
+			if len(r.byMonth) > 0 && !containsInt(r.byMonth, int(day.Month())) {
+				continue
+			}
+			days = append(days, day)
+		}
+		return weekBegin, days
+
+	case "MONTHLY":
+		month := time.Date(base.Year(), base.Month()+time.Month(period*r.interval), 1, 0, 0, 0, 0, time.UTC)
+		if len(r.byMonth) > 0 && !containsInt(r.byMonth, int(month.Month())) {
+			return month, nil
+		}
+		return month, r.monthDays(month.Year(), month.Month(), base.Day())
+
+	default: // YEARLY
+		yearBegin := time.Date(base.Year()+period*r.interval, 1, 1, 0, 0, 0, 0, time.UTC)
+		if len(r.byDay) > 0 && len(r.byMonth) == 0 && len(r.byMonthDay) == 0 {
+			// BYDAY ordinals count within the whole year
+			return yearBegin, r.weekdaysBetween(yearBegin, yearBegin.AddDate(1, 0, 0))
+		}
+
+		months := r.byMonth
+		if len(months) == 0 {
+			months = []int{int(base.Month())}
+		}
+		var days []time.Time
+		for m := 1; m <= 12; m++ {
+			if containsInt(months, m) {
+				days = append(days, r.monthDays(yearBegin.Year(), time.Month(m), base.Day())...)
+			}
+		}
+		return yearBegin, days
+	}
+}
+
+// monthDays returns the days of a month selected by BYDAY and BYMONTHDAY,
+// or defaultDay when neither is set. Months too short for defaultDay are
+// skipped, as RFC 5545 requires.
+func (r *recurrenceRule) monthDays(year int, month time.Month, defaultDay int) []time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	next := first.AddDate(0, 1, 0)
+	daysInMonth := next.AddDate(0, 0, -1).Day()
+
+	if len(r.byDay) == 0 && len(r.byMonthDay) == 0 {
+		if defaultDay > daysInMonth {
+			return nil
+		}
+		return []time.Time{time.Date(year, month, defaultDay, 0, 0, 0, 0, time.UTC)}
+	}
+
+	var days []time.Time
+	if len(r.byDay) > 0 {
+		for _, day := range r.weekdaysBetween(first, next) {
+			if len(r.byMonthDay) == 0 || r.matchesMonthDay(day.Day(), daysInMonth) {
+				days = append(days, day)
+			}
+		}
+		return days
+	}
+
+	for d := 1; d <= daysInMonth; d++ {
+		if r.matchesMonthDay(d, daysInMonth) {
+			days = append(days, time.Date(year, month, d, 0, 0, 0, 0, time.UTC))
+		}
+	}
+	return days
+}
+
+// weekdaysBetween returns the days from begin up to (excluding) end selected
+// by BYDAY, honouring ordinals such as 2TU or -1FR
+func (r *recurrenceRule) weekdaysBetween(begin, end time.Time) []time.Time {
+	byWeekday := make(map[time.Weekday][]time.Time)
+	for day := begin; day.Before(end); day = day.AddDate(0, 0, 1) {
+		byWeekday[day.Weekday()] = append(byWeekday[day.Weekday()], day)
+	}
+
+	selected := make(map[time.Time]bool)
+	for _, bd := range r.byDay {
+		candidates := byWeekday[bd.weekday]
+		switch {
+		case bd.ordinal == 0:
+			for _, day := range candidates {
+				selected[day] = true
+			}
+		case bd.ordinal > 0 && bd.ordinal <= len(candidates):
+			selected[candidates[bd.ordinal-1]] = true
+		case bd.ordinal < 0 && -bd.ordinal <= len(candidates):
+			selected[candidates[len(candidates)+bd.ordinal]] = true
+		}
+	}
+
+	days := make([]time.Time, 0, len(selected))
+	for day := range selected {
+		days = append(days, day)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	return days
+}
+
+// matchesMonthDay reports whether day is selected by BYMONTHDAY, where
+// negative values count from the end of the month
+func (r *recurrenceRule) matchesMonthDay(day, daysInMonth int) bool {
+	for _, md := range r.byMonthDay {
+		if md == day || (md < 0 && daysInMonth+md+1 == day) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasWeekday reports whether BYDAY lists the weekday, ignoring ordinals
+func (r *recurrenceRule) hasWeekday(weekday time.Weekday) bool {
+	for _, bd := range r.byDay {
+		if bd.weekday == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// selectPositions applies BYSETPOS to the candidate days of one period
+func selectPositions(days []time.Time, positions []int) []time.Time {
+	var result []time.Time
+	for i, day := range days {
+		for _, pos := range positions {
+			if pos == i+1 || pos == i-len(days) {
+				result = append(result, day)
+				break
+			}
+		}
+	}
+	return result
+}
+
+func containsInt(list []int, value int) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
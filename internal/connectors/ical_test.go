@@ -0,0 +1,380 @@
+package connectors
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// icalFeed wraps VEVENT blocks into a calendar
+func icalFeed(events ...string) string {
+	var b strings.Builder
+	b.WriteString("BEGIN:VCALENDAR\nVERSION:2.0\n")
+	for _, e := range events {
+		b.WriteString("BEGIN:VEVENT\n")
+		b.WriteString(strings.TrimSpace(e))
+		b.WriteString("\nEND:VEVENT\n")
+	}
+	b.WriteString("END:VCALENDAR\n")
+	return b.String()
+}
+
+func parseTestFeed(t *testing.T, feed, first, last string) []ICalEvent {
+	t.Helper()
+	firstDate, _ := time.Parse("2006-01-02", first)
+	lastDate, _ := time.Parse("2006-01-02", last)
+
+	events, err := NewCalendarConnector().parseICalData(strings.NewReader(feed), firstDate, lastDate)
+	if err != nil {
+		t.Fatalf("parseICalData failed: %v", err)
+	}
+	return events
+}
+
+func eventDates(events []ICalEvent) []string {
+	var dates []string
+	for _, e := range events {
+		dates = append(dates, e.StartTime.UTC().Format("2006-01-02"))
+	}
+	return dates
+}
+
+func expectDates(t *testing.T, events []ICalEvent, expected ...string) {
+	t.Helper()
+	got := eventDates(events)
+	if strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected occurrences on %v, got %v", expected, got)
+	}
+}
+
+func TestParseICalData_NonRecurring(t *testing.T) {
+	feed := icalFeed(`
+UID:single
+SUMMARY:Kickoff
+DTSTART:20240115T100000Z
+DTEND:20240115T110000Z`)
+
+	events := parseTestFeed(t, feed, "2024-01-15", "2024-01-15")
+	expectDates(t, events, "2024-01-15")
+	if !events[0].RecurrenceID.IsZero() {
+		t.Error("Expected no recurrence ID on a single event")
+	}
+}
+
+func TestParseICalData_WeeklyByDayCount(t *testing.T) {
+	feed := icalFeed(`
+UID:standup
+SUMMARY:Standup
+DTSTART:20240115T090000Z
+DTEND:20240115T091500Z
+RRULE:FREQ=WEEKLY;BYDAY=MO,WE,FR;COUNT=5`)
+
+	events := parseTestFeed(t, feed, "2024-01-01", "2024-02-29")
+	expectDates(t, events, "2024-01-15", "2024-01-17", "2024-01-19", "2024-01-22", "2024-01-24")
+
+	for _, e := range events {
+		if e.EndTime.Sub(e.StartTime) != 15*time.Minute {
+			t.Errorf("Expected each occurrence to last 15m, got %v", e.EndTime.Sub(e.StartTime))
+		}
+		if !e.RecurrenceID.Equal(e.StartTime) {
+			t.Errorf("Expected recurrence ID %v, got %v", e.StartTime, e.RecurrenceID)
+		}
+	}
+}
+
+func TestParseICalData_DTStartOutsideRule(t *testing.T) {
+	// DTSTART is a Monday: it is still the first occurrence and counts
+	// towards COUNT
+	feed := icalFeed(`
+UID:sync
+SUMMARY:Design sync
+DTSTART:20240115T140000Z
+DTEND:20240115T150000Z
+RRULE:FREQ=WEEKLY;BYDAY=TU,TH;COUNT=4`)
+
+	events := parseTestFeed(t, feed, "2024-01-01", "2024-02-29")
+	expectDates(t, events, "2024-01-15", "2024-01-16", "2024-01-18", "2024-01-23")
+}
+
+func TestParseICalData_DailyIntervalUntilExDate(t *testing.T) {
+	feed := icalFeed(`
+UID:daily
+SUMMARY:Focus time
+DTSTART:20240101T080000Z
+DTEND:20240101T100000Z
+RRULE:FREQ=DAILY;INTERVAL=2;UNTIL=20240109T080000Z
+EXDATE:20240103T080000Z,20240107T080000Z`)
+
+	events := parseTestFeed(t, feed, "2024-01-01", "2024-01-31")
+	expectDates(t, events, "2024-01-01", "2024-01-05", "2024-01-09")
+}
+
+func TestParseICalData_Monthly(t *testing.T) {
+	lastFriday := icalFeed(`
+UID:retro
+SUMMARY:Retro
+DTSTART:20240126T150000Z
+DTEND:20240126T160000Z
+RRULE:FREQ=MONTHLY;BYDAY=-1FR;COUNT=3`)
+	expectDates(t, parseTestFeed(t, lastFriday, "2024-01-01", "2024-12-31"), "2024-01-26", "2024-02-23", "2024-03-29")
+
+	// Months without a 31st are skipped
+	thirtyFirst := icalFeed(`
+UID:report
+SUMMARY:Monthly report
+DTSTART:20240131T120000Z
+DTEND:20240131T130000Z
+RRULE:FREQ=MONTHLY;COUNT=3`)
+	expectDates(t, parseTestFeed(t, thirtyFirst, "2024-01-01", "2024-12-31"), "2024-01-31", "2024-03-31", "2024-05-31")
+
+	// First weekday of the month
+	firstWeekday := icalFeed(`
+UID:planning
+SUMMARY:Planning
+DTSTART:20240603T090000Z
+DTEND:20240603T100000Z
+RRULE:FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=1;COUNT=2`)
+	expectDates(t, parseTestFeed(t, firstWeekday, "2024-01-01", "2024-12-31"), "2024-06-03", "2024-07-01")
+}
+
+func TestParseICalData_Yearly(t *testing.T) {
+	feed := icalFeed(`
+UID:review
+SUMMARY:Annual review
+DTSTART:20220301T100000Z
+DTEND:20220301T110000Z
+RRULE:FREQ=YEARLY`)
+
+	expectDates(t, parseTestFeed(t, feed, "2024-03-01", "2024-03-01"), "2024-03-01")
+}
+
+func TestParseICalData_RecurrenceIDOverrides(t *testing.T) {
+	feed := icalFeed(`
+UID:one-on-one
+SUMMARY:1:1
+DTSTART:20240115T140000Z
+DTEND:20240115T143000Z
+RRULE:FREQ=WEEKLY`, `
+UID:one-on-one
+SUMMARY:1:1 (moved)
+RECURRENCE-ID:20240122T140000Z
+DTSTART:20240123T160000Z
+DTEND:20240123T163000Z`, `
+UID:one-on-one
+SUMMARY:1:1
+RECURRENCE-ID:20240129T140000Z
+DTSTART:20240129T140000Z
+DTEND:20240129T143000Z
+STATUS:CANCELLED`)
+
+	events := parseTestFeed(t, feed, "2024-01-15", "2024-02-05")
+	expectDates(t, events, "2024-01-15", "2024-02-05", "2024-01-23")
+
+	moved := events[2]
+	if moved.Summary != "1:1 (moved)" || moved.StartTime.UTC().Hour() != 16 {
+		t.Errorf("Expected the override to replace the occurrence, got %+v", moved)
+	}
+}
+
+func TestParseICalData_TimezoneKeepsWallClock(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("Time zone data not available: %v", err)
+	}
+
+	// Daylight saving time starts in Berlin on 2024-03-31
+	feed := icalFeed(`
+UID:weekly
+SUMMARY:Weekly sync
+DTSTART;TZID=Europe/Berlin:20240325T090000
+DTEND;TZID=Europe/Berlin:20240325T093000
+RRULE:FREQ=WEEKLY;COUNT=2`)
+
+	events := parseTestFeed(t, feed, "2024-03-01", "2024-04-30")
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 occurrences, got %d", len(events))
+	}
+	for _, e := range events {
+		if local := e.StartTime.In(berlin); local.Hour() != 9 {
+			t.Errorf("Expected occurrence at 09:00 Berlin time, got %s", local.Format("2006-01-02 15:04 MST"))
+		}
+	}
+}
+
+func TestParseICalData_UnsupportedRuleKeepsFirstOccurrence(t *testing.T) {
+	feed := icalFeed(`
+UID:hourly
+SUMMARY:Hourly ping
+DTSTART:20240115T090000Z
+DTEND:20240115T091000Z
+RRULE:FREQ=HOURLY`)
+
+	expectDates(t, parseTestFeed(t, feed, "2024-01-15", "2024-01-15"), "2024-01-15")
+}
+
+func TestParseICalData_FoldedLinesAndParameters(t *testing.T) {
+	feed := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\n" +
+		"UID:folded\r\n" +
+		"SUMMARY:Quarterly business review with the\r\n  extended team\r\n" +
+		"DESCRIPTION:Agenda:\\n1. Numbers\\, targets\\;\r\n\t2. Hiring\r\n" +
+		"DTSTART:20240115T100000Z\r\n" +
+		"DURATION:PT1H30M\r\n" +
+		"ORGANIZER;CN=\"Doe, Jane\":mailto:jane@exam\r\n ple.com\r\n" +
+		"ATTENDEE;CN=\"Smith; John\";ROLE=REQ-PARTICIPANT;PARTSTAT=TENTATIVE:MAILTO:john@example.com\r\n" +
+		"ATTENDEE;CN=Room:mailto:room@example.com\r\n" +
+		"END:VEVENT\r\nEND:VCALENDAR\r\n"
+
+	events := parseTestFeed(t, feed, "2024-01-15", "2024-01-15")
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(events))
+	}
+	e := events[0]
+
+	if e.Summary != "Quarterly business review with the extended team" {
+		t.Errorf("Expected unfolded summary, got %q", e.Summary)
+	}
+	if e.Description != "Agenda:\n1. Numbers, targets;2. Hiring" {
+		t.Errorf("Expected unescaped description, got %q", e.Description)
+	}
+	if e.Organizer != "jane@example.com" {
+		t.Errorf("Expected unfolded organizer email, got %q", e.Organizer)
+	}
+	if e.EndTime.Sub(e.StartTime) != 90*time.Minute {
+		t.Errorf("Expected DURATION to set the end, got %v", e.EndTime.Sub(e.StartTime))
+	}
+
+	if len(e.Attendees) != 2 {
+		t.Fatalf("Expected 2 attendees, got %d", len(e.Attendees))
+	}
+	john := e.Attendees[0]
+	if john.Email != "john@example.com" || john.Name != "Smith; John" || john.PartStat != "TENTATIVE" || john.Role != "REQ-PARTICIPANT" {
+		t.Errorf("Unexpected attendee: %+v", john)
+	}
+	if e.Attendees[1].PartStat != "NEEDS-ACTION" {
+		t.Errorf("Expected default participation status NEEDS-ACTION, got %q", e.Attendees[1].PartStat)
+	}
+
+	if a, ok := e.Attendee([]string{"JOHN@example.com"}); !ok || a.Name != "Smith; John" {
+		t.Errorf("Expected attendee lookup to ignore case, got %+v (%v)", a, ok)
+	}
+}
+
+func TestParseICalData_AllDay(t *testing.T) {
+	feed := icalFeed(`
+UID:offsite
+SUMMARY:Team offsite
+DTSTART;VALUE=DATE:20240115
+DTEND;VALUE=DATE:20240117`, `
+UID:holiday
+SUMMARY:Holiday
+DTSTART;VALUE=DATE:20240116`)
+
+	events := parseTestFeed(t, feed, "2024-01-15", "2024-01-16")
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(events))
+	}
+
+	offsite := events[0]
+	if !offsite.AllDay {
+		t.Error("Expected VALUE=DATE event to be all-day")
+	}
+	if offsite.StartTime.Location() != time.Local || offsite.StartTime.Hour() != 0 || offsite.StartTime.Day() != 15 {
+		t.Errorf("Expected local midnight on the 15th, got %v", offsite.StartTime)
+	}
+	if offsite.EndTime.Day() != 17 {
+		t.Errorf("Expected exclusive end on the 17th, got %v", offsite.EndTime)
+	}
+
+	if holiday := events[1]; !holiday.EndTime.Equal(holiday.StartTime.AddDate(0, 0, 1)) {
+		t.Errorf("Expected all-day event without DTEND to last one day, got %v - %v", holiday.StartTime, holiday.EndTime)
+	}
+}
+
+func TestParseICalData_CustomVTimezone(t *testing.T) {
+	feed := `BEGIN:VCALENDAR
+BEGIN:VTIMEZONE
+TZID:W. Europe Standard Time
+BEGIN:STANDARD
+DTSTART:16010101T030000
+TZOFFSETFROM:+0200
+TZOFFSETTO:+0100
+TZNAME:CET
+RRULE:FREQ=YEARLY;BYDAY=-1SU;BYMONTH=10
+END:STANDARD
+BEGIN:DAYLIGHT
+DTSTART:16010101T020000
+TZOFFSETFROM:+0100
+TZOFFSETTO:+0200
+TZNAME:CEST
+RRULE:FREQ=YEARLY;BYDAY=-1SU;BYMONTH=3
+END:DAYLIGHT
+END:VTIMEZONE
+BEGIN:VEVENT
+UID:winter
+SUMMARY:Winter meeting
+DTSTART;TZID=W. Europe Standard Time:20240115T090000
+DTEND;TZID=W. Europe Standard Time:20240115T100000
+END:VEVENT
+BEGIN:VEVENT
+UID:summer
+SUMMARY:Summer meeting
+DTSTART;TZID="W. Europe Standard Time":20240715T090000
+DTEND;TZID="W. Europe Standard Time":20240715T100000
+END:VEVENT
+END:VCALENDAR
+`
+
+	winter := parseTestFeed(t, feed, "2024-01-15", "2024-01-15")
+	if len(winter) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(winter))
+	}
+	if got := winter[0].StartTime.UTC().Format("15:04"); got != "08:00" {
+		t.Errorf("Expected 09:00 CET to be 08:00 UTC, got %s", got)
+	}
+	if got := winter[1].StartTime.UTC().Format("15:04"); got != "07:00" {
+		t.Errorf("Expected 09:00 CEST to be 07:00 UTC, got %s", got)
+	}
+}
+
+func TestParseICalData_FloatingTimeIsLocal(t *testing.T) {
+	feed := icalFeed(`
+UID:floating
+SUMMARY:Lunch
+DTSTART:20240115T120000
+DTEND:20240115T130000`)
+
+	events := parseTestFeed(t, feed, "2024-01-15", "2024-01-15")
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(events))
+	}
+	if start := events[0].StartTime; start.Location() != time.Local || start.Hour() != 12 {
+		t.Errorf("Expected 12:00 local time, got %v", start)
+	}
+}
+
+func TestParseICalDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		valid    bool
+	}{
+		{"PT1H30M", 90 * time.Minute, true},
+		{"P1D", 24 * time.Hour, true},
+		{"P1W", 7 * 24 * time.Hour, true},
+		{"P1DT2H", 26 * time.Hour, true},
+		{"-PT15M", -15 * time.Minute, true},
+		{"PT", 0, false},
+		{"1H", 0, false},
+		{"P1H", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, err := parseICalDuration(tt.input)
+		if tt.valid && (err != nil || got != tt.expected) {
+			t.Errorf("parseICalDuration(%q) = %v, %v; expected %v", tt.input, got, err, tt.expected)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("parseICalDuration(%q) expected an error, got %v", tt.input, got)
+		}
+	}
+}
@@ -0,0 +1,709 @@
+package connectors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arkeo/arkeo/internal/timeline"
+)
+
+// jiraPageSize is the number of issues requested per search page
+const jiraPageSize = 50
+
+// jiraTimeLayout is the timestamp format of the Jira REST API
+const jiraTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// JiraConnector implements the Connector interface for Jira Cloud and Jira
+// Server/Data Center
+type JiraConnector struct {
+	*BaseConnector
+}
+
+// NewJiraConnector creates a new Jira connector
+func NewJiraConnector() *JiraConnector {
+	return &JiraConnector{
+		BaseConnector: NewBaseConnector(
+			"jira",
+			"Fetches issue transitions, assignments, comments and worklogs from Jira",
+		),
+	}
+}
+
+// GetRequiredConfig returns the required configuration for Jira
+func (j *JiraConnector) GetRequiredConfig() []ConfigField {
+	requiredFields := []ConfigField{
+		{
+			Key:         "base_url",
+			Type:        "string",
+			Required:    true,
+			Description: "Jira base URL (e.g., https://mycompany.atlassian.net/)",
+		},
+		{
+			Key:         "token",
+			Type:        "secret",
+			Required:    true,
+			Description: "Jira Cloud API token, or personal access token for Jira Server/Data Center",
+		},
+		{
+			Key:         "email",
+			Type:        "string",
+			Required:    false,
+			Description: "Account email for Jira Cloud (basic auth with the API token); leave empty to use the token as a bearer token",
+		},
+		{
+			Key:         "username",
+			Type:        "string",
+			Required:    false,
+			Description: "Account ID (Cloud) or username (Server) to filter activities for (defaults to token owner)",
+		},
+		{
+			Key:         "jql",
+			Type:        "string",
+			Required:    false,
+			Description: "Additional JQL to narrow the searched issues (e.g., project in (ACME, OPS))",
+		},
+		{
+			Key:         "include_issues",
+			Type:        "bool",
+			Required:    false,
+			Description: "Include issue creation, transitions and assignments",
+			Default:     true,
+		},
+		{
+			Key:         "include_comments",
+			Type:        "bool",
+			Required:    false,
+			Description: "Include comment activities",
+			Default:     true,
+		},
+		{
+			Key:         "include_worklogs",
+			Type:        "bool",
+			Required:    false,
+			Description: "Include worklogs (time tracking entries)",
+			Default:     true,
+		},
+	}
+
+	// Merge with common fields
+	return MergeConfigFields(requiredFields)
+}
+
+// isDebugMode checks if debug logging is enabled
+func (j *JiraConnector) isDebugMode() bool {
+	return j.BaseConnector.IsDebugMode()
+}
+
+// ValidateConfig validates the Jira configuration
+func (j *JiraConnector) ValidateConfig(config map[string]interface{}) error {
+	baseURL, ok := config["base_url"].(string)
+	if !ok || baseURL == "" {
+		return fmt.Errorf("jira base_url is required")
+	}
+
+	parsedURL, err := url.Parse(baseURL)
+	if err != nil {
+		return fmt.Errorf("invalid Jira base_url format: %v", err)
+	}
+	if (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		return fmt.Errorf("jira base_url must be an http:// or https:// URL with a host")
+	}
+
+	token, ok := config["token"].(string)
+	if !ok || token == "" {
+		return fmt.Errorf("jira token is required")
+	}
+
+	return nil
+}
+
+// Configure validates the configuration then delegates to
+// BaseConnector.Configure so common defaults are merged in. base_url is
+// normalized to end with a single "/".
+func (j *JiraConnector) Configure(config map[string]interface{}) error {
+	if err := j.ValidateConfig(config); err != nil {
+		return err
+	}
+	if err := j.BaseConnector.Configure(config); err != nil {
+		return err
+	}
+	if baseURL, ok := j.config["base_url"].(string); ok && baseURL != "" {
+		j.config["base_url"] = strings.TrimSuffix(baseURL, "/") + "/"
+	}
+	return nil
+}
+
+// TestConnection tests the Jira connection
+func (j *JiraConnector) TestConnection(ctx context.Context) error {
+	if j.GetConfigString("base_url") == "" || j.GetConfigString("token") == "" {
+		return fmt.Errorf("base_url and token must be configured")
+	}
+
+	if j.isDebugMode() {
+		log.Printf("Jira Debug: Testing connection to %s", j.GetConfigString("base_url"))
+	}
+
+	user, err := j.getCurrentUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	if j.isDebugMode() {
+		log.Printf("Jira Debug: Authenticated as %s", user.DisplayName)
+	}
+
+	return nil
+}
+
+// GetActivities retrieves Jira activities for the specified date
+func (j *JiraConnector) GetActivities(ctx context.Context, date time.Time) ([]timeline.Activity, error) {
+	if j.isDebugMode() {
+		log.Printf("Jira Debug: Fetching activities for date %s", date.Format("2006-01-02"))
+	}
+
+	start, end := localDayRange(date, date)
+	activities, err := j.fetchActivities(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return limitPerDay(activities, j.GetConfigInt(CommonConfigKeys.MaxItems)), nil
+}
+
+// GetActivitiesRange retrieves Jira activities for every day from start to
+// end (inclusive) with a single issue search.
+func (j *JiraConnector) GetActivitiesRange(ctx context.Context, start, end time.Time) ([]timeline.Activity, error) {
+	if j.isDebugMode() {
+		log.Printf("Jira Debug: Fetching activities from %s to %s", start.Format("2006-01-02"), end.Format("2006-01-02"))
+	}
+
+	from, to := localDayRange(start, end)
+	activities, err := j.fetchActivities(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// max_items applies per day, as it does for GetActivities
+	return limitPerDay(activities, j.GetConfigInt(CommonConfigKeys.MaxItems)), nil
+}
+
+// jiraUser is a Jira user as returned by /myself and in changelogs, comments
+// and worklogs. Cloud identifies users by accountId, Server by name and key.
+type jiraUser struct {
+	AccountID    string `json:"accountId"`
+	Name         string `json:"name"`
+	Key          string `json:"key"`
+	EmailAddress string `json:"emailAddress"`
+	DisplayName  string `json:"displayName"`
+}
+
+// matches reports whether the user is identified by id
+func (u *jiraUser) matches(id string) bool {
+	if u == nil || id == "" {
+		return false
+	}
+	return u.AccountID == id || u.Name == id || u.Key == id || strings.EqualFold(u.EmailAddress, id)
+}
+
+// jiraIssue is an issue returned by the search API with its changelog
+type jiraIssue struct {
+	ID     string `json:"id"`
+	Key    string `json:"key"`
+	Fields struct {
+		Summary string    `json:"summary"`
+		Created string    `json:"created"`
+		Creator *jiraUser `json:"creator"`
+		Project struct {
+			Key  string `json:"key"`
+			Name string `json:"name"`
+		} `json:"project"`
+		IssueType struct {
+			Name string `json:"name"`
+		} `json:"issuetype"`
+	} `json:"fields"`
+	Changelog struct {
+		Histories []jiraHistory `json:"histories"`
+	} `json:"changelog"`
+}
+
+// jiraHistory is one changelog entry, grouping the fields changed at once
+type jiraHistory struct {
+	ID      string    `json:"id"`
+	Author  *jiraUser `json:"author"`
+	Created string    `json:"created"`
+	Items   []struct {
+		Field      string `json:"field"`
+		FromString string `json:"fromString"`
+		ToString   string `json:"toString"`
+	} `json:"items"`
+}
+
+// jiraSearchResponse covers both the classic /search endpoint (startAt and
+// total) and Cloud's /search/jql endpoint (nextPageToken and isLast)
+type jiraSearchResponse struct {
+	StartAt       int         `json:"startAt"`
+	Total         int         `json:"total"`
+	NextPageToken string      `json:"nextPageToken"`
+	IsLast        bool        `json:"isLast"`
+	Issues        []jiraIssue `json:"issues"`
+}
+
+// jiraComment is an issue comment. Body is plain text (API version 2).
+type jiraComment struct {
+	ID      string    `json:"id"`
+	Author  *jiraUser `json:"author"`
+	Body    string    `json:"body"`
+	Created string    `json:"created"`
+}
+
+// jiraWorklog is a time tracking entry of an issue
+type jiraWorklog struct {
+	ID               string    `json:"id"`
+	Author           *jiraUser `json:"author"`
+	Comment          string    `json:"comment"`
+	Started          string    `json:"started"`
+	TimeSpentSeconds int       `json:"timeSpentSeconds"`
+}
+
+// fetchActivities retrieves the user's Jira activities in [start, end)
+func (j *JiraConnector) fetchActivities(ctx context.Context, start, end time.Time) ([]timeline.Activity, error) {
+	userID := j.GetConfigString("username")
+	if userID == "" {
+		if j.isDebugMode() {
+			log.Printf("Jira Debug: No username specified, fetching current user")
+		}
+		user, err := j.getCurrentUser(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current user: %w", err)
+		}
+		userID = user.AccountID
+		if userID == "" {
+			userID = user.Name
+		}
+		if j.isDebugMode() {
+			log.Printf("Jira Debug: Using user: %s (%s)", userID, user.DisplayName)
+		}
+	}
+
+	issues, err := j.searchIssues(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search issues: %w", err)
+	}
+
+	if j.isDebugMode() {
+		log.Printf("Jira Debug: Found %d issues updated in range", len(issues))
+	}
+
+	var activities []timeline.Activity
+	for _, issue := range issues {
+		if j.GetConfigBool("include_issues") {
+			activities = append(activities, j.convertIssueChanges(issue, userID, start, end)...)
+		}
+
+		if j.GetConfigBool("include_comments") {
+			comments, err := j.getComments(ctx, issue.Key)
+			if err != nil {
+				if j.isDebugMode() {
+					log.Printf("Jira Debug: Failed to fetch comments of %s: %v", issue.Key, err)
+				}
+			} else {
+				for _, comment := range comments {
+					if activity := j.convertComment(issue, comment, userID, start, end); activity != nil {
+						activities = append(activities, *activity)
+					}
+				}
+			}
+		}
+
+		if j.GetConfigBool("include_worklogs") {
+			worklogs, err := j.getWorklogs(ctx, issue.Key)
+			if err != nil {
+				if j.isDebugMode() {
+					log.Printf("Jira Debug: Failed to fetch worklogs of %s: %v", issue.Key, err)
+				}
+			} else {
+				for _, worklog := range worklogs {
+					if activity := j.convertWorklog(issue, worklog, userID, start, end); activity != nil {
+						activities = append(activities, *activity)
+					}
+				}
+			}
+		}
+	}
+
+	if j.isDebugMode() {
+		log.Printf("Jira Debug: Total activities found: %d", len(activities))
+	}
+
+	return activities, nil
+}
+
+// buildJQL returns the JQL selecting the issues the user touched that were
+// updated in [start, end). JQL dates are interpreted in the Jira user's time
+// zone, so the query is widened by a day on both sides and entries are
+// filtered by timestamp and author.
+func (j *JiraConnector) buildJQL(start, end time.Time) string {
+	user := "currentUser()"
+	if username := j.GetConfigString("username"); username != "" {
+		user = jqlString(username)
+	}
+	jql := fmt.Sprintf(`updatedDate >= "%s" AND updatedDate < "%s"`,
+		start.AddDate(0, 0, -1).Format("2006-01-02"), end.AddDate(0, 0, 1).Format("2006-01-02"))
+	jql += fmt.Sprintf(" AND (assignee = %[1]s OR reporter = %[1]s OR worklogAuthor = %[1]s OR issue in updatedBy(%[1]s))", user)
+	if extra := strings.TrimSpace(j.GetConfigString("jql")); extra != "" {
+		jql += " AND (" + extra + ")"
+	}
+	return jql + " ORDER BY updated DESC"
+}
+
+// jqlString quotes a value for JQL
+func jqlString(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+// searchIssues returns the issues updated in [start, end) with their
+// changelog. Jira Cloud's /search/jql endpoint is tried first; Jira Server
+// only has the classic /search endpoint.
+func (j *JiraConnector) searchIssues(ctx context.Context, start, end time.Time) ([]jiraIssue, error) {
+	params := url.Values{}
+	params.Set("jql", j.buildJQL(start, end))
+	params.Set("fields", "summary,created,creator,project,issuetype")
+	params.Set("expand", "changelog")
+	params.Set("maxResults", strconv.Itoa(jiraPageSize))
+
+	if j.isDebugMode() {
+		log.Printf("Jira Debug: Searching issues with JQL: %s", params.Get("jql"))
+	}
+
+	var issues []jiraIssue
+	endpoint := "rest/api/2/search/jql"
+	for page := 0; ; page++ {
+		var result jiraSearchResponse
+		status, err := j.getJSON(ctx, endpoint+"?"+params.Encode(), &result)
+		if status == http.StatusNotFound && endpoint == "rest/api/2/search/jql" && page == 0 {
+			if j.isDebugMode() {
+				log.Printf("Jira Debug: /search/jql not available, falling back to /search")
+			}
+			endpoint = "rest/api/2/search"
+			params.Set("startAt", "0")
+			page--
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, result.Issues...)
+
+		if endpoint == "rest/api/2/search/jql" {
+			if result.IsLast || result.NextPageToken == "" {
+				break
+			}
+			params.Set("nextPageToken", result.NextPageToken)
+		} else {
+			next := result.StartAt + len(result.Issues)
+			if len(result.Issues) == 0 || next >= result.Total {
+				break
+			}
+			params.Set("startAt", strconv.Itoa(next))
+		}
+	}
+
+	return issues, nil
+}
+
+// getComments returns the comments of an issue
+func (j *JiraConnector) getComments(ctx context.Context, issueKey string) ([]jiraComment, error) {
+	var comments []jiraComment
+	for startAt := 0; ; {
+		var result struct {
+			StartAt  int           `json:"startAt"`
+			Total    int           `json:"total"`
+			Comments []jiraComment `json:"comments"`
+		}
+		path := fmt.Sprintf("rest/api/2/issue/%s/comment?startAt=%d&maxResults=100", url.PathEscape(issueKey), startAt)
+		if _, err := j.getJSON(ctx, path, &result); err != nil {
+			return nil, err
+		}
+		comments = append(comments, result.Comments...)
+
+		startAt = result.StartAt + len(result.Comments)
+		if len(result.Comments) == 0 || startAt >= result.Total {
+			return comments, nil
+		}
+	}
+}
+
+// getWorklogs returns the worklogs of an issue
+func (j *JiraConnector) getWorklogs(ctx context.Context, issueKey string) ([]jiraWorklog, error) {
+	var worklogs []jiraWorklog
+	for startAt := 0; ; {
+		var result struct {
+			StartAt  int           `json:"startAt"`
+			Total    int           `json:"total"`
+			Worklogs []jiraWorklog `json:"worklogs"`
+		}
+		path := fmt.Sprintf("rest/api/2/issue/%s/worklog?startAt=%d&maxResults=100", url.PathEscape(issueKey), startAt)
+		if _, err := j.getJSON(ctx, path, &result); err != nil {
+			return nil, err
+		}
+		worklogs = append(worklogs, result.Worklogs...)
+
+		startAt = result.StartAt + len(result.Worklogs)
+		if len(result.Worklogs) == 0 || startAt >= result.Total {
+			return worklogs, nil
+		}
+	}
+}
+
+// getCurrentUser fetches the user owning the credentials
+func (j *JiraConnector) getCurrentUser(ctx context.Context) (*jiraUser, error) {
+	var user jiraUser
+	status, err := j.getJSON(ctx, "rest/api/2/myself", &user)
+	if status == http.StatusUnauthorized || status == http.StatusForbidden {
+		return nil, fmt.Errorf("invalid Jira credentials or insufficient permissions")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// newRequest creates an authenticated API request. Jira Cloud uses basic
+// auth with the account email and an API token; Jira Server/Data Center
+// uses personal access tokens as bearer tokens.
+func (j *JiraConnector) newRequest(ctx context.Context, path string) (*http.Request, error) {
+	apiURL := j.GetConfigString("base_url") + path
+	token := j.GetConfigString("token")
+
+	var req *http.Request
+	var err error
+	if email := j.GetConfigString("email"); email != "" {
+		req, err = j.CreateRequest(ctx, "GET", apiURL, nil)
+		if err == nil {
+			req.SetBasicAuth(email, token)
+		}
+	} else {
+		req, err = j.CreateBearerRequest(ctx, "GET", apiURL, token)
+	}
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	return req, nil
+}
+
+// getJSON performs a GET request and decodes the JSON response into v. It
+// returns the HTTP status code alongside any error.
+func (j *JiraConnector) getJSON(ctx context.Context, path string, v interface{}) (int, error) {
+	req, err := j.newRequest(ctx, path)
+	if err != nil {
+		return 0, err
+	}
+
+	if j.isDebugMode() {
+		log.Printf("Jira Debug: GET %s", req.URL.String())
+	}
+
+	resp, err := j.GetHTTPClient().Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		if j.isDebugMode() {
+			log.Printf("Jira Debug: %s returned status %d: %s", path, resp.StatusCode, string(body))
+		}
+		return resp.StatusCode, fmt.Errorf("jira API returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		if j.isDebugMode() {
+			log.Printf("Jira Debug: Failed to decode JSON response: %v", err)
+		}
+		return resp.StatusCode, err
+	}
+	return resp.StatusCode, nil
+}
+
+// parseJiraTime parses a Jira timestamp, returning ok=false when it is
+// malformed or outside [start, end)
+func parseJiraTime(value string, start, end time.Time) (time.Time, bool) {
+	t, err := time.Parse(jiraTimeLayout, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if t.Before(start) || !t.Before(end) {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// issueRef returns "KEY-1: Summary" like YouTrack activity titles
+func (issue jiraIssue) issueRef() string {
+	if issue.Fields.Summary == "" {
+		return issue.Key
+	}
+	return fmt.Sprintf("%s: %s", issue.Key, issue.Fields.Summary)
+}
+
+// newActivity creates an activity for an issue with the common metadata
+func (j *JiraConnector) newActivity(issue jiraIssue, id, category, title, description string, timestamp time.Time, author *jiraUser) timeline.Activity {
+	metadata := map[string]string{
+		"category":  category,
+		"issue_id":  issue.ID,
+		"issue_key": issue.Key,
+	}
+	if issue.Fields.Summary != "" {
+		metadata["issue_summary"] = issue.Fields.Summary
+	}
+	if issue.Fields.Project.Name != "" {
+		metadata["project"] = issue.Fields.Project.Name
+	}
+	if issue.Fields.Project.Key != "" {
+		metadata["project_key"] = issue.Fields.Project.Key
+	}
+	if issue.Fields.IssueType.Name != "" {
+		metadata["issue_type"] = issue.Fields.IssueType.Name
+	}
+	if author != nil {
+		metadata["author"] = author.DisplayName
+	}
+
+	return timeline.Activity{
+		ID:          "jira-" + id,
+		Type:        timeline.ActivityTypeJira,
+		Title:       title,
+		Description: description,
+		Timestamp:   timestamp,
+		Source:      "jira",
+		URL:         j.GetConfigString("base_url") + "browse/" + issue.Key,
+		Metadata:    metadata,
+	}
+}
+
+// convertIssueChanges converts the user's issue creation, transitions and
+// assignments in [start, end) into activities
+func (j *JiraConnector) convertIssueChanges(issue jiraIssue, userID string, start, end time.Time) []timeline.Activity {
+	var activities []timeline.Activity
+	ref := issue.issueRef()
+
+	if issue.Fields.Creator.matches(userID) {
+		if created, ok := parseJiraTime(issue.Fields.Created, start, end); ok {
+			activities = append(activities, j.newActivity(issue, issue.Key+"-created", "created",
+				fmt.Sprintf("Created %s", ref), "Created the issue", created, issue.Fields.Creator))
+		}
+	}
+
+	for _, history := range issue.Changelog.Histories {
+		if !history.Author.matches(userID) {
+			continue
+		}
+		timestamp, ok := parseJiraTime(history.Created, start, end)
+		if !ok {
+			continue
+		}
+
+		for _, item := range history.Items {
+			var activity timeline.Activity
+			switch strings.ToLower(item.Field) {
+			case "status":
+				title := fmt.Sprintf("Changed status to %s in %s", item.ToString, ref)
+				description := fmt.Sprintf("Changed status from %s to %s", item.FromString, item.ToString)
+				activity = j.newActivity(issue, history.ID+"-status", "transition", title, description, timestamp, history.Author)
+			case "assignee":
+				var title, description string
+				if item.ToString != "" {
+					title = fmt.Sprintf("Assigned %s to %s", ref, item.ToString)
+					description = fmt.Sprintf("Assigned to %s", item.ToString)
+				} else {
+					title = fmt.Sprintf("Unassigned %s", ref)
+					description = fmt.Sprintf("Unassigned (was %s)", item.FromString)
+				}
+				activity = j.newActivity(issue, history.ID+"-assignee", "assignment", title, description, timestamp, history.Author)
+			default:
+				continue
+			}
+
+			activity.Metadata["field_name"] = item.Field
+			if item.ToString != "" {
+				activity.Metadata["field_new_value"] = item.ToString
+			}
+			if item.FromString != "" {
+				activity.Metadata["field_old_value"] = item.FromString
+			}
+			activities = append(activities, activity)
+		}
+	}
+
+	return activities
+}
+
+// convertComment converts one of the user's comments in [start, end)
+func (j *JiraConnector) convertComment(issue jiraIssue, comment jiraComment, userID string, start, end time.Time) *timeline.Activity {
+	if !comment.Author.matches(userID) {
+		return nil
+	}
+	timestamp, ok := parseJiraTime(comment.Created, start, end)
+	if !ok {
+		return nil
+	}
+
+	description := strings.TrimSpace(comment.Body)
+	if runes := []rune(description); len(runes) > 200 {
+		description = string(runes[:197]) + "..."
+	}
+
+	activity := j.newActivity(issue, issue.Key+"-comment-"+comment.ID, "comment",
+		fmt.Sprintf("Commented on %s", issue.issueRef()), description, timestamp, comment.Author)
+	activity.URL += "?focusedCommentId=" + comment.ID
+	activity.Metadata["comment_id"] = comment.ID
+	return &activity
+}
+
+// convertWorklog converts one of the user's worklogs started in [start, end).
+// The activity lasts the logged time.
+func (j *JiraConnector) convertWorklog(issue jiraIssue, worklog jiraWorklog, userID string, start, end time.Time) *timeline.Activity {
+	if !worklog.Author.matches(userID) {
+		return nil
+	}
+	timestamp, ok := parseJiraTime(worklog.Started, start, end)
+	if !ok {
+		return nil
+	}
+
+	duration := time.Duration(worklog.TimeSpentSeconds) * time.Second
+	description := strings.TrimSpace(worklog.Comment)
+	if description == "" {
+		description = "Logged work"
+	}
+
+	activity := j.newActivity(issue, issue.Key+"-worklog-"+worklog.ID, "worklog",
+		fmt.Sprintf("Logged %s on %s", formatWorklogDuration(duration), issue.issueRef()), description, timestamp, worklog.Author)
+	activity.Duration = &duration
+	activity.Metadata["worklog_id"] = worklog.ID
+	activity.Metadata["time_spent_seconds"] = strconv.Itoa(worklog.TimeSpentSeconds)
+	return &activity
+}
+
+// formatWorklogDuration formats logged time the way Jira does (1h 30m)
+func formatWorklogDuration(d time.Duration) string {
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	switch {
+	case hours > 0 && minutes > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
@@ -0,0 +1,241 @@
+package connectors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arkeo/arkeo/internal/timeline"
+)
+
+func TestNewJiraConnector(t *testing.T) {
+	connector := NewJiraConnector()
+
+	if connector.Name() != "jira" {
+		t.Errorf("Expected name 'jira', got %s", connector.Name())
+	}
+
+	if connector.IsEnabled() {
+		t.Error("Connector should be disabled by default")
+	}
+}
+
+func TestJiraConnector_ValidateConfig(t *testing.T) {
+	connector := NewJiraConnector()
+
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr bool
+	}{
+		{"cloud", map[string]interface{}{"base_url": "https://acme.atlassian.net", "token": "abc", "email": "me@acme.com"}, false},
+		{"server", map[string]interface{}{"base_url": "https://jira.acme.com/", "token": "abc"}, false},
+		{"missing base_url", map[string]interface{}{"token": "abc"}, true},
+		{"missing token", map[string]interface{}{"base_url": "https://acme.atlassian.net"}, true},
+		{"invalid base_url", map[string]interface{}{"base_url": "acme.atlassian.net", "token": "abc"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := connector.ValidateConfig(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// jiraTestServer serves the subset of the Jira REST API used by the connector.
+// Only classic /search is available when cloud is false.
+func jiraTestServer(t *testing.T, cloud bool) *httptest.Server {
+	t.Helper()
+
+	issue := `{
+		"id": "10001",
+		"key": "ACME-42",
+		"fields": {
+			"summary": "Fix login redirect",
+			"created": "2024-01-15T08:30:00.000+0000",
+			"creator": {"accountId": "me-123", "name": "me", "displayName": "Me"},
+			"project": {"key": "ACME", "name": "Acme Portal"},
+			"issuetype": {"name": "Bug"}
+		},
+		"changelog": {"histories": [
+			{"id": "500", "author": {"accountId": "me-123", "name": "me", "displayName": "Me"}, "created": "2024-01-15T10:00:00.000+0000",
+			 "items": [{"field": "status", "fromString": "To Do", "toString": "In Progress"},
+			           {"field": "assignee", "fromString": "", "toString": "Me"},
+			           {"field": "labels", "fromString": "", "toString": "backend"}]},
+			{"id": "501", "author": {"accountId": "other-456", "name": "other", "displayName": "Other"}, "created": "2024-01-15T11:00:00.000+0000",
+			 "items": [{"field": "status", "fromString": "In Progress", "toString": "In Review"}]},
+			{"id": "502", "author": {"accountId": "me-123", "name": "me", "displayName": "Me"}, "created": "2024-01-14T11:00:00.000+0000",
+			 "items": [{"field": "status", "fromString": "Backlog", "toString": "To Do"}]}
+		]}
+	}`
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/rest/api/2/myself":
+			fmt.Fprint(w, `{"accountId": "me-123", "name": "me", "displayName": "Me"}`)
+		case "/rest/api/2/search/jql":
+			if !cloud {
+				http.NotFound(w, r)
+				return
+			}
+			jql := r.URL.Query().Get("jql")
+			if !strings.Contains(jql, `updatedDate >= "2024-01-14"`) {
+				t.Errorf("Unexpected JQL: %s", jql)
+			}
+			// Only the user's issues are searched, not the whole instance
+			if !strings.Contains(jql, "(assignee = currentUser() OR reporter = currentUser() OR worklogAuthor = currentUser() OR issue in updatedBy(currentUser()))") {
+				t.Errorf("Expected a user clause in the JQL: %s", jql)
+			}
+			fmt.Fprintf(w, `{"issues": [%s], "isLast": true}`, issue)
+		case "/rest/api/2/search":
+			fmt.Fprintf(w, `{"startAt": 0, "total": 1, "issues": [%s]}`, issue)
+		case "/rest/api/2/issue/ACME-42/comment":
+			fmt.Fprint(w, `{"startAt": 0, "total": 2, "comments": [
+				{"id": "9001", "author": {"accountId": "me-123", "name": "me"}, "body": "Root cause is the stale session cookie.", "created": "2024-01-15T12:00:00.000+0000"},
+				{"id": "9002", "author": {"accountId": "other-456", "name": "other"}, "body": "Thanks!", "created": "2024-01-15T12:30:00.000+0000"}
+			]}`)
+		case "/rest/api/2/issue/ACME-42/worklog":
+			fmt.Fprint(w, `{"startAt": 0, "total": 2, "worklogs": [
+				{"id": "700", "author": {"accountId": "me-123", "name": "me"}, "comment": "Debugging", "started": "2024-01-15T13:00:00.000+0000", "timeSpentSeconds": 5400},
+				{"id": "701", "author": {"accountId": "me-123", "name": "me"}, "started": "2024-01-16T09:00:00.000+0000", "timeSpentSeconds": 3600}
+			]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestJiraConnector_FetchActivities(t *testing.T) {
+	for _, cloud := range []bool{true, false} {
+		t.Run(fmt.Sprintf("cloud=%t", cloud), func(t *testing.T) {
+			server := jiraTestServer(t, cloud)
+			defer server.Close()
+
+			connector := NewJiraConnector()
+			if err := connector.Configure(map[string]interface{}{
+				"base_url":         server.URL,
+				"token":            "secret",
+				"include_issues":   true,
+				"include_comments": true,
+				"include_worklogs": true,
+			}); err != nil {
+				t.Fatalf("Configure failed: %v", err)
+			}
+
+			start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+			activities, err := connector.fetchActivities(context.Background(), start, start.AddDate(0, 0, 1))
+			if err != nil {
+				t.Fatalf("fetchActivities failed: %v", err)
+			}
+
+			titles := make(map[string]timeline.Activity)
+			for _, a := range activities {
+				titles[a.Title] = a
+				if a.Type != timeline.ActivityTypeJira || a.Source != "jira" {
+					t.Errorf("Unexpected type/source %s/%s", a.Type, a.Source)
+				}
+			}
+
+			expected := []string{
+				"Created ACME-42: Fix login redirect",
+				"Changed status to In Progress in ACME-42: Fix login redirect",
+				"Assigned ACME-42: Fix login redirect to Me",
+				"Commented on ACME-42: Fix login redirect",
+				"Logged 1h 30m on ACME-42: Fix login redirect",
+			}
+			if len(activities) != len(expected) {
+				t.Errorf("Expected %d activities, got %d: %v", len(expected), len(activities), titles)
+			}
+			for _, title := range expected {
+				if _, ok := titles[title]; !ok {
+					t.Errorf("Missing activity %q", title)
+				}
+			}
+
+			transition := titles["Changed status to In Progress in ACME-42: Fix login redirect"]
+			if transition.URL != server.URL+"/browse/ACME-42" {
+				t.Errorf("Unexpected URL: %s", transition.URL)
+			}
+			if transition.Metadata["field_old_value"] != "To Do" || transition.Metadata["project_key"] != "ACME" {
+				t.Errorf("Unexpected metadata: %v", transition.Metadata)
+			}
+
+			worklog := titles["Logged 1h 30m on ACME-42: Fix login redirect"]
+			if worklog.Duration == nil || *worklog.Duration != 90*time.Minute {
+				t.Errorf("Expected worklog duration of 90m, got %v", worklog.Duration)
+			}
+
+			comment := titles["Commented on ACME-42: Fix login redirect"]
+			if !strings.HasSuffix(comment.URL, "?focusedCommentId=9001") {
+				t.Errorf("Expected comment URL to focus the comment, got %s", comment.URL)
+			}
+		})
+	}
+}
+
+func TestJiraConnector_BuildJQL(t *testing.T) {
+	connector := NewJiraConnector()
+	if err := connector.Configure(map[string]interface{}{
+		"base_url": "https://acme.atlassian.net",
+		"token":    "secret",
+		"username": `5b10"ac8d`,
+		"jql":      "project = ACME",
+	}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	expected := `updatedDate >= "2024-01-14" AND updatedDate < "2024-01-17"` +
+		` AND (assignee = "5b10\"ac8d" OR reporter = "5b10\"ac8d" OR worklogAuthor = "5b10\"ac8d" OR issue in updatedBy("5b10\"ac8d"))` +
+		` AND (project = ACME) ORDER BY updated DESC`
+	if jql := connector.buildJQL(start, start.AddDate(0, 0, 1)); jql != expected {
+		t.Errorf("Expected JQL:\n%s\ngot:\n%s", expected, jql)
+	}
+}
+
+func TestJiraConnector_Authentication(t *testing.T) {
+	var authHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader = r.Header.Get("Authorization")
+		fmt.Fprint(w, `{"accountId": "me-123", "displayName": "Me"}`)
+	}))
+	defer server.Close()
+
+	connector := NewJiraConnector()
+	connector.Configure(map[string]interface{}{"base_url": server.URL, "token": "pat"})
+	if err := connector.TestConnection(context.Background()); err != nil {
+		t.Fatalf("TestConnection failed: %v", err)
+	}
+	if authHeader != "Bearer pat" {
+		t.Errorf("Expected bearer auth without email, got %q", authHeader)
+	}
+
+	connector.Configure(map[string]interface{}{"base_url": server.URL, "token": "pat", "email": "me@acme.com"})
+	if err := connector.TestConnection(context.Background()); err != nil {
+		t.Fatalf("TestConnection failed: %v", err)
+	}
+	if !strings.HasPrefix(authHeader, "Basic ") {
+		t.Errorf("Expected basic auth with email, got %q", authHeader)
+	}
+}
+
+func TestJiraConnector_TestConnectionUnauthorized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	connector := NewJiraConnector()
+	connector.Configure(map[string]interface{}{"base_url": server.URL, "token": "bad"})
+	if err := connector.TestConnection(context.Background()); err == nil {
+		t.Error("Expected an error for invalid credentials")
+	}
+}
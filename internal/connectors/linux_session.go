@@ -0,0 +1,414 @@
+package connectors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arkeo/arkeo/internal/timeline"
+)
+
+// Error constants for reusable error messages
+const (
+	errLinuxOnly               = "linux session connector only works on Linux"
+	errJournalctlUnavailable   = "journalctl command not available: %v"
+	errJournalctlCommandFailed = "failed to execute journalctl command: %v"
+	errParseJournalOutput      = "failed to parse journal output: %v"
+)
+
+// defaultLinuxSessionIdentifiers lists the journal identifiers that log
+// session, sleep and screen locker events on common desktops.
+const defaultLinuxSessionIdentifiers = "systemd-logind,systemd-sleep,gdm-password,lightdm,sddm,gnome-shell,gnome-screensaver,xfce4-screensaver,light-locker,kscreenlocker_greet,swaylock"
+
+// Journal MESSAGE_IDs (see systemd's catalog) for the events we track.
+const (
+	journalMessageSessionStart = "8d45620c1a4348dbb17410da57c60c66"
+	journalMessageSessionStop  = "3354939424b4456d9802ca8333ed424a"
+	journalMessageSleepStart   = "6bbd95ee977941e497c48be27c254128"
+	journalMessageSleepStop    = "8811e6df2a8e40f58a94cea26f8ebf14"
+)
+
+// Compile regexes once at package level. They are matched against the
+// MESSAGE field of entries that don't carry one of the MESSAGE_IDs above.
+var (
+	journalLockRegex    = regexp.MustCompile(`(?i)(screen ?saver (activated|started)|session \S+ locked|locking session|screen (is )?locked)`)
+	journalUnlockRegex  = regexp.MustCompile(`(?i)(screen ?saver (deactivated|stopped)|session \S+ unlocked|unlocking session|screen (is )?unlocked|pam_unix\((gdm-password|lightdm|sddm|kde|swaylock|xfce4-screensaver|gnome-screensaver)[^)]*:session\): session opened)`)
+	journalSuspendRegex = regexp.MustCompile(`(?i)(entering sleep state|the system will suspend now|suspending system)`)
+	journalResumeRegex  = regexp.MustCompile(`(?i)(system returned from sleep state|system resumed|operation 'sleep' finished)`)
+	journalLoginRegex   = regexp.MustCompile(`^New session (\S+) of user (\S+?)\.?$`)
+	journalLogoutRegex  = regexp.MustCompile(`^Removed session (\S+?)\.?$`)
+	journalSessionUser  = regexp.MustCompile(`for user (\S+?)(\(|\s|$)`)
+)
+
+// sessionEvent is a lock/unlock/suspend/resume/login/logout event read from
+// the journal.
+type sessionEvent struct {
+	timestamp time.Time
+	event     string // lock, unlock, suspend, resume, login, logout
+	active    bool   // whether the computer is in use after this event
+	user      string
+	session   string
+	message   string
+}
+
+// LinuxSessionConnector implements the Connector interface for systemd-logind
+// session events on Linux. It is the Linux counterpart of MacOSSystemConnector.
+type LinuxSessionConnector struct {
+	*BaseConnector
+	isCompatible bool // Cache Linux detection to avoid repeated runtime checks
+}
+
+// NewLinuxSessionConnector creates a new Linux session events connector
+func NewLinuxSessionConnector() *LinuxSessionConnector {
+	return &LinuxSessionConnector{
+		BaseConnector: NewBaseConnector(
+			"linux_session",
+			"Fetches Linux session events (lock/unlock, suspend/resume, login/logout) from the systemd journal",
+		),
+		isCompatible: runtime.GOOS == "linux",
+	}
+}
+
+// GetRequiredConfig returns the required configuration for Linux session events
+func (c *LinuxSessionConnector) GetRequiredConfig() []ConfigField {
+	return MergeConfigFields([]ConfigField{
+		{
+			Key:         "user",
+			Type:        "string",
+			Required:    false,
+			Description: "Only track login/logout sessions of this user (defaults to $USER)",
+			Default:     "",
+		},
+		{
+			Key:         "identifiers",
+			Type:        "string",
+			Required:    false,
+			Description: "Comma-separated journal SYSLOG_IDENTIFIERs to read events from",
+			Default:     defaultLinuxSessionIdentifiers,
+		},
+	})
+}
+
+// ValidateConfig validates the Linux session events configuration
+func (c *LinuxSessionConnector) ValidateConfig(config map[string]interface{}) error {
+	return ValidateConfigFields(config, c.GetRequiredConfig())
+}
+
+// TestConnection tests that the journal can be read
+func (c *LinuxSessionConnector) TestConnection(ctx context.Context) error {
+	if !c.isCompatible {
+		return fmt.Errorf(errLinuxOnly)
+	}
+
+	if _, err := exec.LookPath("journalctl"); err != nil {
+		return fmt.Errorf(errJournalctlUnavailable, err)
+	}
+
+	cmd := exec.CommandContext(ctx, "journalctl", "--output=json", "--no-pager", "-n", "1", "-t", "systemd-logind")
+	if err := cmd.Run(); err != nil {
+		// Respect context cancellation/timeout before wrapping the error.
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf(errJournalctlCommandFailed, err)
+	}
+
+	return nil
+}
+
+// buildJournalCommand creates a journalctl command for the given local day
+func (c *LinuxSessionConnector) buildJournalCommand(ctx context.Context, start, end time.Time) *exec.Cmd {
+	args := []string{
+		"--output=json",
+		"--no-pager",
+		"--since", start.Format("2006-01-02 15:04:05"),
+		"--until", end.Format("2006-01-02 15:04:05"),
+	}
+	for _, id := range c.getIdentifiers() {
+		args = append(args, "-t", id)
+	}
+
+	return exec.CommandContext(ctx, "journalctl", args...)
+}
+
+// GetActivities retrieves Linux session events for the specified date
+func (c *LinuxSessionConnector) GetActivities(ctx context.Context, date time.Time) ([]timeline.Activity, error) {
+	if !c.isCompatible {
+		return nil, fmt.Errorf(errLinuxOnly)
+	}
+
+	// Normalize to local day.
+	localDate := date.In(time.Local)
+	startOfDay := time.Date(localDate.Year(), localDate.Month(), localDate.Day(), 0, 0, 0, 0, localDate.Location())
+	endOfDay := startOfDay.Add(24 * time.Hour)
+
+	if c.IsDebugMode() {
+		log.Printf("LinuxSession Debug: Fetching journal entries for %s", date.Format("2006-01-02"))
+	}
+
+	cmd := c.buildJournalCommand(ctx, startOfDay, endOfDay)
+	output, err := cmd.Output()
+	if err != nil {
+		// Respect context cancellation/timeout before wrapping the error.
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf(errJournalctlCommandFailed, err)
+	}
+
+	events, err := parseJournalSessionEvents(bytes.NewReader(output), c.getUser())
+	if err != nil {
+		return nil, fmt.Errorf(errParseJournalOutput, err)
+	}
+
+	activities := buildSessionActivities(events, startOfDay, endOfDay)
+
+	if c.IsDebugMode() {
+		log.Printf("LinuxSession Debug: %d journal events, %d activities", len(events), len(activities))
+	}
+
+	return activities, nil
+}
+
+// IsEnabled returns whether this connector is enabled
+func (c *LinuxSessionConnector) IsEnabled() bool {
+	// Only available on Linux and when base connector is enabled
+	return c.isCompatible && c.BaseConnector.IsEnabled()
+}
+
+// getUser returns the user whose login sessions are tracked.
+func (c *LinuxSessionConnector) getUser() string {
+	if user := c.GetConfigString("user"); user != "" {
+		return user
+	}
+	return os.Getenv("USER")
+}
+
+// getIdentifiers returns the journal identifiers to read.
+func (c *LinuxSessionConnector) getIdentifiers() []string {
+	raw := c.GetConfigString("identifiers")
+	if raw == "" {
+		raw = defaultLinuxSessionIdentifiers
+	}
+	var ids []string
+	for _, id := range strings.Split(raw, ",") {
+		id = strings.TrimSpace(id)
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// parseJournalSessionEvents reads `journalctl --output=json` output (one JSON
+// object per line) and returns the session events it contains, sorted by
+// time. Login and logout events of users other than user are ignored; an
+// empty user keeps all of them.
+func parseJournalSessionEvents(r io.Reader, user string) ([]sessionEvent, error) {
+	var events []sessionEvent
+	// Maps session IDs to their user so "Removed session N." can be filtered.
+	sessionUsers := make(map[string]string)
+
+	decoder := json.NewDecoder(r)
+	for {
+		var entry map[string]interface{}
+		if err := decoder.Decode(&entry); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+
+		event, ok := journalEntryToEvent(entry)
+		if !ok {
+			continue
+		}
+
+		switch event.event {
+		case "login":
+			sessionUsers[event.session] = event.user
+		case "logout":
+			event.user = sessionUsers[event.session]
+		}
+
+		if user != "" && event.user != "" && event.user != user {
+			continue
+		}
+		// Logouts of sessions whose start we didn't see can't be attributed
+		if user != "" && event.event == "logout" && event.user == "" {
+			continue
+		}
+
+		events = append(events, event)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].timestamp.Before(events[j].timestamp)
+	})
+
+	return events, nil
+}
+
+// journalEntryToEvent classifies a single journal entry.
+func journalEntryToEvent(entry map[string]interface{}) (sessionEvent, bool) {
+	usec, err := strconv.ParseInt(journalField(entry, "__REALTIME_TIMESTAMP"), 10, 64)
+	if err != nil {
+		return sessionEvent{}, false
+	}
+
+	message := journalField(entry, "MESSAGE")
+	event := sessionEvent{
+		timestamp: time.UnixMicro(usec),
+		message:   message,
+	}
+
+	switch journalField(entry, "MESSAGE_ID") {
+	case journalMessageSessionStart:
+		event.event = "login"
+		event.session = journalField(entry, "SESSION_ID")
+		event.user = journalField(entry, "USER_ID")
+	case journalMessageSessionStop:
+		event.event = "logout"
+		event.session = journalField(entry, "SESSION_ID")
+	case journalMessageSleepStart:
+		event.event = "suspend"
+	case journalMessageSleepStop:
+		event.event = "resume"
+	}
+
+	if event.event == "" {
+		switch {
+		case journalLoginRegex.MatchString(message):
+			m := journalLoginRegex.FindStringSubmatch(message)
+			event.event, event.session, event.user = "login", m[1], m[2]
+		case journalLogoutRegex.MatchString(message):
+			event.event = "logout"
+			event.session = journalLogoutRegex.FindStringSubmatch(message)[1]
+		case journalUnlockRegex.MatchString(message):
+			event.event = "unlock"
+			if m := journalSessionUser.FindStringSubmatch(message); m != nil {
+				event.user = m[1]
+			}
+		case journalLockRegex.MatchString(message):
+			event.event = "lock"
+		case journalSuspendRegex.MatchString(message):
+			event.event = "suspend"
+		case journalResumeRegex.MatchString(message):
+			event.event = "resume"
+		default:
+			return sessionEvent{}, false
+		}
+	}
+
+	// Session IDs are sometimes only present in the message
+	if event.session == "" {
+		if m := journalLoginRegex.FindStringSubmatch(message); m != nil {
+			event.session, event.user = m[1], m[2]
+		} else if m := journalLogoutRegex.FindStringSubmatch(message); m != nil {
+			event.session = m[1]
+		}
+	}
+
+	event.active = event.event == "unlock" || event.event == "resume" || event.event == "login"
+	return event, true
+}
+
+// journalField returns a journal field as a string. journalctl encodes
+// non-UTF-8 values as arrays of bytes.
+func journalField(entry map[string]interface{}, key string) string {
+	switch v := entry[key].(type) {
+	case string:
+		return v
+	case []interface{}:
+		b := make([]byte, 0, len(v))
+		for _, n := range v {
+			if f, ok := n.(float64); ok {
+				b = append(b, byte(f))
+			}
+		}
+		return string(b)
+	}
+	return ""
+}
+
+// buildSessionActivities turns session events into "Computer is active/idle"
+// activities. Repeated events for the same state (e.g. a lock followed by a
+// suspend) are collapsed into the first one, and each activity lasts until
+// the state changes again. Only events within [start, end) are returned.
+func buildSessionActivities(events []sessionEvent, start, end time.Time) []timeline.Activity {
+	var changes []sessionEvent
+	for _, e := range events {
+		if e.timestamp.Before(start) || !e.timestamp.Before(end) {
+			continue
+		}
+		if len(changes) > 0 && changes[len(changes)-1].active == e.active {
+			continue
+		}
+		changes = append(changes, e)
+	}
+
+	activities := make([]timeline.Activity, 0, len(changes))
+	for i, e := range changes {
+		var title, description, lockState string
+		if e.active {
+			title = "Computer is active, user started working"
+			lockState = "0"
+		} else {
+			title = "Computer is idle, user left the computer"
+			lockState = "1"
+		}
+
+		switch e.event {
+		case "unlock":
+			description = "Screen unlocked - computer became active"
+		case "resume":
+			description = "System resumed from sleep - computer became active"
+		case "login":
+			description = "User logged in - computer became active"
+		case "lock":
+			description = "Screen locked - computer became idle"
+		case "suspend":
+			description = "System went to sleep - computer became idle"
+		case "logout":
+			description = "User logged out - computer became idle"
+		}
+
+		var dur *time.Duration
+		if i+1 < len(changes) {
+			d := changes[i+1].timestamp.Sub(e.timestamp)
+			dur = &d
+		}
+
+		metadata := map[string]string{
+			"lock_state": lockState,
+			"event_type": e.event,
+		}
+		if e.session != "" {
+			metadata["session"] = e.session
+		}
+
+		activities = append(activities, timeline.Activity{
+			ID:          fmt.Sprintf("linux-session-%s-%d", e.event, e.timestamp.Unix()),
+			Type:        timeline.ActivityTypeSystem,
+			Title:       title,
+			Description: description,
+			Timestamp:   e.timestamp,
+			Duration:    dur,
+			Source:      "linux_session",
+			Metadata:    metadata,
+		})
+	}
+
+	return activities
+}
@@ -0,0 +1,230 @@
+package connectors
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arkeo/arkeo/internal/timeline"
+)
+
+// journalLine builds a journalctl JSON line for the given local time.
+func journalLine(ts time.Time, identifier, message string, extra ...string) string {
+	fields := []string{
+		fmt.Sprintf(`"__REALTIME_TIMESTAMP":"%d"`, ts.UnixMicro()),
+		fmt.Sprintf(`"SYSLOG_IDENTIFIER":"%s"`, identifier),
+		fmt.Sprintf(`"MESSAGE":%q`, message),
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		fields = append(fields, fmt.Sprintf(`%q:%q`, extra[i], extra[i+1]))
+	}
+	return "{" + strings.Join(fields, ",") + "}"
+}
+
+func TestNewLinuxSessionConnector(t *testing.T) {
+	connector := NewLinuxSessionConnector()
+
+	if connector.Name() != "linux_session" {
+		t.Errorf("Expected name 'linux_session', got '%s'", connector.Name())
+	}
+
+	if connector.Description() == "" {
+		t.Error("Expected non-empty description")
+	}
+
+	if connector.IsEnabled() {
+		t.Error("Connector should be disabled by default")
+	}
+}
+
+func TestLinuxSessionConnector_GetRequiredConfig(t *testing.T) {
+	connector := NewLinuxSessionConnector()
+	config := connector.GetRequiredConfig()
+
+	fieldMap := make(map[string]bool)
+	for _, field := range config {
+		fieldMap[field.Key] = true
+	}
+
+	for _, key := range []string{"user", "identifiers"} {
+		if !fieldMap[key] {
+			t.Errorf("Expected config field '%s' not found", key)
+		}
+	}
+}
+
+func TestLinuxSessionConnector_IsEnabled(t *testing.T) {
+	connector := NewLinuxSessionConnector()
+	connector.SetEnabled(true)
+
+	if runtime.GOOS == "linux" {
+		if !connector.IsEnabled() {
+			t.Error("Expected connector to be enabled on Linux")
+		}
+	} else if connector.IsEnabled() {
+		t.Error("Expected connector to be disabled on non-Linux")
+	}
+}
+
+func TestParseJournalSessionEvents(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 1, 15, hour, minute, 0, 0, time.Local)
+	}
+
+	input := strings.Join([]string{
+		journalLine(at(8, 55), "systemd-logind", "New session 3 of user alice.",
+			"MESSAGE_ID", journalMessageSessionStart, "SESSION_ID", "3", "USER_ID", "alice"),
+		journalLine(at(9, 0), "systemd-logind", "New session c1 of user root."),
+		journalLine(at(10, 0), "gnome-shell", "Screen locked"),
+		journalLine(at(10, 1), "systemd-sleep", "Entering sleep state 'suspend'...",
+			"MESSAGE_ID", journalMessageSleepStart),
+		journalLine(at(10, 30), "systemd-sleep", "System returned from sleep state.",
+			"MESSAGE_ID", journalMessageSleepStop),
+		journalLine(at(10, 31), "gdm-password", "pam_unix(gdm-password:session): session opened for user alice(uid=1000) by (uid=0)"),
+		journalLine(at(11, 0), "systemd-logind", "Removed session c1."),
+		journalLine(at(12, 0), "systemd-logind", "Watching system buttons on /dev/input/event0"),
+		journalLine(at(18, 0), "systemd-logind", "Removed session 3.",
+			"MESSAGE_ID", journalMessageSessionStop, "SESSION_ID", "3"),
+	}, "\n")
+
+	events, err := parseJournalSessionEvents(strings.NewReader(input), "alice")
+	if err != nil {
+		t.Fatalf("Failed to parse journal: %v", err)
+	}
+
+	expected := []struct {
+		event  string
+		active bool
+	}{
+		{"login", true},
+		{"lock", false},
+		{"suspend", false},
+		{"resume", true},
+		{"unlock", true},
+		{"logout", false},
+	}
+
+	if len(events) != len(expected) {
+		for _, e := range events {
+			t.Logf("  %s %s %s", e.timestamp.Format("15:04"), e.event, e.message)
+		}
+		t.Fatalf("Expected %d events, got %d", len(expected), len(events))
+	}
+
+	for i, exp := range expected {
+		if events[i].event != exp.event || events[i].active != exp.active {
+			t.Errorf("Event %d: expected %s (active=%v), got %s (active=%v)", i, exp.event, exp.active, events[i].event, events[i].active)
+		}
+	}
+
+	if events[0].session != "3" || events[0].user != "alice" {
+		t.Errorf("Expected session 3 of alice, got session %s of %s", events[0].session, events[0].user)
+	}
+}
+
+func TestParseJournalSessionEvents_BinaryMessage(t *testing.T) {
+	ts := time.Date(2024, 1, 15, 10, 0, 0, 0, time.Local)
+	// journalctl encodes non-UTF-8 fields as byte arrays
+	var bytes []string
+	for _, b := range []byte("Screen locked\xff") {
+		bytes = append(bytes, fmt.Sprintf("%d", b))
+	}
+	input := fmt.Sprintf(`{"__REALTIME_TIMESTAMP":"%d","MESSAGE":[%s]}`, ts.UnixMicro(), strings.Join(bytes, ","))
+
+	events, err := parseJournalSessionEvents(strings.NewReader(input), "")
+	if err != nil {
+		t.Fatalf("Failed to parse journal: %v", err)
+	}
+	if len(events) != 1 || events[0].event != "lock" {
+		t.Errorf("Expected a lock event, got %+v", events)
+	}
+}
+
+func TestParseJournalSessionEvents_InvalidJSON(t *testing.T) {
+	_, err := parseJournalSessionEvents(strings.NewReader("not json"), "")
+	if err == nil {
+		t.Error("Expected error for invalid JSON")
+	}
+
+	events, err := parseJournalSessionEvents(strings.NewReader(""), "")
+	if err != nil {
+		t.Errorf("Expected no error for empty input, got: %v", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("Expected 0 events for empty input, got %d", len(events))
+	}
+}
+
+func TestBuildSessionActivities(t *testing.T) {
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.Local)
+	at := func(hour, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+
+	events := []sessionEvent{
+		{timestamp: at(0, 0).Add(-time.Hour), event: "unlock", active: true},
+		{timestamp: at(9, 0), event: "login", active: true, session: "3"},
+		{timestamp: at(12, 0), event: "lock", active: false},
+		{timestamp: at(12, 1), event: "suspend", active: false},
+		{timestamp: at(13, 0), event: "resume", active: true},
+		{timestamp: at(13, 1), event: "unlock", active: true},
+		{timestamp: at(18, 0), event: "lock", active: false},
+	}
+
+	activities := buildSessionActivities(events, day, day.Add(24*time.Hour))
+
+	if len(activities) != 4 {
+		t.Fatalf("Expected 4 activities, got %d", len(activities))
+	}
+
+	first := activities[0]
+	if first.Title != "Computer is active, user started working" {
+		t.Errorf("Expected active title, got '%s'", first.Title)
+	}
+	if first.Duration == nil || *first.Duration != 3*time.Hour {
+		t.Errorf("Expected 3h duration, got %v", first.Duration)
+	}
+	if first.Metadata["session"] != "3" || first.Metadata["event_type"] != "login" {
+		t.Errorf("Unexpected metadata: %v", first.Metadata)
+	}
+	if first.Type != timeline.ActivityTypeSystem {
+		t.Errorf("Expected type 'system', got '%s'", first.Type)
+	}
+	if first.Source != "linux_session" {
+		t.Errorf("Expected source 'linux_session', got '%s'", first.Source)
+	}
+
+	idle := activities[1]
+	if idle.Title != "Computer is idle, user left the computer" {
+		t.Errorf("Expected idle title, got '%s'", idle.Title)
+	}
+	if idle.Metadata["lock_state"] != "1" {
+		t.Errorf("Expected lock_state '1', got '%s'", idle.Metadata["lock_state"])
+	}
+	if idle.Duration == nil || *idle.Duration != time.Hour {
+		t.Errorf("Expected 1h idle duration, got %v", idle.Duration)
+	}
+
+	if activities[2].Metadata["event_type"] != "resume" {
+		t.Errorf("Expected resume to start the active period, got '%s'", activities[2].Metadata["event_type"])
+	}
+
+	if activities[3].Duration != nil {
+		t.Errorf("Expected last activity to have no duration, got %v", *activities[3].Duration)
+	}
+}
+
+func TestLinuxSessionConnector_GetActivities_NonLinux(t *testing.T) {
+	if runtime.GOOS == "linux" {
+		t.Skip("Skipping non-Linux test on Linux system")
+	}
+
+	connector := NewLinuxSessionConnector()
+	_, err := connector.GetActivities(context.Background(), time.Now())
+	if err == nil {
+		t.Error("Expected error on non-Linux systems")
+	}
+}
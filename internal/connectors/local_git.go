@@ -0,0 +1,564 @@
+package connectors
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arkeo/arkeo/internal/timeline"
+)
+
+// Pre-compiled regexps used by the local git connector.
+var (
+	// remoteURLRegex extracts "owner/repo" from common remote URL forms:
+	//   git@github.com:owner/repo.git
+	//   https://gitlab.example.com/group/sub/repo.git
+	//   ssh://git@host:2222/owner/repo
+	remoteURLRegex = regexp.MustCompile(`^(?:[a-z+]+://)?(?:[^@/]+@)?[^:/]+(?::\d+)?[:/](.+?)(?:\.git)?/?$`)
+)
+
+// skipScanDirs lists directory names that are never descended into while
+// looking for repositories. They are large and never contain working copies
+// the user cares about.
+var skipScanDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	".cache":       true,
+	"Library":      true,
+}
+
+// LocalGitConnector implements the Connector interface for git repositories
+// checked out on the local disk. It reads the reflogs under .git/logs so it
+// works offline and also sees unpushed work and commits on feature branches.
+type LocalGitConnector struct {
+	*BaseConnector
+}
+
+// NewLocalGitConnector creates a new local git connector
+func NewLocalGitConnector() *LocalGitConnector {
+	return &LocalGitConnector{
+		BaseConnector: NewBaseConnector(
+			"local_git",
+			"Fetches commits, checkouts, merges and rebases from local git repositories",
+		),
+	}
+}
+
+// GetRequiredConfig returns the required configuration for local git
+func (l *LocalGitConnector) GetRequiredConfig() []ConfigField {
+	return MergeConfigFields([]ConfigField{
+		{
+			Key:         "roots",
+			Type:        "string",
+			Required:    true,
+			Description: "Comma-separated list of directories to scan for git repositories (~ is expanded)",
+		},
+		{
+			Key:         "emails",
+			Type:        "string",
+			Required:    true,
+			Description: "Comma-separated list of author emails to include",
+		},
+		{
+			Key:         "max_depth",
+			Type:        "int",
+			Required:    false,
+			Description: "Maximum directory depth below each root to look for repositories",
+			Default:     4,
+		},
+	})
+}
+
+// ValidateConfig validates the local git configuration
+func (l *LocalGitConnector) ValidateConfig(config map[string]interface{}) error {
+	return ValidateConfigFields(config, l.GetRequiredConfig())
+}
+
+// TestConnection checks that at least one repository can be found below the
+// configured roots.
+func (l *LocalGitConnector) TestConnection(ctx context.Context) error {
+	roots := l.getConfigList("roots")
+	if len(roots) == 0 {
+		return fmt.Errorf("no roots configured")
+	}
+
+	repos := findGitRepositories(roots, l.getMaxDepth())
+	if len(repos) == 0 {
+		return fmt.Errorf("no git repositories found under %s", strings.Join(roots, ", "))
+	}
+
+	if l.IsDebugMode() {
+		log.Printf("LocalGit Debug: Found %d repositories", len(repos))
+	}
+
+	return nil
+}
+
+// GetActivities retrieves local git activities for the specified date
+func (l *LocalGitConnector) GetActivities(ctx context.Context, date time.Time) ([]timeline.Activity, error) {
+	roots := l.getConfigList("roots")
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("no roots configured")
+	}
+
+	emails := make(map[string]bool)
+	for _, e := range l.getConfigList("emails") {
+		emails[strings.ToLower(e)] = true
+	}
+
+	// Normalize to the user's local day so that day boundaries match the
+	// user's working day rather than UTC.
+	localDate := date.In(time.Local)
+	startOfDay := time.Date(localDate.Year(), localDate.Month(), localDate.Day(), 0, 0, 0, 0, localDate.Location())
+	endOfDay := startOfDay.Add(24 * time.Hour)
+
+	repos := findGitRepositories(roots, l.getMaxDepth())
+
+	if l.IsDebugMode() {
+		log.Printf("LocalGit Debug: Scanning %d repositories for %s", len(repos), date.Format("2006-01-02"))
+	}
+
+	var activities []timeline.Activity
+	for _, repo := range repos {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		repoActivities, err := readRepositoryActivities(repo, emails, startOfDay, endOfDay)
+		if err != nil {
+			if l.IsDebugMode() {
+				log.Printf("LocalGit Debug: Failed to read %s: %v", repo.workDir, err)
+			}
+			continue
+		}
+		activities = append(activities, repoActivities...)
+	}
+
+	sort.Slice(activities, func(i, j int) bool {
+		return activities[i].Timestamp.Before(activities[j].Timestamp)
+	})
+
+	if l.IsDebugMode() {
+		log.Printf("LocalGit Debug: Total activities found: %d", len(activities))
+	}
+
+	return activities, nil
+}
+
+// getConfigList returns a comma-separated config value as a trimmed list.
+func (l *LocalGitConnector) getConfigList(key string) []string {
+	var values []string
+	for _, s := range strings.Split(l.GetConfigString(key), ",") {
+		s = strings.TrimSpace(s)
+		if s != "" {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+// getMaxDepth returns the configured scan depth, defaulting to 4.
+func (l *LocalGitConnector) getMaxDepth() int {
+	if depth := l.GetConfigInt("max_depth"); depth > 0 {
+		return depth
+	}
+	return 4
+}
+
+// gitRepository describes a repository discovered on disk.
+type gitRepository struct {
+	workDir   string // working copy directory
+	gitDir    string // .git directory (per-worktree for linked worktrees)
+	commonDir string // shared .git directory holding refs/ and config
+	name      string // "owner/repo" from the origin remote, or the directory name
+}
+
+// findGitRepositories walks the given roots up to maxDepth levels deep and
+// returns every repository found. Repositories are not descended into, so
+// nested repositories (e.g. submodules) are not reported separately.
+func findGitRepositories(roots []string, maxDepth int) []gitRepository {
+	var repos []gitRepository
+	seen := make(map[string]bool)
+
+	for _, root := range roots {
+		root = expandHome(root)
+		rootDepth := strings.Count(filepath.Clean(root), string(os.PathSeparator))
+
+		_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || !d.IsDir() {
+				return nil
+			}
+
+			if path != root && (strings.HasPrefix(d.Name(), ".") || skipScanDirs[d.Name()]) {
+				return filepath.SkipDir
+			}
+
+			if repo, ok := openGitRepository(path); ok {
+				if !seen[repo.gitDir] {
+					seen[repo.gitDir] = true
+					repos = append(repos, repo)
+				}
+				return filepath.SkipDir
+			}
+
+			if strings.Count(filepath.Clean(path), string(os.PathSeparator))-rootDepth >= maxDepth {
+				return filepath.SkipDir
+			}
+			return nil
+		})
+	}
+
+	return repos
+}
+
+// openGitRepository returns the repository whose working copy is dir, if any.
+// Both regular ".git" directories and ".git" files pointing at a linked
+// worktree ("gitdir: ...") are supported.
+func openGitRepository(dir string) (gitRepository, bool) {
+	dotGit := filepath.Join(dir, ".git")
+	info, err := os.Stat(dotGit)
+	if err != nil {
+		return gitRepository{}, false
+	}
+
+	gitDir := dotGit
+	if !info.IsDir() {
+		data, err := os.ReadFile(dotGit)
+		if err != nil {
+			return gitRepository{}, false
+		}
+		line := strings.TrimSpace(string(data))
+		if !strings.HasPrefix(line, "gitdir:") {
+			return gitRepository{}, false
+		}
+		gitDir = strings.TrimSpace(strings.TrimPrefix(line, "gitdir:"))
+		if !filepath.IsAbs(gitDir) {
+			gitDir = filepath.Join(dir, gitDir)
+		}
+	}
+
+	commonDir := gitDir
+	if data, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		commonDir = strings.TrimSpace(string(data))
+		if !filepath.IsAbs(commonDir) {
+			commonDir = filepath.Join(gitDir, commonDir)
+		}
+	}
+
+	name := repositoryNameFromConfig(filepath.Join(commonDir, "config"))
+	if name == "" {
+		name = filepath.Base(dir)
+	}
+
+	return gitRepository{
+		workDir:   dir,
+		gitDir:    gitDir,
+		commonDir: commonDir,
+		name:      name,
+	}, true
+}
+
+// repositoryNameFromConfig reads the origin remote URL from a git config file
+// and returns its "owner/repo" path. Returns "" if there is no origin remote.
+func repositoryNameFromConfig(configPath string) string {
+	f, err := os.Open(configPath)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	inOrigin := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inOrigin = line == `[remote "origin"]`
+			continue
+		}
+		if !inOrigin {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(key) != "url" {
+			continue
+		}
+		if m := remoteURLRegex.FindStringSubmatch(strings.TrimSpace(value)); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// reflogEntry is a single line of a git reflog file.
+type reflogEntry struct {
+	oldSHA    string
+	newSHA    string
+	name      string
+	email     string
+	timestamp time.Time
+	message   string
+}
+
+// parseReflogLine parses a reflog line of the form
+//
+//	<old-sha> <new-sha> <name> <<email>> <unix-ts> <tz>\t<message>
+func parseReflogLine(line string) (reflogEntry, bool) {
+	header, message, _ := strings.Cut(line, "\t")
+
+	fields := strings.SplitN(header, " ", 3)
+	if len(fields) != 3 {
+		return reflogEntry{}, false
+	}
+
+	rest := fields[2]
+	emailStart := strings.LastIndex(rest, "<")
+	emailEnd := strings.LastIndex(rest, ">")
+	if emailStart < 0 || emailEnd < emailStart {
+		return reflogEntry{}, false
+	}
+
+	tsParts := strings.Fields(rest[emailEnd+1:])
+	if len(tsParts) != 2 {
+		return reflogEntry{}, false
+	}
+	unix, err := strconv.ParseInt(tsParts[0], 10, 64)
+	if err != nil {
+		return reflogEntry{}, false
+	}
+
+	return reflogEntry{
+		oldSHA:    fields[0],
+		newSHA:    fields[1],
+		name:      strings.TrimSpace(rest[:emailStart]),
+		email:     rest[emailStart+1 : emailEnd],
+		timestamp: time.Unix(unix, 0).In(parseGitTimezone(tsParts[1])),
+		message:   message,
+	}, true
+}
+
+// parseGitTimezone converts a git "+HHMM" offset into a fixed zone.
+func parseGitTimezone(tz string) *time.Location {
+	if len(tz) != 5 {
+		return time.UTC
+	}
+	hours, err1 := strconv.Atoi(tz[1:3])
+	minutes, err2 := strconv.Atoi(tz[3:5])
+	if err1 != nil || err2 != nil {
+		return time.UTC
+	}
+	offset := hours*3600 + minutes*60
+	if tz[0] == '-' {
+		offset = -offset
+	}
+	return time.FixedZone(tz, offset)
+}
+
+// readReflog reads all entries from a reflog file.
+func readReflog(path string) ([]reflogEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []reflogEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if entry, ok := parseReflogLine(scanner.Text()); ok {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// readRepositoryActivities converts the reflog entries of a repository that
+// fall in [start, end) and were made by one of the given emails into
+// timeline activities.
+//
+// The HEAD reflog is the primary source since it records every commit,
+// checkout, merge and rebase made in the working copy. Branch reflogs are
+// used to find the branch each commit landed on and to pick up commits that
+// are missing from HEAD's log (e.g. made from another worktree).
+func readRepositoryActivities(repo gitRepository, emails map[string]bool, start, end time.Time) ([]timeline.Activity, error) {
+	matches := func(e reflogEntry) bool {
+		if e.timestamp.Before(start) || !e.timestamp.Before(end) {
+			return false
+		}
+		return len(emails) == 0 || emails[strings.ToLower(e.email)]
+	}
+
+	type branchEntry struct {
+		branch string
+		entry  reflogEntry
+	}
+	var branchEntries []branchEntry
+	branchBySHA := make(map[string]string)
+
+	headsDir := filepath.Join(repo.commonDir, "logs", "refs", "heads")
+	_ = filepath.WalkDir(headsDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(headsDir, path)
+		if err != nil {
+			return nil
+		}
+		branch := filepath.ToSlash(rel)
+		entries, err := readReflog(path)
+		if err != nil {
+			return nil
+		}
+		for _, e := range entries {
+			if !matches(e) {
+				continue
+			}
+			branchBySHA[e.newSHA] = branch
+			branchEntries = append(branchEntries, branchEntry{branch: branch, entry: e})
+		}
+		return nil
+	})
+
+	headEntries, err := readReflog(filepath.Join(repo.gitDir, "logs", "HEAD"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var activities []timeline.Activity
+	seenCommits := make(map[string]bool)
+
+	for _, e := range headEntries {
+		if !matches(e) {
+			continue
+		}
+		activity := reflogEntryToActivity(repo, e, branchBySHA[e.newSHA])
+		if activity == nil {
+			continue
+		}
+		if activity.Metadata["action"] == "commit" {
+			seenCommits[e.newSHA] = true
+		}
+		activities = append(activities, *activity)
+	}
+
+	for _, be := range branchEntries {
+		if seenCommits[be.entry.newSHA] {
+			continue
+		}
+		activity := reflogEntryToActivity(repo, be.entry, be.branch)
+		if activity == nil || activity.Metadata["action"] != "commit" {
+			continue
+		}
+		seenCommits[be.entry.newSHA] = true
+		activities = append(activities, *activity)
+	}
+
+	return activities, nil
+}
+
+// reflogEntryToActivity converts a reflog entry into a timeline activity.
+// Returns nil for entries that don't describe user work (fetches, resets,
+// intermediate rebase steps, branch creation, ...).
+func reflogEntryToActivity(repo gitRepository, e reflogEntry, branch string) *timeline.Activity {
+	action, detail, _ := strings.Cut(e.message, ": ")
+
+	var kind, title, description string
+	switch {
+	case action == "commit" || action == "commit (initial)" || action == "commit (merge)" || action == "cherry-pick":
+		kind = "commit"
+		title = fmt.Sprintf("%s on %s", detail, repo.name)
+		description = fmt.Sprintf("Commit to %s", repo.name)
+	case action == "commit (amend)":
+		kind = "commit"
+		title = fmt.Sprintf("%s on %s", detail, repo.name)
+		description = fmt.Sprintf("Amended commit in %s", repo.name)
+	case strings.HasPrefix(action, "merge "):
+		kind = "merge"
+		merged := strings.TrimPrefix(action, "merge ")
+		title = fmt.Sprintf("Merged %s on %s", merged, repo.name)
+		description = fmt.Sprintf("Merged %s in %s (%s)", merged, repo.name, detail)
+	case action == "checkout":
+		kind = "checkout"
+		var from, to string
+		if _, moving, ok := strings.Cut(detail, "moving from "); ok {
+			from, to, _ = strings.Cut(moving, " to ")
+		}
+		if to == "" || from == to {
+			return nil
+		}
+		title = fmt.Sprintf("Switched to %s on %s", to, repo.name)
+		description = fmt.Sprintf("Checked out %s (from %s) in %s", to, from, repo.name)
+		branch = to
+	case strings.HasPrefix(action, "rebase") && strings.HasSuffix(action, "(finish)"):
+		kind = "rebase"
+		target := strings.TrimPrefix(detail, "returning to ")
+		target = strings.TrimPrefix(target, "refs/heads/")
+		if i := strings.Index(target, " onto "); i >= 0 {
+			target = target[:i]
+		}
+		title = fmt.Sprintf("Rebased %s on %s", target, repo.name)
+		description = fmt.Sprintf("Finished rebase of %s in %s", target, repo.name)
+		branch = target
+	default:
+		return nil
+	}
+
+	if strings.HasPrefix(branch, "refs/heads/") {
+		branch = strings.TrimPrefix(branch, "refs/heads/")
+	}
+	if kind == "commit" && branch != "" {
+		description = fmt.Sprintf("%s (%s)", description, branch)
+	}
+
+	shortSHA := e.newSHA
+	if len(shortSHA) > 8 {
+		shortSHA = shortSHA[:8]
+	}
+
+	id := fmt.Sprintf("local-git-%s", shortSHA)
+	if kind != "commit" {
+		id = fmt.Sprintf("local-git-%s-%s-%d", kind, shortSHA, e.timestamp.Unix())
+	}
+
+	metadata := map[string]string{
+		"repository": repo.name,
+		"path":       repo.workDir,
+		"sha":        e.newSHA,
+		"action":     kind,
+		"author":     e.email,
+	}
+	if branch != "" {
+		metadata["branch"] = branch
+	}
+
+	return &timeline.Activity{
+		ID:          id,
+		Type:        timeline.ActivityTypeGitCommit,
+		Title:       title,
+		Description: description,
+		Timestamp:   e.timestamp,
+		Source:      "local_git",
+		Metadata:    metadata,
+	}
+}
+
+// expandHome replaces a leading "~" with the user's home directory.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
@@ -0,0 +1,335 @@
+package connectors
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arkeo/arkeo/internal/timeline"
+)
+
+// writeFixtureRepo creates a fake repository under dir with the given HEAD
+// reflog, branch reflogs and origin URL. No git binary is required since the
+// connector only reads files under .git.
+func writeFixtureRepo(t *testing.T, dir string, origin string, head []string, branches map[string][]string) {
+	t.Helper()
+
+	gitDir := filepath.Join(dir, ".git")
+	if err := os.MkdirAll(filepath.Join(gitDir, "logs", "refs", "heads"), 0o755); err != nil {
+		t.Fatalf("Failed to create fixture repo: %v", err)
+	}
+
+	if origin != "" {
+		config := fmt.Sprintf("[core]\n\tbare = false\n[remote \"origin\"]\n\turl = %s\n\tfetch = +refs/heads/*:refs/remotes/origin/*\n", origin)
+		if err := os.WriteFile(filepath.Join(gitDir, "config"), []byte(config), 0o644); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(gitDir, "logs", "HEAD"), []byte(strings.Join(head, "\n")+"\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write HEAD reflog: %v", err)
+	}
+
+	for branch, lines := range branches {
+		path := filepath.Join(gitDir, "logs", "refs", "heads", filepath.FromSlash(branch))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("Failed to create branch log dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+			t.Fatalf("Failed to write branch reflog: %v", err)
+		}
+	}
+}
+
+// reflogLine formats a reflog line for the fixture repos.
+func reflogLine(oldSHA, newSHA, email string, ts time.Time, message string) string {
+	return fmt.Sprintf("%s %s Test User <%s> %d +0000\t%s", oldSHA, newSHA, email, ts.Unix(), message)
+}
+
+func sha(c byte) string {
+	return strings.Repeat(string(c), 40)
+}
+
+func TestNewLocalGitConnector(t *testing.T) {
+	connector := NewLocalGitConnector()
+
+	if connector.Name() != "local_git" {
+		t.Errorf("Expected name 'local_git', got '%s'", connector.Name())
+	}
+
+	if connector.Description() == "" {
+		t.Error("Expected non-empty description")
+	}
+
+	if connector.IsEnabled() {
+		t.Error("Connector should be disabled by default")
+	}
+}
+
+func TestLocalGitConnector_GetRequiredConfig(t *testing.T) {
+	connector := NewLocalGitConnector()
+	config := connector.GetRequiredConfig()
+
+	fieldMap := make(map[string]ConfigField)
+	for _, field := range config {
+		fieldMap[field.Key] = field
+	}
+
+	for _, key := range []string{"roots", "emails", "max_depth"} {
+		if _, ok := fieldMap[key]; !ok {
+			t.Errorf("Expected config field '%s' not found", key)
+		}
+	}
+
+	if !fieldMap["roots"].Required {
+		t.Error("Expected 'roots' to be required")
+	}
+	if !fieldMap["emails"].Required {
+		t.Error("Expected 'emails' to be required")
+	}
+}
+
+func TestLocalGitConnector_ValidateConfig(t *testing.T) {
+	connector := NewLocalGitConnector()
+
+	if err := connector.ValidateConfig(map[string]interface{}{}); err == nil {
+		t.Error("Expected error for empty config")
+	}
+
+	err := connector.ValidateConfig(map[string]interface{}{
+		"roots":  "~/code",
+		"emails": "me@example.com",
+	})
+	if err != nil {
+		t.Errorf("Expected no error for valid config, got: %v", err)
+	}
+}
+
+func TestParseReflogLine(t *testing.T) {
+	line := sha('a') + " " + sha('b') + " Jane Q. Doe <jane@example.com> 1705315200 +0100\tcommit: Fix the thing"
+
+	entry, ok := parseReflogLine(line)
+	if !ok {
+		t.Fatal("Expected line to parse")
+	}
+
+	if entry.oldSHA != sha('a') || entry.newSHA != sha('b') {
+		t.Errorf("Unexpected SHAs: %s -> %s", entry.oldSHA, entry.newSHA)
+	}
+	if entry.name != "Jane Q. Doe" {
+		t.Errorf("Expected name 'Jane Q. Doe', got '%s'", entry.name)
+	}
+	if entry.email != "jane@example.com" {
+		t.Errorf("Expected email 'jane@example.com', got '%s'", entry.email)
+	}
+	if entry.timestamp.Unix() != 1705315200 {
+		t.Errorf("Expected timestamp 1705315200, got %d", entry.timestamp.Unix())
+	}
+	if _, offset := entry.timestamp.Zone(); offset != 3600 {
+		t.Errorf("Expected +0100 offset, got %d", offset)
+	}
+	if entry.message != "commit: Fix the thing" {
+		t.Errorf("Expected message 'commit: Fix the thing', got '%s'", entry.message)
+	}
+
+	for _, bad := range []string{"", "garbage", sha('a') + " " + sha('b') + " No Email 123 +0000\tmsg"} {
+		if _, ok := parseReflogLine(bad); ok {
+			t.Errorf("Expected %q not to parse", bad)
+		}
+	}
+}
+
+func TestRepositoryNameFromConfig(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{"git@github.com:arkeo/arkeo.git", "arkeo/arkeo"},
+		{"https://github.com/arkeo/arkeo.git", "arkeo/arkeo"},
+		{"https://gitlab.example.com/group/sub/project", "group/sub/project"},
+		{"ssh://git@gitlab.example.com:2222/team/service.git", "team/service"},
+	}
+
+	for _, tt := range tests {
+		dir := t.TempDir()
+		writeFixtureRepo(t, dir, tt.url, nil, nil)
+
+		name := repositoryNameFromConfig(filepath.Join(dir, ".git", "config"))
+		if name != tt.expected {
+			t.Errorf("For %s expected '%s', got '%s'", tt.url, tt.expected, name)
+		}
+	}
+}
+
+func TestFindGitRepositories(t *testing.T) {
+	root := t.TempDir()
+
+	writeFixtureRepo(t, filepath.Join(root, "alpha"), "", nil, nil)
+	writeFixtureRepo(t, filepath.Join(root, "clients", "beta"), "git@github.com:acme/beta.git", nil, nil)
+	// Nested repo inside alpha should not be reported separately
+	writeFixtureRepo(t, filepath.Join(root, "alpha", "sub"), "", nil, nil)
+	// Too deep for max_depth=2
+	writeFixtureRepo(t, filepath.Join(root, "a", "b", "c", "deep"), "", nil, nil)
+	// Ignored directories
+	writeFixtureRepo(t, filepath.Join(root, "node_modules", "pkg"), "", nil, nil)
+	writeFixtureRepo(t, filepath.Join(root, ".hidden", "repo"), "", nil, nil)
+
+	// Linked worktree: .git file pointing into alpha's .git/worktrees
+	wtGitDir := filepath.Join(root, "alpha", ".git", "worktrees", "feature")
+	if err := os.MkdirAll(wtGitDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(wtGitDir, "commondir"), []byte("../..\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	wtDir := filepath.Join(root, "alpha-feature")
+	if err := os.MkdirAll(wtDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(wtDir, ".git"), []byte("gitdir: "+wtGitDir+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	repos := findGitRepositories([]string{root}, 2)
+
+	names := make(map[string]gitRepository)
+	for _, r := range repos {
+		names[r.name] = r
+	}
+
+	if len(repos) != 3 {
+		t.Errorf("Expected 3 repositories, got %d: %v", len(repos), names)
+	}
+	if _, ok := names["alpha"]; !ok {
+		t.Error("Expected to find 'alpha'")
+	}
+	if _, ok := names["acme/beta"]; !ok {
+		t.Error("Expected to find 'acme/beta' named from origin")
+	}
+	wt, ok := names["alpha-feature"]
+	if !ok {
+		t.Fatal("Expected to find linked worktree 'alpha-feature'")
+	}
+	if filepath.Clean(wt.commonDir) != filepath.Join(root, "alpha", ".git") {
+		t.Errorf("Expected worktree common dir to be alpha's .git, got %s", wt.commonDir)
+	}
+}
+
+func TestLocalGitConnector_GetActivities(t *testing.T) {
+	root := t.TempDir()
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.Local)
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 1, 15, hour, minute, 0, 0, time.Local)
+	}
+
+	me := "me@example.com"
+	other := "someone@example.com"
+
+	head := []string{
+		reflogLine(sha('0'), sha('1'), me, at(8, 0).Add(-24*time.Hour), "commit: Yesterday's work"),
+		reflogLine(sha('1'), sha('2'), me, at(9, 0), "commit: Add feature"),
+		reflogLine(sha('2'), sha('2'), me, at(9, 30), "checkout: moving from main to feature/login"),
+		reflogLine(sha('2'), sha('3'), strings.ToUpper(me), at(10, 0), "commit (amend): Add login form"),
+		reflogLine(sha('3'), sha('4'), other, at(10, 30), "commit: Not mine"),
+		reflogLine(sha('4'), sha('5'), me, at(11, 0), "pull: Fast-forward"),
+		reflogLine(sha('5'), sha('6'), me, at(11, 15), "rebase (start): checkout main"),
+		reflogLine(sha('6'), sha('7'), me, at(11, 16), "rebase (pick): Add login form"),
+		reflogLine(sha('7'), sha('7'), me, at(11, 17), "rebase (finish): returning to refs/heads/feature/login"),
+		reflogLine(sha('7'), sha('7'), me, at(12, 0), "checkout: moving from feature/login to main"),
+		reflogLine(sha('7'), sha('8'), me, at(12, 5), "merge feature/login: Fast-forward"),
+		reflogLine(sha('8'), sha('9'), me, at(8, 0).Add(24*time.Hour), "commit: Tomorrow's work"),
+	}
+	branches := map[string][]string{
+		"main": {
+			reflogLine(sha('1'), sha('2'), me, at(9, 0), "commit: Add feature"),
+		},
+		"feature/login": {
+			reflogLine(sha('2'), sha('3'), me, at(10, 0), "commit (amend): Add login form"),
+			// Made from another worktree, so it's missing from this HEAD log
+			reflogLine(sha('3'), sha('c'), me, at(13, 0), "commit: Polish login styles"),
+		},
+	}
+	writeFixtureRepo(t, filepath.Join(root, "project"), "git@github.com:acme/project.git", head, branches)
+
+	connector := NewLocalGitConnector()
+	err := connector.Configure(map[string]interface{}{
+		"roots":  root,
+		"emails": me + ", extra@example.com",
+	})
+	if err != nil {
+		t.Fatalf("Failed to configure: %v", err)
+	}
+
+	activities, err := connector.GetActivities(context.Background(), date)
+	if err != nil {
+		t.Fatalf("GetActivities failed: %v", err)
+	}
+
+	expected := []struct {
+		action string
+		title  string
+		branch string
+	}{
+		{"commit", "Add feature on acme/project", "main"},
+		{"checkout", "Switched to feature/login on acme/project", "feature/login"},
+		{"commit", "Add login form on acme/project", "feature/login"},
+		{"rebase", "Rebased feature/login on acme/project", "feature/login"},
+		{"checkout", "Switched to main on acme/project", "main"},
+		{"merge", "Merged feature/login on acme/project", ""},
+		{"commit", "Polish login styles on acme/project", "feature/login"},
+	}
+
+	if len(activities) != len(expected) {
+		for _, a := range activities {
+			t.Logf("  %s %s", a.Timestamp.Format("15:04"), a.Title)
+		}
+		t.Fatalf("Expected %d activities, got %d", len(expected), len(activities))
+	}
+
+	for i, exp := range expected {
+		a := activities[i]
+		if a.Metadata["action"] != exp.action {
+			t.Errorf("Activity %d: expected action '%s', got '%s'", i, exp.action, a.Metadata["action"])
+		}
+		if a.Title != exp.title {
+			t.Errorf("Activity %d: expected title '%s', got '%s'", i, exp.title, a.Title)
+		}
+		if exp.branch != "" && a.Metadata["branch"] != exp.branch {
+			t.Errorf("Activity %d: expected branch '%s', got '%s'", i, exp.branch, a.Metadata["branch"])
+		}
+		if a.Type != timeline.ActivityTypeGitCommit {
+			t.Errorf("Activity %d: expected type git_commit, got %s", i, a.Type)
+		}
+		if a.Source != "local_git" {
+			t.Errorf("Activity %d: expected source 'local_git', got '%s'", i, a.Source)
+		}
+		if a.Metadata["repository"] != "acme/project" {
+			t.Errorf("Activity %d: expected repository 'acme/project', got '%s'", i, a.Metadata["repository"])
+		}
+	}
+
+	if activities[0].ID != "local-git-"+sha('2')[:8] {
+		t.Errorf("Expected commit ID based on SHA, got '%s'", activities[0].ID)
+	}
+}
+
+func TestLocalGitConnector_TestConnection(t *testing.T) {
+	connector := NewLocalGitConnector()
+	root := t.TempDir()
+
+	if err := connector.Configure(map[string]interface{}{"roots": root, "emails": "me@example.com"}); err != nil {
+		t.Fatalf("Failed to configure: %v", err)
+	}
+	if err := connector.TestConnection(context.Background()); err == nil {
+		t.Error("Expected error when no repositories exist")
+	}
+
+	writeFixtureRepo(t, filepath.Join(root, "repo"), "", nil, nil)
+	if err := connector.TestConnection(context.Background()); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+}
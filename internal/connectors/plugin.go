@@ -0,0 +1,287 @@
+package connectors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arkeo/arkeo/internal/timeline"
+)
+
+// PluginCommandKey is the config key holding the executable a plugin runs.
+// A connector configured with a command becomes a plugin.
+const PluginCommandKey = "command"
+
+// describeTimeout bounds the --describe call, which runs the first time the
+// plugin's description or configuration fields are needed
+const describeTimeout = 5 * time.Second
+
+// PluginConnector runs an external executable to fetch activities. The
+// executable is called with the date (YYYY-MM-DD) as its only argument and
+// the connector configuration as a JSON object on stdin, and prints a JSON
+// array of activities in the webhook format to stdout. Called with
+// --describe, it prints a PluginDescription.
+type PluginConnector struct {
+	*BaseConnector
+	command string
+
+	describeOnce sync.Once
+	described    *PluginDescription
+	describeErr  error
+}
+
+// PluginDescription is what a plugin prints when called with --describe
+type PluginDescription struct {
+	Description string        `json:"description"`
+	Config      []ConfigField `json:"config"`
+
+	// Increased by the plugin when its activities change shape, so that
+	// days cached from an older version are fetched again
+	OutputVersion int `json:"output_version,omitempty"`
+}
+
+// NewPluginConnector creates a connector named name that runs command
+func NewPluginConnector(name, command string) *PluginConnector {
+	return &PluginConnector{
+		BaseConnector: NewBaseConnector(name, fmt.Sprintf("Plugin %s", command)),
+		command:       command,
+	}
+}
+
+// DiscoverPlugins returns a connector for every executable file in dir,
+// named after the file without its extension. A missing directory is not
+// an error.
+func DiscoverPlugins(dir string) ([]*PluginConnector, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var plugins []*PluginConnector
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		// Follow symlinks so plugins can be linked from elsewhere
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0111 == 0 {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		plugins = append(plugins, NewPluginConnector(name, path))
+	}
+
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name() < plugins[j].Name() })
+	return plugins, nil
+}
+
+// Command returns the executable the plugin runs
+func (p *PluginConnector) Command() string {
+	return p.command
+}
+
+// Description returns the description declared by the plugin, if any
+func (p *PluginConnector) Description() string {
+	if desc, err := p.describe(); err == nil && desc.Description != "" {
+		return desc.Description
+	}
+	return p.BaseConnector.Description()
+}
+
+// OutputVersion returns the output version declared by the plugin
+func (p *PluginConnector) OutputVersion() int {
+	if desc, err := p.describe(); err == nil {
+		return desc.OutputVersion
+	}
+	return 0
+}
+
+// GetRequiredConfig returns the configuration fields declared by the plugin
+func (p *PluginConnector) GetRequiredConfig() []ConfigField {
+	requiredFields := []ConfigField{
+		{
+			Key:         PluginCommandKey,
+			Type:        "string",
+			Required:    false,
+			Description: "Executable to run (defaults to the file found in the plugins directory)",
+		},
+	}
+
+	if desc, err := p.describe(); err == nil {
+		for _, field := range desc.Config {
+			if field.Key != "" && field.Key != PluginCommandKey {
+				requiredFields = append(requiredFields, field)
+			}
+		}
+	}
+
+	return MergeConfigFields(requiredFields)
+}
+
+// ValidateConfig validates the plugin configuration
+func (p *PluginConnector) ValidateConfig(config map[string]interface{}) error {
+	return ValidateConfigFields(config, p.GetRequiredConfig())
+}
+
+// Configure sets up the plugin. A command in the config replaces the
+// executable the plugin was created with.
+func (p *PluginConnector) Configure(config map[string]interface{}) error {
+	if err := p.setCommand(config); err != nil {
+		return err
+	}
+	if err := p.ValidateConfig(config); err != nil {
+		return err
+	}
+	return p.BaseConnector.Configure(config)
+}
+
+// Preconfigure sets up the plugin like Configure but without checking the
+// config against the fields the plugin declares, so the executable is not
+// run. It is meant for plugins that may never be used, such as disabled ones.
+func (p *PluginConnector) Preconfigure(config map[string]interface{}) error {
+	if err := p.setCommand(config); err != nil {
+		return err
+	}
+	return p.BaseConnector.Configure(config)
+}
+
+// setCommand applies a command given in the config, forgetting the
+// description of the previous executable
+func (p *PluginConnector) setCommand(config map[string]interface{}) error {
+	if command, ok := config[PluginCommandKey].(string); ok && command != "" && command != p.command {
+		p.command = command
+		p.describeOnce = sync.Once{}
+		p.described, p.describeErr = nil, nil
+	}
+	if p.command == "" {
+		return fmt.Errorf("command is required")
+	}
+	return nil
+}
+
+// TestConnection runs the plugin for yesterday and checks its output
+func (p *PluginConnector) TestConnection(ctx context.Context) error {
+	_, err := p.GetActivities(ctx, time.Now().AddDate(0, 0, -1))
+	return err
+}
+
+// GetActivities runs the plugin for the specified date
+func (p *PluginConnector) GetActivities(ctx context.Context, date time.Time) ([]timeline.Activity, error) {
+	dateStr := date.Format("2006-01-02")
+
+	input, err := json.Marshal(p.pluginConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode plugin config: %w", err)
+	}
+
+	if p.IsDebugMode() {
+		log.Printf("Plugin Debug: Running %s %s", p.command, dateStr)
+	}
+
+	output, err := p.run(ctx, bytes.NewReader(input), dateStr)
+	if err != nil {
+		return nil, err
+	}
+
+	var pluginActivities []WebhookActivity
+	if err := json.Unmarshal(output, &pluginActivities); err != nil {
+		return nil, fmt.Errorf("failed to parse plugin output: %w", err)
+	}
+
+	var activities []timeline.Activity
+	for _, pa := range pluginActivities {
+		activity, err := pa.toActivity(p.Name(), timeline.ActivityTypeCustom, p.Name()+" activity", map[string]string{
+			"plugin": p.Name(),
+		})
+		if err != nil {
+			if p.IsDebugMode() {
+				log.Printf("Plugin Debug: Skipping invalid activity: %v", err)
+			}
+			continue
+		}
+		activities = append(activities, activity)
+	}
+
+	if p.IsDebugMode() {
+		log.Printf("Plugin Debug: %s returned %d activities", p.Name(), len(activities))
+	}
+
+	return limitPerDay(activities, p.GetConfigInt(CommonConfigKeys.MaxItems)), nil
+}
+
+// describe runs the plugin with --describe once and remembers the result.
+// Plugins that don't support it have no configuration fields of their own.
+func (p *PluginConnector) describe() (*PluginDescription, error) {
+	p.describeOnce.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), describeTimeout)
+		defer cancel()
+
+		output, err := p.run(ctx, nil, "--describe")
+		if err != nil {
+			p.describeErr = err
+			return
+		}
+
+		var desc PluginDescription
+		if err := json.Unmarshal(output, &desc); err != nil {
+			p.describeErr = fmt.Errorf("failed to parse --describe output: %w", err)
+			return
+		}
+		p.described = &desc
+	})
+	return p.described, p.describeErr
+}
+
+// run executes the plugin and returns its stdout. The process is killed
+// when ctx is done, so the caller's timeout applies to the plugin.
+func (p *PluginConnector) run(ctx context.Context, stdin *bytes.Reader, arg string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, p.command, arg)
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Don't wait forever for children that keep stdout open after a kill
+	cmd.WaitDelay = time.Second
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("plugin %s: %w", p.Name(), ctx.Err())
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("plugin %s failed: %w: %s", p.Name(), err, msg)
+		}
+		return nil, fmt.Errorf("plugin %s failed: %w", p.Name(), err)
+	}
+
+	if p.IsDebugMode() && stderr.Len() > 0 {
+		log.Printf("Plugin Debug: %s stderr: %s", p.Name(), strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// pluginConfig returns the configuration passed to the plugin on stdin
+func (p *PluginConnector) pluginConfig() map[string]interface{} {
+	config := make(map[string]interface{}, len(p.config))
+	for k, v := range p.config {
+		if k != PluginCommandKey {
+			config[k] = v
+		}
+	}
+	return config
+}
@@ -0,0 +1,174 @@
+package connectors
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writePlugin writes an executable shell script to dir
+func writePlugin(t *testing.T, dir, name, script string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin tests use shell scripts")
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatalf("Failed to write plugin: %v", err)
+	}
+	return path
+}
+
+const echoPlugin = `if [ "$1" = "--describe" ]; then
+  echo '{"description": "Echo plugin", "output_version": 2, "config": [{"key": "greeting", "type": "string", "required": true, "description": "Title prefix"}]}'
+  exit 0
+fi
+config=$(cat)
+greeting=$(echo "$config" | sed 's/.*"greeting":"\([^"]*\)".*/\1/')
+cat <<EOF
+[
+  {"timestamp": "${1}T09:30:00Z", "title": "$greeting $1", "description": "from stdin", "metadata": {"count": 3}},
+  {"timestamp": "${1}T10:00:00Z", "title": "Meeting", "type": "calendar"},
+  {"timestamp": "not a time", "title": "Broken"}
+]
+EOF
+`
+
+func TestDiscoverPlugins(t *testing.T) {
+	dir := t.TempDir()
+	writePlugin(t, dir, "echo.sh", echoPlugin)
+	writePlugin(t, dir, ".hidden", "echo '[]'\n")
+	if err := os.WriteFile(filepath.Join(dir, "README"), []byte("not a plugin"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	plugins, err := DiscoverPlugins(dir)
+	if err != nil {
+		t.Fatalf("DiscoverPlugins failed: %v", err)
+	}
+	if len(plugins) != 1 {
+		t.Fatalf("Expected 1 plugin, got %d", len(plugins))
+	}
+	if plugins[0].Name() != "echo" {
+		t.Errorf("Expected plugin named echo, got %s", plugins[0].Name())
+	}
+	if plugins[0].Description() != "Echo plugin" {
+		t.Errorf("Expected description from --describe, got %q", plugins[0].Description())
+	}
+	if plugins[0].OutputVersion() != 2 {
+		t.Errorf("Expected output version 2 from --describe, got %d", plugins[0].OutputVersion())
+	}
+
+	plugins, err = DiscoverPlugins(filepath.Join(dir, "missing"))
+	if err != nil || len(plugins) != 0 {
+		t.Errorf("Expected no plugins and no error for a missing directory, got %d, %v", len(plugins), err)
+	}
+}
+
+func TestPluginConnector_GetRequiredConfig(t *testing.T) {
+	plugin := NewPluginConnector("echo", writePlugin(t, t.TempDir(), "echo", echoPlugin))
+
+	var found bool
+	for _, field := range plugin.GetRequiredConfig() {
+		if field.Key == "greeting" {
+			found = true
+			if !field.Required || field.Type != "string" {
+				t.Errorf("Unexpected greeting field: %+v", field)
+			}
+		}
+	}
+	if !found {
+		t.Error("Expected the greeting field declared by the plugin")
+	}
+
+	if err := plugin.Configure(map[string]interface{}{}); err == nil {
+		t.Error("Expected an error when a field required by the plugin is missing")
+	}
+}
+
+func TestPluginConnector_Preconfigure(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "described")
+	plugin := NewPluginConnector("echo", writePlugin(t, dir, "echo", "touch "+marker+"\n"+echoPlugin))
+
+	if err := plugin.Preconfigure(map[string]interface{}{"log_level": "info"}); err != nil {
+		t.Fatalf("Preconfigure failed: %v", err)
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("Expected Preconfigure not to run the plugin")
+	}
+
+	if err := plugin.Configure(map[string]interface{}{"greeting": "Hello"}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	if _, err := os.Stat(marker); err != nil {
+		t.Error("Expected Configure to run the plugin with --describe")
+	}
+}
+
+func TestPluginConnector_GetActivities(t *testing.T) {
+	plugin := NewPluginConnector("echo", writePlugin(t, t.TempDir(), "echo", echoPlugin))
+	if err := plugin.Configure(map[string]interface{}{"greeting": "Hello"}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	activities, err := plugin.GetActivities(context.Background(), date)
+	if err != nil {
+		t.Fatalf("GetActivities failed: %v", err)
+	}
+
+	if len(activities) != 2 {
+		t.Fatalf("Expected 2 activities (invalid one skipped), got %d", len(activities))
+	}
+	first := activities[0]
+	if first.Title != "Hello 2024-01-15" {
+		t.Errorf("Expected the config to reach the plugin on stdin, got title %q", first.Title)
+	}
+	if first.Source != "echo" || first.Type != "custom" {
+		t.Errorf("Expected source echo and type custom, got %s and %s", first.Source, first.Type)
+	}
+	if first.Metadata["count"] != "3" || first.Metadata["plugin"] != "echo" {
+		t.Errorf("Unexpected metadata: %v", first.Metadata)
+	}
+	if !first.Timestamp.Equal(time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected timestamp: %v", first.Timestamp)
+	}
+	if activities[1].Type != "calendar" {
+		t.Errorf("Expected the type given by the plugin, got %s", activities[1].Type)
+	}
+	if first.ID == activities[1].ID {
+		t.Errorf("Expected unique IDs, got %s twice", first.ID)
+	}
+}
+
+func TestPluginConnector_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	failing := NewPluginConnector("failing", writePlugin(t, dir, "failing", "echo 'token expired' >&2\nexit 3\n"))
+	_, err := failing.GetActivities(context.Background(), time.Now())
+	if err == nil || !strings.Contains(err.Error(), "token expired") {
+		t.Errorf("Expected the plugin's stderr in the error, got %v", err)
+	}
+
+	garbage := NewPluginConnector("garbage", writePlugin(t, dir, "garbage", "echo 'not json'\n"))
+	if _, err := garbage.GetActivities(context.Background(), time.Now()); err == nil {
+		t.Error("Expected an error for invalid plugin output")
+	}
+
+	slow := NewPluginConnector("slow", writePlugin(t, dir, "slow", "exec sleep 10\n"))
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = slow.GetActivities(ctx, time.Now())
+	if err == nil {
+		t.Error("Expected a timeout error")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the plugin to be killed at the timeout, took %v", elapsed)
+	}
+}
@@ -0,0 +1,49 @@
+package connectors
+
+import (
+	"context"
+	"time"
+
+	"github.com/arkeo/arkeo/internal/timeline"
+)
+
+// RangeConnector is an optional interface for connectors that can fetch
+// several days with a single request instead of one round-trip per day.
+// Callers should check for it with a type assertion and fall back to
+// calling GetActivities for each day when it isn't implemented.
+type RangeConnector interface {
+	Connector
+
+	// GetActivitiesRange retrieves activities for every day from start to
+	// end, both inclusive. Activities are returned unsorted; callers split
+	// them back into days using the local date of each activity.
+	GetActivitiesRange(ctx context.Context, start, end time.Time) ([]timeline.Activity, error)
+}
+
+// localDayRange returns the time window covering the local calendar days of
+// start through end: [start 00:00, end+1 00:00) in the local timezone.
+func localDayRange(start, end time.Time) (time.Time, time.Time) {
+	from := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.Local)
+	to := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, 1)
+	return from, to
+}
+
+// limitPerDay keeps at most maxItems activities for each local day, so that
+// max_items means the same for a range request as for a single day.
+func limitPerDay(activities []timeline.Activity, maxItems int) []timeline.Activity {
+	if maxItems <= 0 {
+		return activities
+	}
+
+	perDay := make(map[string]int)
+	limited := make([]timeline.Activity, 0, len(activities))
+	for _, a := range activities {
+		day := a.Timestamp.In(time.Local).Format("2006-01-02")
+		if perDay[day] >= maxItems {
+			continue
+		}
+		perDay[day]++
+		limited = append(limited, a)
+	}
+	return limited
+}
@@ -0,0 +1,185 @@
+package connectors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arkeo/arkeo/internal/timeline"
+	"github.com/arkeo/arkeo/internal/utils"
+)
+
+func TestLocalDayRange(t *testing.T) {
+	start := time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 6, 7, 0, 0, 0, 0, time.UTC)
+
+	from, to := localDayRange(start, end)
+
+	expectedFrom := time.Date(2024, 6, 3, 0, 0, 0, 0, time.Local)
+	expectedTo := time.Date(2024, 6, 8, 0, 0, 0, 0, time.Local)
+	if !from.Equal(expectedFrom) {
+		t.Errorf("Expected from %v, got %v", expectedFrom, from)
+	}
+	if !to.Equal(expectedTo) {
+		t.Errorf("Expected to %v, got %v", expectedTo, to)
+	}
+}
+
+func TestLimitPerDay(t *testing.T) {
+	activities := []timeline.Activity{
+		{ID: "1", Timestamp: time.Date(2024, 6, 3, 9, 0, 0, 0, time.Local)},
+		{ID: "2", Timestamp: time.Date(2024, 6, 3, 10, 0, 0, 0, time.Local)},
+		{ID: "3", Timestamp: time.Date(2024, 6, 3, 11, 0, 0, 0, time.Local)},
+		{ID: "4", Timestamp: time.Date(2024, 6, 4, 9, 0, 0, 0, time.Local)},
+	}
+
+	limited := limitPerDay(activities, 2)
+	if len(limited) != 3 {
+		t.Fatalf("Expected 3 activities, got %d", len(limited))
+	}
+	if limited[2].ID != "4" {
+		t.Errorf("Expected activity 4 to be kept, got %s", limited[2].ID)
+	}
+
+	if got := limitPerDay(activities, 0); len(got) != len(activities) {
+		t.Errorf("Expected no limit with maxItems 0, got %d activities", len(got))
+	}
+}
+
+func TestRangeConnectorImplementations(t *testing.T) {
+	var _ RangeConnector = NewGitLabConnector()
+	var _ RangeConnector = NewGitHubConnector()
+	var _ RangeConnector = NewYouTrackConnector()
+	var _ RangeConnector = NewCalendarConnector()
+	var _ RangeConnector = NewBrowserHistoryConnector()
+}
+
+// withLocal runs the test with time.Local set to a zone far from UTC
+func withLocal(t *testing.T, name string) {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("Time zone %s not available: %v", name, err)
+	}
+	previous := time.Local
+	time.Local = loc
+	t.Cleanup(func() { time.Local = previous })
+}
+
+// boundaryTimes are activities around the local midnights of 2024-06-04 in
+// Asia/Tokyo (UTC+9), newest first
+var boundaryTimes = []string{
+	"2024-06-04T16:00:00Z", // 06-05 01:00 local
+	"2024-06-04T14:00:00Z", // 06-04 23:00 local
+	"2024-06-03T15:30:00Z", // 06-04 00:30 local
+	"2024-06-03T14:30:00Z", // 06-03 23:30 local
+}
+
+// activityIDs returns the sorted IDs of activities
+func activityIDs(activities []timeline.Activity) []string {
+	ids := make([]string, 0, len(activities))
+	for _, a := range activities {
+		ids = append(ids, a.ID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// checkSameDay checks that fetching day on its own returns the activities
+// of that day in a range around it, and that they are the expected ones
+func checkSameDay(t *testing.T, conn RangeConnector, expected []string) {
+	t.Helper()
+	day := time.Date(2024, 6, 4, 0, 0, 0, 0, time.UTC)
+
+	single, err := conn.GetActivities(context.Background(), day)
+	if err != nil {
+		t.Fatalf("GetActivities failed: %v", err)
+	}
+	days := []time.Time{day.AddDate(0, 0, -1), day, day.AddDate(0, 0, 1)}
+	ranged, err := conn.GetActivitiesRange(context.Background(), days[0], days[2])
+	if err != nil {
+		t.Fatalf("GetActivitiesRange failed: %v", err)
+	}
+	fromRange := utils.SplitActivitiesByDay(ranged, days)[1].Activities
+
+	if got, want := activityIDs(single), activityIDs(fromRange); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected the single day to match the range, got %v and %v", got, want)
+	}
+	if got := activityIDs(single); strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestGitLabConnector_SingleDayMatchesRange(t *testing.T) {
+	withLocal(t, "Asia/Tokyo")
+
+	var events []GitLabEvent
+	for i, createdAt := range boundaryTimes {
+		events = append(events, GitLabEvent{
+			ID:         i + 1,
+			ActionName: "pushed to",
+			CreatedAt:  createdAt,
+			PushData:   &GitLabPushData{CommitCount: 1, Action: "pushed", RefType: "branch", Ref: "main", CommitTitle: "Commit"},
+		})
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") != "1" {
+			fmt.Fprint(w, "[]")
+			return
+		}
+		json.NewEncoder(w).Encode(events)
+	}))
+	defer server.Close()
+
+	conn := NewGitLabConnector()
+	if err := conn.Configure(map[string]interface{}{"gitlab_url": server.URL, "username": "jdoe", "access_token": "token"}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	checkSameDay(t, conn, []string{"gitlab-push-2", "gitlab-push-3"})
+}
+
+// githubSearch answers GitHub commit searches with the commits of
+// boundaryTimes in the requested author-date window, and finds no issues
+type githubSearch struct{}
+
+func (githubSearch) RoundTrip(r *http.Request) (*http.Response, error) {
+	body := `{"items": []}`
+	if r.URL.Path == "/search/commits" {
+		_, window, _ := strings.Cut(r.URL.Query().Get("q"), "author-date:")
+		since, until, _ := strings.Cut(window, "..")
+		from, _ := time.Parse(time.RFC3339, since)
+		to, _ := time.Parse(time.RFC3339, until)
+
+		var items []map[string]interface{}
+		for i, date := range boundaryTimes {
+			if at, _ := time.Parse(time.RFC3339, date); !at.Before(from) && at.Before(to) {
+				items = append(items, map[string]interface{}{
+					"sha":    fmt.Sprintf("%08d", i+1),
+					"commit": map[string]interface{}{"message": "Commit", "author": map[string]string{"date": date}},
+				})
+			}
+		}
+		data, _ := json.Marshal(map[string]interface{}{"items": items})
+		body = string(data)
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Header: http.Header{}, Request: r}, nil
+}
+
+func TestGitHubConnector_SingleDayMatchesRange(t *testing.T) {
+	withLocal(t, "Asia/Tokyo")
+
+	conn := NewGitHubConnector()
+	if err := conn.Configure(map[string]interface{}{"username": "jdoe", "token": "token"}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	conn.httpClient.Transport = githubSearch{}
+
+	checkSameDay(t, conn, []string{"github-commit-00000002", "github-commit-00000003"})
+}
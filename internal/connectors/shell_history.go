@@ -0,0 +1,706 @@
+package connectors
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arkeo/arkeo/internal/timeline"
+)
+
+// defaultShellExcludePattern drops commands that most likely contain secrets
+// typed on the command line.
+const defaultShellExcludePattern = `(?i)(password|passwd|secret|token|api[_-]?key|authorization:|bearer )`
+
+// Pre-compiled regexps used by the shell history parsers.
+var (
+	// zshExtendedRegex matches zsh EXTENDED_HISTORY lines: ": <ts>:<duration>;<command>"
+	zshExtendedRegex = regexp.MustCompile(`^: (\d+):(\d+);(.*)$`)
+
+	// bashTimestampRegex matches the "#<ts>" lines bash writes when HISTTIMEFORMAT is set.
+	bashTimestampRegex = regexp.MustCompile(`^#(\d{9,})$`)
+)
+
+// ShellHistoryConnector implements the Connector interface for shell history
+// files (zsh, bash and fish).
+type ShellHistoryConnector struct {
+	*BaseConnector
+}
+
+// NewShellHistoryConnector creates a new shell history connector.
+func NewShellHistoryConnector() *ShellHistoryConnector {
+	return &ShellHistoryConnector{
+		BaseConnector: NewBaseConnector(
+			"shell_history",
+			"Fetches terminal sessions from zsh, bash and fish history files",
+		),
+	}
+}
+
+// GetRequiredConfig returns the required configuration for shell history.
+func (s *ShellHistoryConnector) GetRequiredConfig() []ConfigField {
+	return MergeConfigFields([]ConfigField{
+		{
+			Key:         "shells",
+			Type:        "string",
+			Required:    false,
+			Description: "Comma-separated list of shells to read history from (zsh, bash, fish)",
+			Default:     "zsh,bash,fish",
+		},
+		{
+			Key:         "history_files",
+			Type:        "string",
+			Required:    false,
+			Description: "Comma-separated history files to read instead of the defaults (prefix with zsh:, bash: or fish: if the name doesn't tell)",
+			Default:     "",
+		},
+		{
+			Key:         "session_gap_minutes",
+			Type:        "int",
+			Required:    false,
+			Description: "Start a new session when no command was run for N minutes",
+			Default:     10,
+		},
+		{
+			Key:         "min_commands",
+			Type:        "int",
+			Required:    false,
+			Description: "Minimum number of commands for a session to be shown (0 = show all)",
+			Default:     1,
+		},
+		{
+			Key:         "include_pattern",
+			Type:        "string",
+			Required:    false,
+			Description: "Only keep commands matching this regular expression (empty = keep all)",
+			Default:     "",
+		},
+		{
+			Key:         "exclude_pattern",
+			Type:        "string",
+			Required:    false,
+			Description: "Drop commands matching this regular expression, e.g. ones containing secrets",
+			Default:     defaultShellExcludePattern,
+		},
+	})
+}
+
+// ValidateConfig validates the shell history configuration.
+func (s *ShellHistoryConnector) ValidateConfig(config map[string]interface{}) error {
+	if err := ValidateConfigFields(config, s.GetRequiredConfig()); err != nil {
+		return err
+	}
+
+	for _, key := range []string{"include_pattern", "exclude_pattern"} {
+		pattern, _ := config[key].(string)
+		if pattern == "" {
+			continue
+		}
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// TestConnection tests if at least one history file is readable.
+func (s *ShellHistoryConnector) TestConnection(ctx context.Context) error {
+	files := s.getHistoryFiles()
+	for _, f := range files {
+		if _, err := os.Stat(f.path); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("no shell history files found")
+}
+
+// GetActivities retrieves terminal sessions for the specified date.
+func (s *ShellHistoryConnector) GetActivities(ctx context.Context, date time.Time) ([]timeline.Activity, error) {
+	if s.IsDebugMode() {
+		log.Printf("ShellHistory Debug: Fetching activities for date %s", date.Format("2006-01-02"))
+	}
+
+	include, exclude, err := s.getPatterns()
+	if err != nil {
+		return nil, err
+	}
+
+	files := s.getHistoryFiles()
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no shell history files configured")
+	}
+
+	gap := time.Duration(s.GetConfigInt("session_gap_minutes")) * time.Minute
+	if gap <= 0 {
+		gap = 10 * time.Minute
+	}
+	minCommands := s.GetConfigInt("min_commands")
+
+	// Normalize to local day.
+	localDate := date.In(time.Local)
+	startOfDay := time.Date(localDate.Year(), localDate.Month(), localDate.Day(), 0, 0, 0, 0, localDate.Location())
+	endOfDay := startOfDay.Add(24 * time.Hour)
+
+	home, _ := os.UserHomeDir()
+
+	var commands []shellCommand
+	for _, f := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		fileCommands, err := readShellHistoryFile(f)
+		if err != nil {
+			if s.IsDebugMode() {
+				log.Printf("ShellHistory Debug: Failed to read %s: %v", f.path, err)
+			}
+			continue
+		}
+
+		// Working directories are inferred over the whole file since a "cd"
+		// made before midnight still applies to commands run after it.
+		inferWorkingDirs(fileCommands, home)
+
+		count := 0
+		for _, c := range fileCommands {
+			if c.timestamp.Before(startOfDay) || !c.timestamp.Before(endOfDay) {
+				continue
+			}
+			if include != nil && !include.MatchString(c.command) {
+				continue
+			}
+			if exclude != nil && exclude.MatchString(c.command) {
+				continue
+			}
+			commands = append(commands, c)
+			count++
+		}
+
+		if s.IsDebugMode() {
+			log.Printf("ShellHistory Debug: Found %d commands in %s history %s", count, f.shell, f.path)
+		}
+	}
+
+	sort.SliceStable(commands, func(i, j int) bool {
+		return commands[i].timestamp.Before(commands[j].timestamp)
+	})
+
+	activities := groupCommandsIntoSessions(commands, gap, minCommands)
+
+	if s.IsDebugMode() {
+		log.Printf("ShellHistory Debug: Total activities after grouping: %d", len(activities))
+	}
+
+	return activities, nil
+}
+
+// getPatterns compiles the include/exclude patterns. A nil regexp means the
+// pattern is not set.
+func (s *ShellHistoryConnector) getPatterns() (include, exclude *regexp.Regexp, err error) {
+	if p := s.GetConfigString("include_pattern"); p != "" {
+		if include, err = regexp.Compile(p); err != nil {
+			return nil, nil, fmt.Errorf("invalid include_pattern: %w", err)
+		}
+	}
+
+	p := defaultShellExcludePattern
+	if _, ok := s.GetConfig()["exclude_pattern"]; ok {
+		p = s.GetConfigString("exclude_pattern")
+	}
+	if p != "" {
+		if exclude, err = regexp.Compile(p); err != nil {
+			return nil, nil, fmt.Errorf("invalid exclude_pattern: %w", err)
+		}
+	}
+
+	return include, exclude, nil
+}
+
+// getConfigShells returns the list of shells to read history from.
+func (s *ShellHistoryConnector) getConfigShells() []string {
+	raw := s.GetConfigString("shells")
+	if raw == "" {
+		return []string{"zsh", "bash", "fish"}
+	}
+	var shells []string
+	for _, sh := range strings.Split(raw, ",") {
+		sh = strings.TrimSpace(strings.ToLower(sh))
+		if sh != "" {
+			shells = append(shells, sh)
+		}
+	}
+	return shells
+}
+
+// getHistoryFiles returns the history files to read, either from the
+// history_files option or from the default locations of the configured shells.
+func (s *ShellHistoryConnector) getHistoryFiles() []shellHistoryFile {
+	if raw := s.GetConfigString("history_files"); raw != "" {
+		var files []shellHistoryFile
+		for _, entry := range strings.Split(raw, ",") {
+			entry = strings.TrimSpace(entry)
+			if entry == "" {
+				continue
+			}
+			shell, path, ok := strings.Cut(entry, ":")
+			if !ok || (shell != "zsh" && shell != "bash" && shell != "fish") {
+				path = entry
+				shell = guessShellFromPath(entry)
+			}
+			files = append(files, shellHistoryFile{shell: shell, path: expandHome(path)})
+		}
+		return files
+	}
+
+	return defaultHistoryFiles(s.getConfigShells())
+}
+
+// shellHistoryFile is a history file together with its format.
+type shellHistoryFile struct {
+	shell string
+	path  string
+}
+
+// shellCommand is a single command read from a history file.
+type shellCommand struct {
+	shell     string
+	command   string
+	timestamp time.Time
+	duration  time.Duration
+	cwd       string
+}
+
+// defaultHistoryFiles returns the existing default history files for the
+// given shells.
+func defaultHistoryFiles(shells []string) []shellHistoryFile {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+
+	var files []shellHistoryFile
+	seen := make(map[string]bool)
+	add := func(shell, path string) {
+		if path == "" || seen[path] {
+			return
+		}
+		if _, err := os.Stat(path); err != nil {
+			return
+		}
+		seen[path] = true
+		files = append(files, shellHistoryFile{shell: shell, path: path})
+	}
+
+	for _, shell := range shells {
+		switch shell {
+		case "zsh":
+			if zdot := os.Getenv("ZDOTDIR"); zdot != "" {
+				add("zsh", filepath.Join(zdot, ".zsh_history"))
+			}
+			add("zsh", filepath.Join(home, ".zsh_history"))
+			add("zsh", filepath.Join(home, ".zhistory"))
+		case "bash":
+			add("bash", filepath.Join(home, ".bash_history"))
+		case "fish":
+			dataHome := os.Getenv("XDG_DATA_HOME")
+			if dataHome == "" {
+				dataHome = filepath.Join(home, ".local", "share")
+			}
+			add("fish", filepath.Join(dataHome, "fish", "fish_history"))
+		}
+	}
+
+	return files
+}
+
+// guessShellFromPath guesses the history format from a file name.
+func guessShellFromPath(path string) string {
+	base := strings.ToLower(filepath.Base(path))
+	switch {
+	case strings.Contains(base, "fish"):
+		return "fish"
+	case strings.Contains(base, "zsh") || strings.Contains(base, "zhistory"):
+		return "zsh"
+	default:
+		return "bash"
+	}
+}
+
+// readShellHistoryFile reads and parses a history file in the given format.
+func readShellHistoryFile(f shellHistoryFile) ([]shellCommand, error) {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	switch f.shell {
+	case "zsh":
+		return parseZshHistory(file)
+	case "fish":
+		return parseFishHistory(file)
+	default:
+		return parseBashHistory(file)
+	}
+}
+
+// newHistoryScanner returns a line scanner that tolerates very long commands.
+func newHistoryScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	return scanner
+}
+
+// parseZshHistory parses a zsh history file written with EXTENDED_HISTORY.
+// Multi-line commands are stored with a trailing backslash on every line but
+// the last. Lines without a timestamp header are skipped.
+func parseZshHistory(r io.Reader) ([]shellCommand, error) {
+	var commands []shellCommand
+	var current *shellCommand
+
+	scanner := newHistoryScanner(r)
+	for scanner.Scan() {
+		line := unmetafyZsh(scanner.Text())
+
+		if current != nil {
+			// Continuation of a multi-line command
+			current.command += "\n" + line
+		} else {
+			m := zshExtendedRegex.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			ts, _ := strconv.ParseInt(m[1], 10, 64)
+			dur, _ := strconv.ParseInt(m[2], 10, 64)
+			current = &shellCommand{
+				shell:     "zsh",
+				command:   m[3],
+				timestamp: time.Unix(ts, 0),
+				duration:  time.Duration(dur) * time.Second,
+			}
+		}
+
+		if strings.HasSuffix(current.command, "\\") {
+			current.command = strings.TrimSuffix(current.command, "\\")
+			continue
+		}
+
+		commands = append(commands, *current)
+		current = nil
+	}
+	if current != nil {
+		commands = append(commands, *current)
+	}
+
+	return commands, scanner.Err()
+}
+
+// unmetafyZsh decodes zsh's "metafied" history encoding, where bytes in the
+// 0x83-0x9f range are written as 0x83 followed by the byte XOR 0x20.
+func unmetafyZsh(s string) string {
+	if strings.IndexByte(s, 0x83) < 0 {
+		return s
+	}
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] == 0x83 && i+1 < len(s) {
+			i++
+			b = append(b, s[i]^0x20)
+			continue
+		}
+		b = append(b, s[i])
+	}
+	return string(b)
+}
+
+// parseBashHistory parses a bash history file written with HISTTIMEFORMAT
+// set, where each command is preceded by a "#<unix-ts>" line. Commands
+// without a timestamp are skipped since they can't be placed on a timeline.
+func parseBashHistory(r io.Reader) ([]shellCommand, error) {
+	var commands []shellCommand
+	var current *shellCommand
+
+	scanner := newHistoryScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if m := bashTimestampRegex.FindStringSubmatch(line); m != nil {
+			if current != nil && current.command != "" {
+				commands = append(commands, *current)
+			}
+			ts, _ := strconv.ParseInt(m[1], 10, 64)
+			current = &shellCommand{shell: "bash", timestamp: time.Unix(ts, 0)}
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+		// With lithist, multi-line commands span several lines until the
+		// next timestamp.
+		if current.command == "" {
+			current.command = line
+		} else {
+			current.command += "\n" + line
+		}
+	}
+	if current != nil && current.command != "" {
+		commands = append(commands, *current)
+	}
+
+	return commands, scanner.Err()
+}
+
+// parseFishHistory parses fish's YAML-like history file:
+//
+//	- cmd: git status
+//	  when: 1705315200
+//	  paths:
+//	    - some/file
+func parseFishHistory(r io.Reader) ([]shellCommand, error) {
+	var commands []shellCommand
+	var current *shellCommand
+
+	flush := func() {
+		if current != nil && current.command != "" && !current.timestamp.IsZero() {
+			commands = append(commands, *current)
+		}
+		current = nil
+	}
+
+	scanner := newHistoryScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if cmd, ok := strings.CutPrefix(line, "- cmd: "); ok {
+			flush()
+			current = &shellCommand{shell: "fish", command: unescapeFish(cmd)}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		if when, ok := strings.CutPrefix(strings.TrimSpace(line), "when: "); ok {
+			if ts, err := strconv.ParseInt(strings.TrimSpace(when), 10, 64); err == nil {
+				current.timestamp = time.Unix(ts, 0)
+			}
+		}
+	}
+	flush()
+
+	return commands, scanner.Err()
+}
+
+// unescapeFish reverses the escaping fish applies to commands in its history
+// file ("\\" for a backslash, "\n" for a newline).
+func unescapeFish(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			switch s[i+1] {
+			case '\\':
+				b.WriteByte('\\')
+				i++
+				continue
+			case 'n':
+				b.WriteByte('\n')
+				i++
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// inferWorkingDirs fills in the cwd of each command by following the "cd"
+// and "pushd" commands in the history. Commands in a history file are in
+// order, so the directory changes carry over from one command to the next.
+// A command that changes directory is attributed to the directory it ends up
+// in. Relative changes are only followed once an absolute directory is known.
+func inferWorkingDirs(commands []shellCommand, home string) {
+	var cwd, previous string
+
+	for i := range commands {
+		for _, segment := range splitShellSegments(commands[i].command) {
+			fields := strings.Fields(segment)
+			if len(fields) == 0 || (fields[0] != "cd" && fields[0] != "pushd") {
+				continue
+			}
+
+			var target string
+			if len(fields) > 1 {
+				target = strings.Trim(fields[1], `"'`)
+			}
+
+			next := ""
+			switch {
+			case target == "" || target == "~":
+				next = home
+			case target == "-":
+				next = previous
+			case strings.HasPrefix(target, "~/"):
+				if home != "" {
+					next = filepath.Join(home, target[2:])
+				}
+			case filepath.IsAbs(target):
+				next = filepath.Clean(target)
+			case cwd != "":
+				next = filepath.Join(cwd, target)
+			}
+
+			previous, cwd = cwd, next
+		}
+
+		commands[i].cwd = cwd
+	}
+}
+
+// splitShellSegments splits a command line on "&&", "||" and ";" so that a
+// "cd" in the middle of a chain is seen. Quoting is not taken into account.
+func splitShellSegments(command string) []string {
+	replacer := strings.NewReplacer("&&", "\n", "||", "\n", ";", "\n")
+	return strings.Split(replacer.Replace(command), "\n")
+}
+
+// projectForDir returns where a working directory belongs. Inside a git
+// repository the project is the repository name as the local git connector
+// reports it ("owner/repo" from the origin remote). Outside of one the
+// project is empty and the directory itself is returned instead.
+func projectForDir(dir string) (project, directory string) {
+	if dir == "" {
+		return "", ""
+	}
+	for d := dir; ; d = filepath.Dir(d) {
+		if repo, ok := openGitRepository(d); ok {
+			return repo.name, ""
+		}
+		if parent := filepath.Dir(d); parent == d {
+			break
+		}
+	}
+	return "", dir
+}
+
+// groupCommandsIntoSessions groups consecutive commands into terminal
+// sessions. A new session starts when no command was run for longer than gap
+// or when the inferred project, or the directory outside of repositories,
+// changes. Commands must be sorted by timestamp.
+func groupCommandsIntoSessions(commands []shellCommand, gap time.Duration, minCommands int) []timeline.Activity {
+	if len(commands) == 0 {
+		return nil
+	}
+
+	var activities []timeline.Activity
+	var group []shellCommand
+	var groupProject, groupDirectory string
+
+	flushGroup := func() {
+		if len(group) == 0 {
+			return
+		}
+		if minCommands > 0 && len(group) < minCommands {
+			group = group[:0]
+			return
+		}
+
+		first := group[0]
+		last := group[len(group)-1]
+
+		uniqueCommands := make(map[string]bool)
+		var topCommands []string
+		programs := make(map[string]bool)
+		var programList []string
+		shells := make(map[string]bool)
+		var shellList []string
+		for _, c := range group {
+			summary := strings.SplitN(c.command, "\n", 2)[0]
+			if len(summary) > 60 {
+				summary = summary[:57] + "..."
+			}
+			if len(topCommands) < 3 && !uniqueCommands[summary] {
+				uniqueCommands[summary] = true
+				topCommands = append(topCommands, summary)
+			}
+			if fields := strings.Fields(c.command); len(fields) > 0 && !programs[fields[0]] {
+				programs[fields[0]] = true
+				programList = append(programList, fields[0])
+			}
+			if !shells[c.shell] {
+				shells[c.shell] = true
+				shellList = append(shellList, c.shell)
+			}
+		}
+
+		count := fmt.Sprintf("%d commands", len(group))
+		if len(group) == 1 {
+			count = "1 command"
+		}
+		title := fmt.Sprintf("Terminal session (%s)", count)
+		if groupProject != "" {
+			title = fmt.Sprintf("Terminal session in %s (%s)", groupProject, count)
+		} else if groupDirectory != "" {
+			title = fmt.Sprintf("Terminal session in %s (%s)", filepath.Base(groupDirectory), count)
+		}
+
+		var dur *time.Duration
+		if total := last.timestamp.Add(last.duration).Sub(first.timestamp); total > 0 {
+			d := total
+			dur = &d
+		}
+
+		metadata := map[string]string{
+			"command_count": fmt.Sprintf("%d", len(group)),
+			"programs":      strings.Join(programList, ", "),
+			"shells":        strings.Join(shellList, ", "),
+		}
+		if groupProject != "" {
+			metadata["project"] = groupProject
+			metadata["cwd"] = first.cwd
+		} else if groupDirectory != "" {
+			metadata["directory"] = groupDirectory
+		}
+
+		activities = append(activities, timeline.Activity{
+			ID:          fmt.Sprintf("shell-%s-%d", first.shell, first.timestamp.Unix()),
+			Type:        timeline.ActivityTypeApplication,
+			Title:       title,
+			Description: strings.Join(topCommands, ", "),
+			Timestamp:   first.timestamp,
+			Duration:    dur,
+			Source:      "shell_history",
+			Metadata:    metadata,
+		})
+		group = group[:0]
+	}
+
+	for _, c := range commands {
+		project, directory := projectForDir(c.cwd)
+		if len(group) > 0 {
+			last := group[len(group)-1]
+			if c.timestamp.Sub(last.timestamp.Add(last.duration)) > gap || project != groupProject || directory != groupDirectory {
+				flushGroup()
+			}
+		}
+		if len(group) == 0 {
+			groupProject, groupDirectory = project, directory
+		}
+		group = append(group, c)
+	}
+	flushGroup()
+
+	return activities
+}
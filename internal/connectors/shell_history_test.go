@@ -0,0 +1,319 @@
+package connectors
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arkeo/arkeo/internal/timeline"
+)
+
+func TestNewShellHistoryConnector(t *testing.T) {
+	connector := NewShellHistoryConnector()
+
+	if connector.Name() != "shell_history" {
+		t.Errorf("Expected name 'shell_history', got '%s'", connector.Name())
+	}
+
+	if connector.Description() == "" {
+		t.Error("Expected non-empty description")
+	}
+
+	if connector.IsEnabled() {
+		t.Error("Connector should be disabled by default")
+	}
+}
+
+func TestShellHistoryConnector_GetRequiredConfig(t *testing.T) {
+	connector := NewShellHistoryConnector()
+	config := connector.GetRequiredConfig()
+
+	fieldMap := make(map[string]bool)
+	for _, field := range config {
+		fieldMap[field.Key] = true
+	}
+
+	expectedFields := []string{"shells", "history_files", "session_gap_minutes", "min_commands", "include_pattern", "exclude_pattern"}
+	for _, key := range expectedFields {
+		if !fieldMap[key] {
+			t.Errorf("Expected config field '%s' not found", key)
+		}
+	}
+}
+
+func TestShellHistoryConnector_ValidateConfig(t *testing.T) {
+	connector := NewShellHistoryConnector()
+
+	if err := connector.ValidateConfig(map[string]interface{}{}); err != nil {
+		t.Errorf("Expected no error for empty config, got: %v", err)
+	}
+
+	if err := connector.ValidateConfig(map[string]interface{}{"exclude_pattern": "("}); err == nil {
+		t.Error("Expected error for invalid exclude_pattern")
+	}
+}
+
+func TestParseZshHistory(t *testing.T) {
+	input := ": 1705312800:0;git status\n" +
+		": 1705312860:12;go test ./...\n" +
+		": 1705312900:0;for f in *; do\\\n  echo $f\\\ndone\n" +
+		"plain line without header\n" +
+		": 1705312950:0;echo caf\x83\xa3\n"
+
+	commands, err := parseZshHistory(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	if len(commands) != 4 {
+		t.Fatalf("Expected 4 commands, got %d", len(commands))
+	}
+
+	if commands[0].command != "git status" || commands[0].timestamp.Unix() != 1705312800 {
+		t.Errorf("Unexpected first command: %+v", commands[0])
+	}
+	if commands[1].duration != 12*time.Second {
+		t.Errorf("Expected duration 12s, got %v", commands[1].duration)
+	}
+	if commands[2].command != "for f in *; do\n  echo $f\ndone" {
+		t.Errorf("Expected multi-line command, got %q", commands[2].command)
+	}
+	if commands[3].command != "echo caf\x83" {
+		t.Errorf("Expected unmetafied command, got %q", commands[3].command)
+	}
+	if commands[0].shell != "zsh" {
+		t.Errorf("Expected shell 'zsh', got '%s'", commands[0].shell)
+	}
+}
+
+func TestParseBashHistory(t *testing.T) {
+	input := "ls without timestamp\n" +
+		"#1705312800\n" +
+		"make build\n" +
+		"#1705312900\n" +
+		"echo one\n" +
+		"echo two\n" +
+		"#1705313000\n"
+
+	commands, err := parseBashHistory(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	if len(commands) != 2 {
+		t.Fatalf("Expected 2 commands, got %d", len(commands))
+	}
+	if commands[0].command != "make build" || commands[0].timestamp.Unix() != 1705312800 {
+		t.Errorf("Unexpected first command: %+v", commands[0])
+	}
+	if commands[1].command != "echo one\necho two" {
+		t.Errorf("Expected multi-line command, got %q", commands[1].command)
+	}
+}
+
+func TestParseFishHistory(t *testing.T) {
+	input := `- cmd: git commit -m "fix"
+  when: 1705312800
+  paths:
+    - src/main.go
+- cmd: echo a\\b\nnext
+  when: 1705312900
+- cmd: no timestamp
+`
+
+	commands, err := parseFishHistory(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	if len(commands) != 2 {
+		t.Fatalf("Expected 2 commands, got %d", len(commands))
+	}
+	if commands[0].command != `git commit -m "fix"` || commands[0].timestamp.Unix() != 1705312800 {
+		t.Errorf("Unexpected first command: %+v", commands[0])
+	}
+	if commands[1].command != "echo a\\b\nnext" {
+		t.Errorf("Expected unescaped command, got %q", commands[1].command)
+	}
+}
+
+func TestInferWorkingDirs(t *testing.T) {
+	commands := []shellCommand{
+		{command: "ls"},
+		{command: "cd src"},
+		{command: "cd /work/arkeo"},
+		{command: "cd internal && go test"},
+		{command: "cd -"},
+		{command: "cd ~/notes"},
+		{command: "cd"},
+		{command: "pwd"},
+	}
+
+	inferWorkingDirs(commands, "/home/me")
+
+	expected := []string{"", "", "/work/arkeo", "/work/arkeo/internal", "/work/arkeo", "/home/me/notes", "/home/me", "/home/me"}
+	for i, exp := range expected {
+		if commands[i].cwd != exp {
+			t.Errorf("Command %d (%s): expected cwd '%s', got '%s'", i, commands[i].command, exp, commands[i].cwd)
+		}
+	}
+}
+
+func TestProjectForDir(t *testing.T) {
+	tmpDir := t.TempDir()
+	repo := filepath.Join(tmpDir, "billing")
+	writeFixtureRepo(t, repo, "git@github.com:acme/billing-api.git", nil, nil)
+	unnamed := filepath.Join(tmpDir, "scratch")
+	writeFixtureRepo(t, unnamed, "", nil, nil)
+	plain := filepath.Join(tmpDir, "downloads")
+
+	tests := []struct {
+		dir       string
+		project   string
+		directory string
+	}{
+		{repo, "acme/billing-api", ""},
+		{filepath.Join(repo, "cmd", "server"), "acme/billing-api", ""},
+		{unnamed, "scratch", ""},
+		{plain, "", plain},
+		{"", "", ""},
+	}
+
+	for _, tt := range tests {
+		project, directory := projectForDir(tt.dir)
+		if project != tt.project || directory != tt.directory {
+			t.Errorf("projectForDir(%q): expected (%q, %q), got (%q, %q)", tt.dir, tt.project, tt.directory, project, directory)
+		}
+	}
+}
+
+func TestGroupCommandsIntoSessions(t *testing.T) {
+	baseTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	tmpDir := t.TempDir()
+	repo := filepath.Join(tmpDir, "arkeo")
+	writeFixtureRepo(t, repo, "https://github.com/acme/arkeo.git", nil, nil)
+	other := filepath.Join(tmpDir, "other")
+
+	commands := []shellCommand{
+		{shell: "zsh", command: "git status", timestamp: baseTime, cwd: repo},
+		{shell: "zsh", command: "go test ./...", timestamp: baseTime.Add(2 * time.Minute), duration: time.Minute, cwd: filepath.Join(repo, "internal")},
+		{shell: "zsh", command: "git status", timestamp: baseTime.Add(4 * time.Minute), cwd: repo},
+		// Outside of the repository: new session
+		{shell: "zsh", command: "make", timestamp: baseTime.Add(5 * time.Minute), cwd: other},
+		// Gap > 10 minutes: new session
+		{shell: "bash", command: "ssh server", timestamp: baseTime.Add(30 * time.Minute)},
+	}
+
+	activities := groupCommandsIntoSessions(commands, 10*time.Minute, 1)
+
+	if len(activities) != 3 {
+		t.Fatalf("Expected 3 sessions, got %d", len(activities))
+	}
+
+	first := activities[0]
+	if first.Title != "Terminal session in acme/arkeo (3 commands)" {
+		t.Errorf("Expected title 'Terminal session in acme/arkeo (3 commands)', got '%s'", first.Title)
+	}
+	if first.Metadata["project"] != "acme/arkeo" {
+		t.Errorf("Expected project 'acme/arkeo', got '%s'", first.Metadata["project"])
+	}
+	if first.Description != "git status, go test ./..." {
+		t.Errorf("Expected distinct commands in description, got '%s'", first.Description)
+	}
+	if first.Metadata["programs"] != "git, go" {
+		t.Errorf("Expected programs 'git, go', got '%s'", first.Metadata["programs"])
+	}
+	if first.Duration == nil || *first.Duration != 4*time.Minute {
+		t.Errorf("Expected 4m duration, got %v", first.Duration)
+	}
+	if first.Type != timeline.ActivityTypeApplication {
+		t.Errorf("Expected type 'application', got '%s'", first.Type)
+	}
+	if first.Source != "shell_history" {
+		t.Errorf("Expected source 'shell_history', got '%s'", first.Source)
+	}
+
+	if activities[1].Metadata["project"] != "" {
+		t.Errorf("Expected no project outside of a repository, got '%s'", activities[1].Metadata["project"])
+	}
+	if activities[1].Metadata["directory"] != other {
+		t.Errorf("Expected directory '%s', got '%s'", other, activities[1].Metadata["directory"])
+	}
+	if activities[2].Title != "Terminal session (1 command)" {
+		t.Errorf("Expected title 'Terminal session (1 command)', got '%s'", activities[2].Title)
+	}
+
+	// minCommands=2 drops the single-command sessions
+	activities = groupCommandsIntoSessions(commands, 10*time.Minute, 2)
+	if len(activities) != 1 {
+		t.Errorf("Expected 1 session with minCommands=2, got %d", len(activities))
+	}
+}
+
+func TestShellHistoryConnector_GetActivities(t *testing.T) {
+	tmpDir := t.TempDir()
+	at := func(hour, minute int) int64 {
+		return time.Date(2024, 1, 15, hour, minute, 0, 0, time.Local).Unix()
+	}
+
+	repo := filepath.Join(tmpDir, "arkeo")
+	writeFixtureRepo(t, repo, "git@github.com:acme/arkeo.git", nil, nil)
+
+	zshPath := filepath.Join(tmpDir, ".zsh_history")
+	zsh := fmt.Sprintf(": %d:0;cd %s\n: %d:0;git pull\n: %d:0;export API_TOKEN=hunter2\n: %d:0;yesterday\n",
+		at(9, 0), repo, at(9, 1), at(9, 2), at(9, 0)-24*3600)
+	if err := os.WriteFile(zshPath, []byte(zsh), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	fishPath := filepath.Join(tmpDir, "fish_history")
+	fish := fmt.Sprintf("- cmd: htop\n  when: %d\n- cmd: ls\n  when: %d\n", at(14, 0), at(14, 1))
+	if err := os.WriteFile(fishPath, []byte(fish), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	connector := NewShellHistoryConnector()
+	err := connector.Configure(map[string]interface{}{
+		"history_files": zshPath + ", fish:" + fishPath,
+	})
+	if err != nil {
+		t.Fatalf("Failed to configure: %v", err)
+	}
+
+	activities, err := connector.GetActivities(context.Background(), time.Date(2024, 1, 15, 0, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatalf("GetActivities failed: %v", err)
+	}
+
+	if len(activities) != 2 {
+		t.Fatalf("Expected 2 sessions, got %d", len(activities))
+	}
+
+	// The secret-looking export is dropped by the default exclude pattern
+	if activities[0].Metadata["command_count"] != "2" {
+		t.Errorf("Expected 2 commands in zsh session, got %s", activities[0].Metadata["command_count"])
+	}
+	if activities[0].Metadata["project"] != "acme/arkeo" {
+		t.Errorf("Expected project 'acme/arkeo', got '%s'", activities[0].Metadata["project"])
+	}
+	if strings.Contains(activities[0].Description, "hunter2") {
+		t.Error("Expected secret command to be excluded")
+	}
+	if activities[1].Metadata["shells"] != "fish" {
+		t.Errorf("Expected fish session, got '%s'", activities[1].Metadata["shells"])
+	}
+
+	// include_pattern keeps only matching commands
+	connector.Configure(map[string]interface{}{"include_pattern": "^git "})
+	activities, err = connector.GetActivities(context.Background(), time.Date(2024, 1, 15, 0, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatalf("GetActivities failed: %v", err)
+	}
+	if len(activities) != 1 || activities[0].Description != "git pull" {
+		t.Errorf("Expected only the git command, got %+v", activities)
+	}
+}
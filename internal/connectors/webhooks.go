@@ -2,12 +2,15 @@ package connectors
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"net/url"
+	"sort"
 	"time"
 
 	"github.com/arkeo/arkeo/internal/timeline"
@@ -306,7 +309,9 @@ func (w *WebhooksConnector) fetchActivitiesFromWebhook(ctx context.Context, webh
 	// Convert webhook activities to timeline activities
 	var activities []timeline.Activity
 	for _, wa := range webhookActivities {
-		activity, err := w.convertWebhookActivity(wa, webhook.Name)
+		activity, err := wa.toActivity(w.Name(), "webhook", "Webhook Activity", map[string]string{
+			"webhook_name": webhook.Name,
+		})
 		if err != nil {
 		if w.IsDebugMode() {
 			log.Printf("Warning: skipping invalid activity: %v\n", err)
@@ -323,51 +328,70 @@ func (w *WebhooksConnector) fetchActivitiesFromWebhook(ctx context.Context, webh
 	return activities, nil
 }
 
-// convertWebhookActivity converts a WebhookActivity to a timeline.Activity
-func (w *WebhooksConnector) convertWebhookActivity(wa WebhookActivity, webhookName string) (timeline.Activity, error) {
-	// Parse timestamp
-	timestamp, err := time.Parse(time.RFC3339, wa.Timestamp)
+// toActivity converts an activity in the webhook format, as returned by
+// webhooks and printed by plugins. Activities without a type or title get
+// defaultType and defaultTitle, and their metadata is added to metadata.
+// The ID is derived from the content, so that an activity keeps its ID when
+// the list it came in is reordered.
+func (wa WebhookActivity) toActivity(source string, defaultType timeline.ActivityType, defaultTitle string, metadata map[string]string) (timeline.Activity, error) {
+	timestamp, err := parseWebhookTimestamp(wa.Timestamp)
 	if err != nil {
-		// Try alternative formats
-		if timestamp, err = time.Parse("2006-01-02T15:04:05Z", wa.Timestamp); err != nil {
-			if timestamp, err = time.Parse("2006-01-02 15:04:05", wa.Timestamp); err != nil {
-				return timeline.Activity{}, fmt.Errorf("invalid timestamp format: %s", wa.Timestamp)
-			}
-		}
+		return timeline.Activity{}, err
 	}
 
-	activityType := wa.Type
+	activityType := timeline.ActivityType(wa.Type)
 	if activityType == "" {
-		activityType = "webhook"
+		activityType = defaultType
 	}
 
 	title := wa.Title
 	if title == "" {
-		title = "Webhook Activity"
+		title = defaultTitle
+	}
+
+	// Where the activity came from is part of its identity, e.g. the
+	// webhook name
+	keys := make([]string, 0, len(metadata))
+	for k := range metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\x00%s", timestamp.UTC().Format(time.RFC3339Nano), title)
+	for _, k := range keys {
+		fmt.Fprintf(hash, "\x00%s=%s", k, metadata[k])
 	}
 
-	// Create the activity
-	activity := timeline.Activity{
+	for k, v := range wa.Metadata {
+		if strVal, ok := v.(string); ok {
+			metadata[k] = strVal
+		} else {
+			metadata[k] = fmt.Sprintf("%v", v)
+		}
+	}
+
+	return timeline.Activity{
+		ID:          fmt.Sprintf("%s-%s", source, hex.EncodeToString(hash.Sum(nil))[:16]),
 		Timestamp:   timestamp,
 		Title:       title,
 		Description: wa.Description,
-		Type:        timeline.ActivityType(activityType),
-		Source:      w.Name(),
-		Metadata: map[string]string{
-			"webhook_name": webhookName,
-		},
-	}
+		Type:        activityType,
+		Source:      source,
+		Metadata:    metadata,
+	}, nil
+}
 
-	// Merge additional metadata
-	if wa.Metadata != nil {
-		for k, v := range wa.Metadata {
-			if strVal, ok := v.(string); ok {
-				activity.Metadata[k] = strVal
-			} else {
-				activity.Metadata[k] = fmt.Sprintf("%v", v)
+// parseWebhookTimestamp parses the timestamp of a WebhookActivity, accepting
+// RFC3339 as well as a few common alternatives
+func parseWebhookTimestamp(s string) (time.Time, error) {
+	timestamp, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		// Try alternative formats
+		if timestamp, err = time.Parse("2006-01-02T15:04:05Z", s); err != nil {
+			if timestamp, err = time.Parse("2006-01-02 15:04:05", s); err != nil {
+				return time.Time{}, fmt.Errorf("invalid timestamp format: %s", s)
 			}
 		}
 	}
-
-	return activity, nil
+	return timestamp, nil
 }
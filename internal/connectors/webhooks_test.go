@@ -0,0 +1,49 @@
+package connectors
+
+import (
+	"testing"
+
+	"github.com/arkeo/arkeo/internal/timeline"
+)
+
+func TestWebhookActivity_ToActivity(t *testing.T) {
+	convert := func(wa WebhookActivity, webhook string) timeline.Activity {
+		t.Helper()
+		activity, err := wa.toActivity("webhooks", "webhook", "Webhook Activity", map[string]string{"webhook_name": webhook})
+		if err != nil {
+			t.Fatalf("toActivity failed: %v", err)
+		}
+		return activity
+	}
+
+	deploy := WebhookActivity{Timestamp: "2024-01-15T09:30:00Z", Title: "Deploy", Metadata: map[string]interface{}{"count": 3}}
+	activity := convert(deploy, "ci")
+
+	if activity.Type != "webhook" || activity.Source != "webhooks" {
+		t.Errorf("Expected the default type and the source, got %s and %s", activity.Type, activity.Source)
+	}
+	if activity.Metadata["webhook_name"] != "ci" || activity.Metadata["count"] != "3" {
+		t.Errorf("Unexpected metadata: %v", activity.Metadata)
+	}
+	if untitled := convert(WebhookActivity{Timestamp: deploy.Timestamp}, "ci"); untitled.Title != "Webhook Activity" {
+		t.Errorf("Expected the default title, got %q", untitled.Title)
+	}
+
+	// The ID depends on the content only, not on the position in the list
+	if again := convert(deploy, "ci"); again.ID != activity.ID || activity.ID == "" {
+		t.Errorf("Expected a stable ID, got %q and %q", activity.ID, again.ID)
+	}
+	later := deploy
+	later.Timestamp = "2024-01-15T10:30:00Z"
+	renamed := deploy
+	renamed.Title = "Rollback"
+	for _, other := range []timeline.Activity{convert(later, "ci"), convert(renamed, "ci"), convert(deploy, "nightly")} {
+		if other.ID == activity.ID {
+			t.Errorf("Expected different activities to get different IDs, got %s twice", activity.ID)
+		}
+	}
+
+	if _, err := (WebhookActivity{Timestamp: "yesterday"}).toActivity("webhooks", "webhook", "", map[string]string{}); err == nil {
+		t.Error("Expected an error for an invalid timestamp")
+	}
+}
@@ -15,6 +15,9 @@ import (
 	"github.com/arkeo/arkeo/internal/timeline"
 )
 
+// youTrackPageSize is the number of activities requested per page
+const youTrackPageSize = 500
+
 // YouTrackConnector implements the Connector interface for YouTrack
 type YouTrackConnector struct {
 	*BaseConnector
@@ -53,6 +56,12 @@ func (y *YouTrackConnector) GetRequiredConfig() []ConfigField {
 			Required:    false,
 			Description: "Username to filter activities for (defaults to token owner)",
 		},
+		{
+			Key:         "projects",
+			Type:        "string",
+			Required:    false,
+			Description: "Comma-separated short names of the projects time is logged to (defaults to all projects the token can see)",
+		},
 		{
 			Key:         "include_work_items",
 			Type:        "bool",
@@ -270,12 +279,35 @@ func (y *YouTrackConnector) testAPIEndpoint(ctx context.Context, apiURL, token,
 
 // GetActivities retrieves YouTrack activities for the specified date
 func (y *YouTrackConnector) GetActivities(ctx context.Context, date time.Time) ([]timeline.Activity, error) {
-	var allActivities []timeline.Activity
-
 	if y.isDebugMode() {
 		log.Printf("YouTrack Debug: Fetching activities for date %s", date.Format("2006-01-02"))
 	}
 
+	// Calculate start and end of the day.
+	// Normalize to the user's local day so that day boundaries match the
+	// user's working day rather than UTC (time.Parse returns UTC).
+	localDate := date.In(time.Local)
+	startOfDay := time.Date(localDate.Year(), localDate.Month(), localDate.Day(), 0, 0, 0, 0, localDate.Location())
+	endOfDay := startOfDay.Add(24 * time.Hour)
+
+	return y.fetchActivities(ctx, startOfDay, endOfDay)
+}
+
+// GetActivitiesRange retrieves YouTrack activities for every day from start
+// to end (inclusive) with a single activities query.
+func (y *YouTrackConnector) GetActivitiesRange(ctx context.Context, start, end time.Time) ([]timeline.Activity, error) {
+	if y.isDebugMode() {
+		log.Printf("YouTrack Debug: Fetching activities from %s to %s", start.Format("2006-01-02"), end.Format("2006-01-02"))
+	}
+
+	from, to := localDayRange(start, end)
+	return y.fetchActivities(ctx, from, to)
+}
+
+// fetchActivities retrieves the user's YouTrack activities in [start, end)
+func (y *YouTrackConnector) fetchActivities(ctx context.Context, start, end time.Time) ([]timeline.Activity, error) {
+	var allActivities []timeline.Activity
+
 	// Get user info if username not specified
 	username := y.GetConfigString("username")
 	if username == "" {
@@ -292,8 +324,8 @@ func (y *YouTrackConnector) GetActivities(ctx context.Context, date time.Time) (
 		}
 	}
 
-	// Get activities for the specified date
-	activities, err := y.getActivities(ctx, date, username)
+	// Get activities for the specified window
+	activities, err := y.getActivities(ctx, start, end, username)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get activities: %w", err)
 	}
@@ -382,8 +414,8 @@ func (y *YouTrackConnector) getCurrentUser(ctx context.Context) (*youTrackUser,
 	return &user, nil
 }
 
-// getActivities fetches activities for a specific date and user
-func (y *YouTrackConnector) getActivities(ctx context.Context, date time.Time, username string) ([]timeline.Activity, error) {
+// getActivities fetches activities in [start, end) for a user
+func (y *YouTrackConnector) getActivities(ctx context.Context, start, end time.Time, username string) ([]timeline.Activity, error) {
 	baseURL := y.GetConfigString("base_url")
 	token := y.GetConfigString("token")
 
@@ -392,15 +424,8 @@ func (y *YouTrackConnector) getActivities(ctx context.Context, date time.Time, u
 		return nil, fmt.Errorf("username cannot be empty")
 	}
 
-	// Calculate start and end timestamps for the day.
-	// Normalize to the user's local day so that day boundaries match the
-	// user's working day rather than UTC (time.Parse returns UTC).
-	localDate := date.In(time.Local)
-	startOfDay := time.Date(localDate.Year(), localDate.Month(), localDate.Day(), 0, 0, 0, 0, localDate.Location())
-	endOfDay := startOfDay.Add(24 * time.Hour)
-
-	startTimestamp := startOfDay.Unix() * 1000 // YouTrack uses milliseconds
-	endTimestamp := endOfDay.Unix() * 1000
+	startTimestamp := start.Unix() * 1000 // YouTrack uses milliseconds
+	endTimestamp := end.Unix() * 1000
 
 	// Validate timestamps (YouTrack doesn't accept negative timestamps)
 	if startTimestamp < 0 || endTimestamp < 0 {
@@ -455,6 +480,10 @@ func (y *YouTrackConnector) getActivities(ctx context.Context, date time.Time, u
 		baseURL += "/"
 	}
 
+	// Page through the results so that long date ranges aren't truncated
+	params.Set("$top", strconv.Itoa(youTrackPageSize))
+	params.Set("$skip", "0")
+
 	apiURL := baseURL + "api/activities?" + params.Encode()
 
 	// Check URL length (some systems have URL length limits)
@@ -483,6 +512,37 @@ func (y *YouTrackConnector) getActivities(ctx context.Context, date time.Time, u
 		}
 	}
 
+	var activities []youTrackActivity
+	for skip := 0; ; skip += youTrackPageSize {
+		params.Set("$skip", strconv.Itoa(skip))
+		apiURL = baseURL + "api/activities?" + params.Encode()
+
+		page, err := y.requestActivitiesPage(ctx, apiURL, token)
+		if err != nil {
+			return nil, err
+		}
+		activities = append(activities, page...)
+
+		if len(page) < youTrackPageSize {
+			break
+		}
+	}
+
+	if y.isDebugMode() {
+		log.Printf("YouTrack Debug: Parsed %d activities from API response", len(activities))
+	}
+
+	convertedActivities := y.convertActivities(activities)
+
+	if y.isDebugMode() {
+		log.Printf("YouTrack Debug: Converted %d activities for timeline", len(convertedActivities))
+	}
+
+	return convertedActivities, nil
+}
+
+// requestActivitiesPage performs a single /api/activities request
+func (y *YouTrackConnector) requestActivitiesPage(ctx context.Context, apiURL, token string) ([]youTrackActivity, error) {
 	req, err := y.CreateBearerRequest(ctx, "GET", apiURL, token)
 	if err != nil {
 		return nil, err
@@ -543,17 +603,7 @@ func (y *YouTrackConnector) getActivities(ctx context.Context, date time.Time, u
 		return nil, err
 	}
 
-	if y.isDebugMode() {
-		log.Printf("YouTrack Debug: Parsed %d activities from API response", len(activities))
-	}
-
-	convertedActivities := y.convertActivities(activities)
-
-	if y.isDebugMode() {
-		log.Printf("YouTrack Debug: Converted %d activities for timeline", len(convertedActivities))
-	}
-
-	return convertedActivities, nil
+	return activities, nil
 }
 
 // convertActivities converts YouTrack activities to timeline activities
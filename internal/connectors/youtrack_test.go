@@ -33,6 +33,7 @@ func TestYouTrackConnector_GetRequiredConfig(t *testing.T) {
 		"base_url":           true,  // required
 		"token":              true,  // required
 		"username":           false, // not required
+		"projects":           false, // not required
 		"include_work_items": false, // not required
 		"include_comments":   false, // not required
 		"include_issues":     false, // not required
@@ -0,0 +1,275 @@
+package connectors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Work item statuses reported by LogWorkItems
+const (
+	WorkItemStatusNew     = "new"
+	WorkItemStatusCreated = "created"
+	WorkItemStatusExists  = "exists"
+	WorkItemStatusError   = "error"
+)
+
+// YouTrackWorkItem is time spent on an issue on a given day
+type YouTrackWorkItem struct {
+	IssueKey string        `json:"issue"`
+	Date     time.Time     `json:"date"`
+	Duration time.Duration `json:"duration"`
+	Text     string        `json:"text"`
+}
+
+// WorkItemResult is the outcome of logging a single work item
+type WorkItemResult struct {
+	YouTrackWorkItem
+	Status string `json:"status"`
+
+	// Existing is the time already logged by the user on the issue that day
+	Existing time.Duration `json:"existing,omitempty"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// youTrackWorkItem is a work item as returned by the YouTrack API
+type youTrackWorkItem struct {
+	ID       string `json:"id"`
+	Date     int64  `json:"date"`
+	Text     string `json:"text"`
+	Duration struct {
+		Minutes int `json:"minutes"`
+	} `json:"duration"`
+	Author struct {
+		Login string `json:"login"`
+	} `json:"author"`
+}
+
+// workItemDate returns the timestamp YouTrack uses for the day of t: UTC
+// midnight of the local date
+func workItemDate(t time.Time) int64 {
+	local := t.In(time.Local)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC).UnixMilli()
+}
+
+// LogWorkItems creates the given work items in YouTrack. Items for an issue
+// and day on which the user already logged time are skipped, so running it
+// twice doesn't book the same time twice. With dryRun nothing is created and
+// the items that would be are reported with WorkItemStatusNew.
+func (y *YouTrackConnector) LogWorkItems(ctx context.Context, items []YouTrackWorkItem, dryRun bool) ([]WorkItemResult, error) {
+	username := y.GetConfigString("username")
+	if username == "" {
+		user, err := y.getCurrentUser(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current user: %w", err)
+		}
+		username = user.Login
+	}
+
+	existing := make(map[string][]youTrackWorkItem)
+	results := make([]WorkItemResult, 0, len(items))
+
+	for _, item := range items {
+		result := WorkItemResult{YouTrackWorkItem: item}
+
+		issueItems, fetched := existing[item.IssueKey]
+		if !fetched {
+			var err error
+			issueItems, err = y.getWorkItems(ctx, item.IssueKey)
+			if err != nil {
+				result.Status = WorkItemStatusError
+				result.Error = err.Error()
+				results = append(results, result)
+				continue
+			}
+			existing[item.IssueKey] = issueItems
+		}
+
+		date := workItemDate(item.Date)
+		for _, wi := range issueItems {
+			if wi.Date == date && wi.Author.Login == username {
+				result.Existing += time.Duration(wi.Duration.Minutes) * time.Minute
+			}
+		}
+
+		switch {
+		case result.Existing > 0:
+			result.Status = WorkItemStatusExists
+		case dryRun:
+			result.Status = WorkItemStatusNew
+		default:
+			created, err := y.createWorkItem(ctx, item)
+			if err != nil {
+				result.Status = WorkItemStatusError
+				result.Error = err.Error()
+			} else {
+				result.Status = WorkItemStatusCreated
+				existing[item.IssueKey] = append(existing[item.IssueKey], *created)
+			}
+		}
+
+		if y.isDebugMode() {
+			log.Printf("YouTrack Debug: Work item %s on %s (%v): %s",
+				item.IssueKey, item.Date.Format("2006-01-02"), item.Duration, result.Status)
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// ProjectShortNames returns the short names of the projects work items can
+// be logged to: the projects setting when there is one, or else every
+// project the token can see
+func (y *YouTrackConnector) ProjectShortNames(ctx context.Context) (map[string]bool, error) {
+	projects := make(map[string]bool)
+	if setting := y.GetConfigString("projects"); setting != "" {
+		for _, name := range strings.Split(setting, ",") {
+			if name = strings.ToUpper(strings.TrimSpace(name)); name != "" {
+				projects[name] = true
+			}
+		}
+		return projects, nil
+	}
+
+	for skip := 0; ; skip += youTrackPageSize {
+		params := url.Values{}
+		params.Set("fields", "shortName")
+		params.Set("$top", fmt.Sprintf("%d", youTrackPageSize))
+		params.Set("$skip", fmt.Sprintf("%d", skip))
+		apiURL := y.GetConfigString("base_url") + "api/admin/projects?" + params.Encode()
+
+		req, err := y.CreateBearerRequest(ctx, "GET", apiURL, y.GetConfigString("token"))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/json")
+
+		resp, err := y.GetHTTPClient().Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to list projects, status: %d", resp.StatusCode)
+		}
+
+		var page []struct {
+			ShortName string `json:"shortName"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse projects: %w", err)
+		}
+
+		for _, project := range page {
+			if project.ShortName != "" {
+				projects[strings.ToUpper(project.ShortName)] = true
+			}
+		}
+		if len(page) < youTrackPageSize {
+			return projects, nil
+		}
+	}
+}
+
+// getWorkItems returns all work items of an issue
+func (y *YouTrackConnector) getWorkItems(ctx context.Context, issueKey string) ([]youTrackWorkItem, error) {
+	var items []youTrackWorkItem
+
+	for skip := 0; ; skip += youTrackPageSize {
+		params := url.Values{}
+		params.Set("fields", "id,date,text,duration(minutes),author(login)")
+		params.Set("$top", fmt.Sprintf("%d", youTrackPageSize))
+		params.Set("$skip", fmt.Sprintf("%d", skip))
+		apiURL := y.workItemsURL(issueKey) + "?" + params.Encode()
+
+		req, err := y.CreateBearerRequest(ctx, "GET", apiURL, y.GetConfigString("token"))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/json")
+
+		var page []youTrackWorkItem
+		if err := y.doWorkItemRequest(req, issueKey, &page); err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+
+		if len(page) < youTrackPageSize {
+			return items, nil
+		}
+	}
+}
+
+// createWorkItem creates a work item through the issue's time tracking API
+func (y *YouTrackConnector) createWorkItem(ctx context.Context, item YouTrackWorkItem) (*youTrackWorkItem, error) {
+	minutes := int(item.Duration.Round(time.Minute) / time.Minute)
+	if minutes <= 0 {
+		return nil, fmt.Errorf("duration must be at least one minute")
+	}
+
+	payload := map[string]interface{}{
+		"date":     workItemDate(item.Date),
+		"duration": map[string]int{"minutes": minutes},
+		"text":     item.Text,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	apiURL := y.workItemsURL(item.IssueKey) + "?fields=id,date,text,duration(minutes),author(login)"
+	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+y.GetConfigString("token"))
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	var created youTrackWorkItem
+	if err := y.doWorkItemRequest(req, item.IssueKey, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// workItemsURL returns the time tracking work items endpoint of an issue
+func (y *YouTrackConnector) workItemsURL(issueKey string) string {
+	return y.GetConfigString("base_url") + "api/issues/" + url.PathEscape(issueKey) + "/timeTracking/workItems"
+}
+
+// doWorkItemRequest performs a work items request and decodes the response
+func (y *YouTrackConnector) doWorkItemRequest(req *http.Request, issueKey string, v interface{}) error {
+	if y.isDebugMode() {
+		log.Printf("YouTrack Debug: %s %s", req.Method, req.URL.String())
+	}
+
+	resp, err := y.GetHTTPClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("issue %s not found in YouTrack", issueKey)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		if y.isDebugMode() {
+			log.Printf("YouTrack Debug: Work items response body: %s", string(body))
+		}
+		return fmt.Errorf("YouTrack returned status %d for %s: %s", resp.StatusCode, issueKey, strings.TrimSpace(string(body)))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,162 @@
+package connectors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// youTrackWorkItemServer stores work items per issue and serves the time
+// tracking API. Issues other than ACME-1 and ACME-2 don't exist.
+func youTrackWorkItemServer(t *testing.T) (*httptest.Server, func() int) {
+	t.Helper()
+
+	var mu sync.Mutex
+	items := map[string][]map[string]interface{}{
+		"ACME-2": {{"id": "1-1", "date": workItemDate(time.Date(2024, 1, 15, 0, 0, 0, 0, time.Local)),
+			"duration": map[string]int{"minutes": 30}, "author": map[string]string{"login": "jdoe"}}},
+	}
+	posts := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer perm:token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == "/api/admin/users/me" {
+			fmt.Fprint(w, `{"id": "1-1", "login": "jdoe", "name": "John Doe"}`)
+			return
+		}
+		if r.URL.Path == "/api/admin/projects" {
+			fmt.Fprint(w, `[{"shortName": "ACME"}, {"shortName": "ops"}]`)
+			return
+		}
+
+		var issue string
+		if _, err := fmt.Sscanf(r.URL.Path, "/api/issues/%s", &issue); err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		issue = issue[:len(issue)-len("/timeTracking/workItems")]
+		if issue != "ACME-1" && issue != "ACME-2" {
+			http.NotFound(w, r)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Method == "POST" {
+			var body map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			posts++
+			body["id"] = fmt.Sprintf("1-%d", 100+posts)
+			body["author"] = map[string]string{"login": "jdoe"}
+			items[issue] = append(items[issue], body)
+			json.NewEncoder(w).Encode(body)
+			return
+		}
+
+		if items[issue] == nil {
+			fmt.Fprint(w, `[]`)
+			return
+		}
+		json.NewEncoder(w).Encode(items[issue])
+	}))
+
+	return server, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return posts
+	}
+}
+
+func TestYouTrackConnector_LogWorkItems(t *testing.T) {
+	server, postCount := youTrackWorkItemServer(t)
+	defer server.Close()
+
+	connector := NewYouTrackConnector()
+	if err := connector.Configure(map[string]interface{}{"base_url": server.URL, "token": "perm:token"}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.Local)
+	items := []YouTrackWorkItem{
+		{IssueKey: "ACME-1", Date: day, Duration: 90 * time.Minute, Text: "Fix login redirect"},
+		{IssueKey: "ACME-2", Date: day, Duration: time.Hour, Text: "Review"},
+		{IssueKey: "NOPE-1", Date: day, Duration: time.Hour, Text: "Unknown issue"},
+	}
+
+	results, err := connector.LogWorkItems(context.Background(), items, true)
+	if err != nil {
+		t.Fatalf("LogWorkItems (dry run) failed: %v", err)
+	}
+	expected := []string{WorkItemStatusNew, WorkItemStatusExists, WorkItemStatusError}
+	for i, r := range results {
+		if r.Status != expected[i] {
+			t.Errorf("Dry run item %s: expected status %s, got %s (%s)", r.IssueKey, expected[i], r.Status, r.Error)
+		}
+	}
+	if results[1].Existing != 30*time.Minute {
+		t.Errorf("Expected 30m already logged on ACME-2, got %v", results[1].Existing)
+	}
+	if postCount() != 0 {
+		t.Errorf("Dry run must not create work items, got %d", postCount())
+	}
+
+	results, err = connector.LogWorkItems(context.Background(), items, false)
+	if err != nil {
+		t.Fatalf("LogWorkItems failed: %v", err)
+	}
+	if results[0].Status != WorkItemStatusCreated || postCount() != 1 {
+		t.Errorf("Expected ACME-1 to be created once, got %s with %d posts", results[0].Status, postCount())
+	}
+
+	// Running it again must not book the time twice
+	results, err = connector.LogWorkItems(context.Background(), items, false)
+	if err != nil {
+		t.Fatalf("LogWorkItems failed: %v", err)
+	}
+	if results[0].Status != WorkItemStatusExists || results[0].Existing != 90*time.Minute {
+		t.Errorf("Expected ACME-1 to be skipped with 90m logged, got %s (%v)", results[0].Status, results[0].Existing)
+	}
+	if postCount() != 1 {
+		t.Errorf("Expected no additional work items, got %d posts", postCount())
+	}
+}
+
+func TestYouTrackConnector_ProjectShortNames(t *testing.T) {
+	server, _ := youTrackWorkItemServer(t)
+	defer server.Close()
+
+	connector := NewYouTrackConnector()
+	if err := connector.Configure(map[string]interface{}{"base_url": server.URL, "token": "perm:token"}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	projects, err := connector.ProjectShortNames(context.Background())
+	if err != nil {
+		t.Fatalf("ProjectShortNames failed: %v", err)
+	}
+	if len(projects) != 2 || !projects["ACME"] || !projects["OPS"] {
+		t.Errorf("Expected the projects ACME and OPS from the API, got %v", projects)
+	}
+
+	if err := connector.Configure(map[string]interface{}{"base_url": server.URL, "token": "perm:token", "projects": "web, Mobile"}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+	projects, err = connector.ProjectShortNames(context.Background())
+	if err != nil || len(projects) != 2 || !projects["WEB"] || !projects["MOBILE"] {
+		t.Errorf("Expected the projects of the setting, got %v (%v)", projects, err)
+	}
+}
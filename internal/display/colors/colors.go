@@ -2,6 +2,7 @@ package colors
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/arkeo/arkeo/internal/timeline"
@@ -45,10 +46,26 @@ var SourceLabels = map[string]string{
 	"slack":            "SLK",
 	"jira":             "JRA",
 	"macos_system":     "MAC",
+	"linux_session":    "LNX",
 	"system":           "SYS",
 	"file":             "FILE",
 	"browser":          "WEB",
 	"browser_history":  "WEB",
+	"local_git":        "GIT",
+	"shell_history":    "SH",
+}
+
+// SourceLabel returns the short label of an activity source. Sources of
+// additional connector instances (gitlab:client-a) keep their instance name
+// after the label of their type (GL:client-a).
+func SourceLabel(source string) string {
+	if connectorType, instance, ok := strings.Cut(source, ":"); ok {
+		return SourceLabel(connectorType) + ":" + instance
+	}
+	if label := SourceLabels[source]; label != "" {
+		return label
+	}
+	return strings.ToUpper(source[:min(3, len(source))])
 }
 
 // Colorize adds color codes to text
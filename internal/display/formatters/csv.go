@@ -0,0 +1,105 @@
+package formatters
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arkeo/arkeo/internal/timeline"
+)
+
+// DefaultColumns are the columns of the csv and tsv formats unless others
+// are chosen
+var DefaultColumns = []string{"date", "time", "end_time", "duration", "source", "type", "title", "description", "url"}
+
+// ValidateColumns checks that every column is one of DefaultColumns or a
+// metadata.<key>
+func ValidateColumns(columns []string) error {
+	for _, column := range columns {
+		if key, ok := strings.CutPrefix(column, "metadata."); ok {
+			if key == "" {
+				return fmt.Errorf("column %q needs a metadata key, e.g. metadata.project", column)
+			}
+			continue
+		}
+		if !contains(DefaultColumns, column) {
+			return fmt.Errorf("unknown column %q (expected %s or metadata.<key>)", column, strings.Join(DefaultColumns, ", "))
+		}
+	}
+	return nil
+}
+
+// columnValue returns the value of a column for an activity. Times are in
+// the activity's timezone and durations in whole minutes.
+func columnValue(column string, a timeline.Activity) (string, error) {
+	switch column {
+	case "time":
+		return a.Timestamp.Format("15:04"), nil
+	case "end_time":
+		if a.Duration == nil {
+			return "", nil
+		}
+		return a.Timestamp.Add(*a.Duration).Format("15:04"), nil
+	case "duration":
+		if a.Duration == nil {
+			return "", nil
+		}
+		return strconv.Itoa(int(a.Duration.Round(time.Minute).Minutes())), nil
+	}
+	return activityField(column, a)
+}
+
+// WriteDelimited writes activities one per row, after a header row of
+// column names, separated by comma: ',' for CSV or '\t' for TSV. Fields
+// holding the separator, quotes or line breaks are quoted. No columns
+// means DefaultColumns.
+func WriteDelimited(w io.Writer, activities []timeline.Activity, columns []string, comma rune) error {
+	if len(columns) == 0 {
+		columns = DefaultColumns
+	}
+	if err := ValidateColumns(columns); err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+
+	record := make([]string, len(columns))
+	for _, a := range activities {
+		for i, column := range columns {
+			value, err := columnValue(column, a)
+			if err != nil {
+				return err
+			}
+			record[i] = value
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// DisplayDelimited prints activities as CSV or TSV to the console
+func DisplayDelimited(activities []timeline.Activity, columns []string, comma rune) error {
+	return WriteDelimited(os.Stdout, activities, columns, comma)
+}
+
+// contains reports whether list holds value
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
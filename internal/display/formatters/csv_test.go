@@ -0,0 +1,71 @@
+package formatters
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arkeo/arkeo/internal/timeline"
+)
+
+func csvActivities() []timeline.Activity {
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	meeting := 45 * time.Minute
+	return []timeline.Activity{
+		{ID: "1", Type: timeline.ActivityTypeCalendar, Title: "Planning", Description: "Sprint 4, \"Q1\" goals", Source: "calendar",
+			Timestamp: date.Add(9 * time.Hour), Duration: &meeting, Metadata: map[string]string{"project": "ACME"}},
+		{ID: "2", Type: timeline.ActivityTypeGitCommit, Title: "Fix login", Description: "first line\nsecond line", Source: "github",
+			Timestamp: date.Add(10*time.Hour + 5*time.Minute), URL: "https://github.com/acme/api/commit/1"},
+	}
+}
+
+func TestWriteDelimited_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteDelimited(&buf, csvActivities(), nil, ','); err != nil {
+		t.Fatalf("WriteDelimited failed: %v", err)
+	}
+
+	expected := `date,time,end_time,duration,source,type,title,description,url
+2024-01-15,09:00,09:45,45,calendar,calendar,Planning,"Sprint 4, ""Q1"" goals",
+2024-01-15,10:05,,,github,git_commit,Fix login,"first line
+second line",https://github.com/acme/api/commit/1
+`
+	if buf.String() != expected {
+		t.Errorf("Expected CSV:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestWriteDelimited_TSVColumns(t *testing.T) {
+	var buf bytes.Buffer
+	columns := []string{"date", "duration", "title", "metadata.project"}
+	if err := WriteDelimited(&buf, csvActivities(), columns, '\t'); err != nil {
+		t.Fatalf("WriteDelimited failed: %v", err)
+	}
+
+	expected := "date\tduration\ttitle\tmetadata.project\n" +
+		"2024-01-15\t45\tPlanning\tACME\n" +
+		"2024-01-15\t\tFix login\t\n"
+	if buf.String() != expected {
+		t.Errorf("Expected TSV:\n%q\ngot:\n%q", expected, buf.String())
+	}
+}
+
+func TestValidateColumns(t *testing.T) {
+	if err := ValidateColumns(append([]string{"metadata.client"}, DefaultColumns...)); err != nil {
+		t.Errorf("Expected valid columns, got %v", err)
+	}
+	for _, column := range []string{"owner", "metadata.", "Title"} {
+		if err := ValidateColumns([]string{column}); err == nil {
+			t.Errorf("Expected %q to be rejected", column)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := WriteDelimited(&buf, csvActivities(), []string{"owner"}, ','); err == nil || !strings.Contains(err.Error(), "owner") {
+		t.Errorf("Expected an unknown column error, got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output for invalid columns, got %q", buf.String())
+	}
+}
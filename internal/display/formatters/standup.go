@@ -0,0 +1,283 @@
+package formatters
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arkeo/arkeo/internal/timeline"
+)
+
+// Standup is the data passed to standup templates: the work of one day
+// collapsed into items, and the meetings of the day of the standup
+type Standup struct {
+	// Day the items are from
+	Date time.Time
+
+	// Work grouped by issue, repository, project or website, in the order
+	// it was first touched. Meetings of Date come last as a single item.
+	Items []StandupItem
+
+	// Day of the standup and its calendar events by start time. Meetings is
+	// empty when the report covers a day without a standup.
+	Today    time.Time
+	Meetings []timeline.Activity
+}
+
+// StandupItem collapses the activities on one issue, repository, project
+// or website into a single bullet
+type StandupItem struct {
+	// ACME-123, acme/api#42, acme/api, ACME API, github.com or, for
+	// activities with nothing to group them by, their title
+	Key string
+
+	// Summary of the issue or pull request, empty for other items
+	Title string
+
+	URL string
+
+	// What happened, e.g. "moved to Review, 3 commits, 2 comments"
+	Summary string
+
+	Activities []timeline.Activity
+
+	// Whether the activities only share their title
+	byTitle bool
+}
+
+// DefaultStandupTemplate renders a standup as Markdown
+const DefaultStandupTemplate = `**{{.Date.Format "Monday, January 2"}}**
+{{range .Items}}- {{if .URL}}[{{.Key}}]({{.URL}}){{else}}{{.Key}}{{end}}{{with .Title}} {{.}}{{end}}{{with .Summary}}: {{.}}{{end}}
+{{else}}- Nothing recorded
+{{end}}{{if .Meetings}}
+**Today**
+{{range .Meetings}}{{if eq (index .Metadata "all_day") "true"}}- {{.Title}} ({{.FormatDuration}})
+{{else}}- {{.Timestamp.Format "15:04"}} {{.Title}}{{if .Duration}} ({{.FormatDuration}}){{end}}
+{{end}}{{end}}{{end}}`
+
+// meetingsKey is the key of the item holding the meetings of the day
+const meetingsKey = "Meetings"
+
+// BuildStandup collapses the activities of date into standup items and adds
+// the calendar events among todays, the activities of the day of the
+// standup. todays may be nil.
+func BuildStandup(date time.Time, activities []timeline.Activity, today time.Time, todays []timeline.Activity) Standup {
+	standup := Standup{Date: date, Today: today, Items: []StandupItem{}}
+
+	sorted := append([]timeline.Activity(nil), activities...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	// Commits mentioning an issue someone worked on go with the issue
+	issues := make(map[string]bool)
+	for _, a := range sorted {
+		if key := a.Metadata["issue_key"]; key != "" {
+			issues[key] = true
+		}
+	}
+
+	index := make(map[string]int)
+	var meetings StandupItem
+	for _, a := range sorted {
+		if a.Type == timeline.ActivityTypeSystem || isCancelled(a) {
+			continue
+		}
+		if a.Type == timeline.ActivityTypeCalendar {
+			if a.Metadata["all_day"] != "true" {
+				meetings.Activities = append(meetings.Activities, a)
+			}
+			continue
+		}
+
+		key, title, byTitle := standupKey(a, issues)
+		i, ok := index[key]
+		if !ok {
+			i = len(standup.Items)
+			index[key] = i
+			standup.Items = append(standup.Items, StandupItem{Key: key, Title: title, URL: a.URL, byTitle: byTitle})
+		}
+		item := &standup.Items[i]
+		item.Activities = append(item.Activities, a)
+		if item.Title == "" {
+			item.Title = title
+		}
+		if a.URL != "" && (item.URL == "" || a.Metadata["issue_key"] == key) {
+			item.URL = a.URL
+		}
+	}
+
+	for i := range standup.Items {
+		standup.Items[i].Summary = summarize(standup.Items[i])
+	}
+
+	if len(meetings.Activities) > 0 {
+		var titles []string
+		for _, a := range meetings.Activities {
+			titles = append(titles, a.Title)
+		}
+		meetings.Key = meetingsKey
+		meetings.Summary = strings.Join(titles, ", ")
+		standup.Items = append(standup.Items, meetings)
+	}
+
+	for _, a := range todays {
+		if a.Type == timeline.ActivityTypeCalendar && !isCancelled(a) {
+			standup.Meetings = append(standup.Meetings, a)
+		}
+	}
+	sort.SliceStable(standup.Meetings, func(i, j int) bool {
+		return standup.Meetings[i].Timestamp.Before(standup.Meetings[j].Timestamp)
+	})
+
+	return standup
+}
+
+// standupKey returns the item an activity belongs to, the title of issues
+// and pull requests, and whether the activity has nothing but its title to
+// group it by
+func standupKey(a timeline.Activity, issues map[string]bool) (key, title string, byTitle bool) {
+	if key := a.Metadata["issue_key"]; key != "" {
+		return key, a.Metadata["issue_summary"], false
+	}
+
+	repository := a.Metadata["repository"]
+	if number := a.Metadata["number"]; repository != "" && number != "" {
+		// GitHub issues and pull requests: "PR #42: Title"
+		title := a.Title
+		if _, rest, ok := strings.Cut(title, ": "); ok {
+			title = rest
+		}
+		return repository + "#" + number, title, false
+	}
+
+	if a.Type == timeline.ActivityTypeGitCommit {
+		for _, key := range timeline.IssueKeyPattern.FindAllString(a.Title, -1) {
+			if issues[key] {
+				return key, "", false
+			}
+		}
+	}
+
+	for _, field := range []string{"repository", "project", "domain"} {
+		if value := a.Metadata[field]; value != "" {
+			return value, "", false
+		}
+	}
+	return a.Title, "", true
+}
+
+// summarize describes what happened to an item, most significant first
+func summarize(item StandupItem) string {
+	// Activities only grouped by their title are repeats of the same thing
+	if item.byTitle {
+		if len(item.Activities) > 1 {
+			return fmt.Sprintf("%d times", len(item.Activities))
+		}
+		return ""
+	}
+
+	var created bool
+	var status string
+	var commits, comments, worklogs, visits, updates int
+
+	for _, a := range item.Activities {
+		category := a.Metadata["category"]
+		switch {
+		case category == "created" || category == "IssueCreatedCategory":
+			created = true
+		case category == "transition" || (category == "CustomFieldCategory" && isStatusField(a.Metadata["field_name"])):
+			if value := a.Metadata["field_new_value"]; value != "" {
+				status = value
+			} else {
+				updates++
+			}
+		case category == "comment" || category == "CommentsCategory":
+			comments++
+		case category == "worklog" || category == "WorkItemCategory":
+			worklogs++
+		case a.Type == timeline.ActivityTypeGitCommit:
+			if action := a.Metadata["action"]; action != "" && action != "commit" {
+				updates++
+			} else {
+				commits += countMetadata(a, "commit_count")
+			}
+		case a.Type == timeline.ActivityTypeBrowser:
+			visits += countMetadata(a, "visit_count")
+		default:
+			updates++
+		}
+	}
+
+	var parts []string
+	if created {
+		parts = append(parts, "created")
+	}
+	if status != "" {
+		parts = append(parts, "moved to "+status)
+	}
+	parts = appendCount(parts, commits, "commit")
+	parts = appendCount(parts, comments, "comment")
+	if worklogs > 0 {
+		parts = append(parts, "logged work")
+	}
+	parts = appendCount(parts, visits, "visit")
+	parts = appendCount(parts, updates, "update")
+	return strings.Join(parts, ", ")
+}
+
+// isStatusField reports whether a YouTrack custom field holds the issue state
+func isStatusField(name string) bool {
+	return strings.EqualFold(name, "state") || strings.EqualFold(name, "status")
+}
+
+// isCancelled reports whether an activity is a cancelled calendar event
+func isCancelled(a timeline.Activity) bool {
+	return a.Type == timeline.ActivityTypeCalendar && strings.EqualFold(a.Metadata["status"], "CANCELLED")
+}
+
+// countMetadata returns a count kept in the metadata, or 1 when it is missing
+func countMetadata(a timeline.Activity, key string) int {
+	if n, err := strconv.Atoi(a.Metadata[key]); err == nil && n > 0 {
+		return n
+	}
+	return 1
+}
+
+// appendCount adds "1 commit" or "3 commits" to parts unless n is 0
+func appendCount(parts []string, n int, noun string) []string {
+	switch n {
+	case 0:
+		return parts
+	case 1:
+		return append(parts, "1 "+noun)
+	default:
+		return append(parts, fmt.Sprintf("%d %ss", n, noun))
+	}
+}
+
+// RenderStandup writes a standup using a text/template, or
+// DefaultStandupTemplate when templateText is empty. The functions of
+// TemplateFuncs are available.
+func RenderStandup(w io.Writer, standup Standup, templateText string) error {
+	if templateText == "" {
+		templateText = DefaultStandupTemplate
+	}
+	tmpl, err := ParseTemplate("standup", templateText)
+	if err != nil {
+		return err
+	}
+	if err := tmpl.Execute(w, standup); err != nil {
+		return fmt.Errorf("failed to render standup: %w", err)
+	}
+	return nil
+}
+
+// DisplayStandup prints a standup to the console
+func DisplayStandup(standup Standup, templateText string) error {
+	return RenderStandup(os.Stdout, standup, templateText)
+}
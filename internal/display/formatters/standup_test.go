@@ -0,0 +1,148 @@
+package formatters
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arkeo/arkeo/internal/timeline"
+)
+
+func standupActivities(date time.Time) []timeline.Activity {
+	at := func(hour, minute int) time.Time {
+		return date.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+	meeting := 30 * time.Minute
+
+	return []timeline.Activity{
+		{ID: "yt-1", Type: timeline.ActivityTypeYouTrack, Title: "Updated State of ACME-123: Fix login", Source: "youtrack",
+			Timestamp: at(9, 30), URL: "https://yt.example.com/issue/ACME-123",
+			Metadata: map[string]string{"issue_key": "ACME-123", "issue_summary": "Fix login", "category": "CustomFieldCategory",
+				"field_name": "State", "field_old_value": "In Progress", "field_new_value": "Review"}},
+		{ID: "c-1", Type: timeline.ActivityTypeGitCommit, Title: "ACME-123 validate tokens on acme/api", Source: "github",
+			Timestamp: at(10, 0), URL: "https://github.com/acme/api/commit/1", Metadata: map[string]string{"repository": "acme/api"}},
+		{ID: "c-2", Type: timeline.ActivityTypeGitCommit, Title: "Pushed 2 commits to main", Source: "gitlab",
+			Timestamp: at(10, 30), Metadata: map[string]string{"repository": "acme/web", "commit_count": "2"}},
+		{ID: "yt-2", Type: timeline.ActivityTypeYouTrack, Title: "Commented on ACME-123: Fix login", Source: "youtrack",
+			Timestamp: at(11, 0), Metadata: map[string]string{"issue_key": "ACME-123", "category": "CommentsCategory"}},
+		{ID: "yt-3", Type: timeline.ActivityTypeYouTrack, Title: "Commented on ACME-123: Fix login", Source: "youtrack",
+			Timestamp: at(11, 15), Metadata: map[string]string{"issue_key": "ACME-123", "category": "CommentsCategory"}},
+		{ID: "c-3", Type: timeline.ActivityTypeGitCommit, Title: "ACME-123 add tests on acme/api", Source: "github",
+			Timestamp: at(11, 30), Metadata: map[string]string{"repository": "acme/api"}},
+		{ID: "b-1", Type: timeline.ActivityTypeBrowser, Title: "docs.example.com", Source: "browser_history",
+			Timestamp: at(12, 0), Metadata: map[string]string{"domain": "docs.example.com", "visit_count": "4"}},
+		{ID: "b-2", Type: timeline.ActivityTypeBrowser, Title: "docs.example.com", Source: "browser_history",
+			Timestamp: at(15, 0), Metadata: map[string]string{"domain": "docs.example.com", "visit_count": "3"}},
+		{ID: "gh-1", Type: timeline.ActivityTypeJira, Title: "PR #42: Rate limiting", Source: "github",
+			Timestamp: at(13, 0), URL: "https://github.com/acme/api/pull/42",
+			Metadata: map[string]string{"repository": "acme/api", "number": "42"}},
+		{ID: "cal-1", Type: timeline.ActivityTypeCalendar, Title: "Planning", Source: "calendar", Timestamp: at(14, 0), Duration: &meeting},
+		{ID: "cal-2", Type: timeline.ActivityTypeCalendar, Title: "Cancelled sync", Source: "calendar", Timestamp: at(16, 0),
+			Metadata: map[string]string{"status": "CANCELLED"}},
+		{ID: "lock", Type: timeline.ActivityTypeSystem, Title: "Screen locked", Source: "macos_system", Timestamp: at(17, 0)},
+		{ID: "sh-1", Type: timeline.ActivityTypeCustom, Title: "make deploy", Source: "shell_history", Timestamp: at(16, 30)},
+		{ID: "sh-2", Type: timeline.ActivityTypeCustom, Title: "make deploy", Source: "shell_history", Timestamp: at(16, 45)},
+	}
+}
+
+func TestBuildStandup(t *testing.T) {
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	standup := BuildStandup(date, standupActivities(date), time.Time{}, nil)
+
+	expected := []struct {
+		key, title, summary string
+	}{
+		{"ACME-123", "Fix login", "moved to Review, 2 commits, 2 comments"},
+		{"acme/web", "", "2 commits"},
+		{"docs.example.com", "", "7 visits"},
+		{"acme/api#42", "Rate limiting", "1 update"},
+		{"make deploy", "", "2 times"},
+		{"Meetings", "", "Planning"},
+	}
+	if len(standup.Items) != len(expected) {
+		t.Fatalf("Expected %d items, got %+v", len(expected), standup.Items)
+	}
+	for i, e := range expected {
+		item := standup.Items[i]
+		if item.Key != e.key || item.Title != e.title || item.Summary != e.summary {
+			t.Errorf("Item %d: expected %q %q %q, got %q %q %q", i, e.key, e.title, e.summary, item.Key, item.Title, item.Summary)
+		}
+	}
+	if standup.Items[0].URL != "https://yt.example.com/issue/ACME-123" {
+		t.Errorf("Expected the issue URL, got %q", standup.Items[0].URL)
+	}
+	if len(standup.Meetings) != 0 {
+		t.Errorf("Expected no meetings without a standup day, got %v", standup.Meetings)
+	}
+}
+
+func TestBuildStandup_TodaysMeetings(t *testing.T) {
+	date := time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC)
+	today := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	hour := time.Hour
+
+	todays := []timeline.Activity{
+		{ID: "2", Type: timeline.ActivityTypeCalendar, Title: "Sprint review", Source: "calendar", Timestamp: today.Add(15 * time.Hour), Duration: &hour},
+		{ID: "1", Type: timeline.ActivityTypeCalendar, Title: "Standup", Source: "calendar", Timestamp: today.Add(10 * time.Hour)},
+		{ID: "3", Type: timeline.ActivityTypeGitCommit, Title: "Early commit", Source: "github", Timestamp: today.Add(8 * time.Hour)},
+	}
+	standup := BuildStandup(date, nil, today, todays)
+
+	if len(standup.Meetings) != 2 || standup.Meetings[0].Title != "Standup" {
+		t.Fatalf("Expected today's 2 meetings in order, got %v", standup.Meetings)
+	}
+
+	var out bytes.Buffer
+	if err := RenderStandup(&out, standup, ""); err != nil {
+		t.Fatalf("RenderStandup failed: %v", err)
+	}
+	expected := `**Friday, January 12**
+- Nothing recorded
+
+**Today**
+- 10:00 Standup
+- 15:00 Sprint review (1h 0m)
+`
+	if out.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestRenderStandup(t *testing.T) {
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	standup := BuildStandup(date, standupActivities(date), time.Time{}, nil)
+
+	var out bytes.Buffer
+	if err := RenderStandup(&out, standup, ""); err != nil {
+		t.Fatalf("RenderStandup failed: %v", err)
+	}
+	for _, line := range []string{
+		"**Monday, January 15**",
+		"- [ACME-123](https://yt.example.com/issue/ACME-123) Fix login: moved to Review, 2 commits, 2 comments",
+		"- acme/web: 2 commits",
+		"- make deploy: 2 times",
+		"- Meetings: Planning",
+	} {
+		if !strings.Contains(out.String(), line+"\n") {
+			t.Errorf("Expected line %q in:\n%s", line, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "**Today**") {
+		t.Error("Expected no Today section without meetings")
+	}
+
+	// A team's own layout
+	out.Reset()
+	custom := `Yesterday:{{range .Items}} :white_check_mark: {{.Key}}{{end}}`
+	if err := RenderStandup(&out, standup, custom); err != nil {
+		t.Fatalf("RenderStandup failed: %v", err)
+	}
+	if !strings.HasPrefix(out.String(), "Yesterday: :white_check_mark: ACME-123 :white_check_mark: acme/web") {
+		t.Errorf("Unexpected output of the custom template: %q", out.String())
+	}
+
+	if err := RenderStandup(&out, standup, "{{.Nope"); err == nil {
+		t.Error("Expected an error for an invalid template")
+	}
+}
@@ -73,10 +73,7 @@ func DisplayChronological(activities []timeline.Activity, format string) error {
 // The line is truncated to terminalWidth to ensure it fits on one line.
 func DisplayActivity(activity timeline.Activity, format string, prefix string, isLast bool) {
 	timeStr := activity.Timestamp.Format("15:04")
-	sourceLabel := colors.SourceLabels[activity.Source]
-	if sourceLabel == "" {
-		sourceLabel = strings.ToUpper(activity.Source[:min(3, len(activity.Source))])
-	}
+	sourceLabel := colors.SourceLabel(activity.Source)
 
 	// Build the main text: "Title — Description"
 	mainText := activity.Title
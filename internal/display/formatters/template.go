@@ -0,0 +1,299 @@
+package formatters
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/arkeo/arkeo/internal/display/colors"
+	"github.com/arkeo/arkeo/internal/timeline"
+)
+
+// TemplatesDir is the directory of named templates, relative to the config
+// directory. The template ~/.config/arkeo/templates/weekly.tmpl is named weekly.
+const TemplatesDir = "templates"
+
+// templateExt is the extension of named templates
+const templateExt = ".tmpl"
+
+// minGap is the shortest pause between two activities reported as a gap,
+// the same as in the table view
+const minGap = time.Hour
+
+// TemplateData is the data passed to timeline templates
+type TemplateData struct {
+	// First and last day of the timeline
+	Start time.Time
+	End   time.Time
+
+	// Every day of the timeline, including days without activities
+	Days []TemplateDay
+
+	// Activities of all days by time, and their summary
+	Activities []timeline.Activity
+	Summary    timeline.TimelineSummary
+}
+
+// TemplateDay is one day of a timeline template
+type TemplateDay struct {
+	Date       time.Time
+	Activities []timeline.Activity // by time
+	Summary    timeline.TimelineSummary
+
+	// Time between the first and the last activity
+	Span time.Duration
+
+	// Pauses of more than an hour between two activities
+	Gaps []Gap
+}
+
+// Gap is a pause between two activities
+type Gap struct {
+	Start    time.Time
+	End      time.Time
+	Duration time.Duration
+}
+
+// ActivityGroup holds the activities sharing a value, as returned by the
+// groupBy template function
+type ActivityGroup struct {
+	Key        string
+	Activities []timeline.Activity
+}
+
+// BuildTemplateData builds the data of a timeline template from the
+// activities of each day in dates. activitiesByDay is keyed by the dates.
+func BuildTemplateData(activitiesByDay map[time.Time][]timeline.Activity, dates []time.Time) TemplateData {
+	data := TemplateData{Days: []TemplateDay{}, Activities: []timeline.Activity{}}
+	if len(dates) > 0 {
+		data.Start = dates[0]
+		data.End = dates[len(dates)-1]
+	}
+
+	for _, date := range dates {
+		activities := append([]timeline.Activity{}, activitiesByDay[date]...)
+		sort.SliceStable(activities, func(i, j int) bool {
+			return activities[i].Timestamp.Before(activities[j].Timestamp)
+		})
+
+		tl := timeline.NewTimeline(date)
+		tl.AddActivitiesUnsorted(activities)
+		day := TemplateDay{Date: date, Activities: activities, Summary: tl.GetSummary(), Gaps: []Gap{}}
+		day.Span = day.Summary.TimeRange.End.Sub(day.Summary.TimeRange.Start)
+
+		for i := 1; i < len(activities); i++ {
+			start, end := activities[i-1].Timestamp, activities[i].Timestamp
+			if end.Sub(start) > minGap {
+				day.Gaps = append(day.Gaps, Gap{Start: start, End: end, Duration: end.Sub(start)})
+			}
+		}
+
+		data.Days = append(data.Days, day)
+		data.Activities = append(data.Activities, activities...)
+	}
+
+	tl := timeline.NewTimeline(data.Start)
+	tl.AddActivitiesUnsorted(data.Activities)
+	data.Summary = tl.GetSummary()
+	return data
+}
+
+// TemplateFuncs returns the functions available in timeline and standup
+// templates
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"duration":      templateDuration,
+		"hours":         templateHours,
+		"totalDuration": totalDuration,
+		"truncate":      truncate,
+		"groupBy":       groupBy,
+		"field":         activityField,
+		"sourceLabel":   colors.SourceLabel,
+		"plural":        plural,
+		"join":          func(sep string, values []string) string { return strings.Join(values, sep) },
+		"upper":         strings.ToUpper,
+		"lower":         strings.ToLower,
+		"trim":          strings.TrimSpace,
+	}
+}
+
+// toDuration accepts the durations found in the template data: a
+// time.Duration or an activity's *time.Duration, which may be nil
+func toDuration(value interface{}) (time.Duration, error) {
+	switch d := value.(type) {
+	case time.Duration:
+		return d, nil
+	case *time.Duration:
+		if d == nil {
+			return 0, nil
+		}
+		return *d, nil
+	default:
+		return 0, fmt.Errorf("expected a duration, got %T", value)
+	}
+}
+
+// templateDuration formats a duration like the table view: 45m, 1h30m
+func templateDuration(value interface{}) (string, error) {
+	d, err := toDuration(value)
+	if err != nil {
+		return "", err
+	}
+	return colors.FormatDuration(d), nil
+}
+
+// templateHours formats a duration in decimal hours: 1.50
+func templateHours(value interface{}) (string, error) {
+	d, err := toDuration(value)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%.2f", d.Hours()), nil
+}
+
+// totalDuration adds up the durations of activities
+func totalDuration(activities []timeline.Activity) time.Duration {
+	var total time.Duration
+	for _, a := range activities {
+		if a.Duration != nil {
+			total += *a.Duration
+		}
+	}
+	return total
+}
+
+// truncate shortens s to n characters, ending it with "…" when it is cut
+func truncate(n int, s string) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	if n <= 1 {
+		return "…"
+	}
+	return string([]rune(s)[:n-1]) + "…"
+}
+
+// activityField returns a field of an activity by name: type, source,
+// title, description, url, date (YYYY-MM-DD) or metadata.<key>
+func activityField(name string, a timeline.Activity) (string, error) {
+	if key, ok := strings.CutPrefix(name, "metadata."); ok {
+		return a.Metadata[key], nil
+	}
+	switch name {
+	case "type":
+		return string(a.Type), nil
+	case "source":
+		return a.Source, nil
+	case "title":
+		return a.Title, nil
+	case "description":
+		return a.Description, nil
+	case "url":
+		return a.URL, nil
+	case "date":
+		return a.Timestamp.Format("2006-01-02"), nil
+	}
+	return "", fmt.Errorf("unknown activity field %q", name)
+}
+
+// groupBy groups activities by a field (see activityField), in the order
+// each value first appears
+func groupBy(name string, activities []timeline.Activity) ([]ActivityGroup, error) {
+	groups := []ActivityGroup{}
+	index := make(map[string]int)
+	for _, a := range activities {
+		key, err := activityField(name, a)
+		if err != nil {
+			return nil, err
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, ActivityGroup{Key: key})
+		}
+		groups[i].Activities = append(groups[i].Activities, a)
+	}
+	return groups, nil
+}
+
+// plural returns "1 commit" or "3 commits"
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// ParseTemplate parses a timeline or standup template with TemplateFuncs
+func ParseTemplate(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(TemplateFuncs()).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// RenderTemplate writes the data of a timeline using a text/template
+func RenderTemplate(w io.Writer, data TemplateData, templateText string) error {
+	tmpl, err := ParseTemplate("timeline", templateText)
+	if err != nil {
+		return err
+	}
+	if err := tmpl.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to render template: %w", err)
+	}
+	return nil
+}
+
+// DisplayTemplate prints a timeline to the console using a text/template
+func DisplayTemplate(data TemplateData, templateText string) error {
+	return RenderTemplate(os.Stdout, data, templateText)
+}
+
+// NamedTemplates returns the names of the templates in dir, sorted. A
+// missing directory has no templates.
+func NamedTemplates(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), templateExt); ok && !entry.IsDir() && name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// IsTemplateName reports whether ref names a template in TemplatesDir
+// rather than giving the path of a template file
+func IsTemplateName(ref string) bool {
+	return ref != "" && !strings.ContainsAny(ref, `/\`) && !strings.HasSuffix(ref, templateExt) && ref != "." && ref != ".."
+}
+
+// ReadNamedTemplate reads the template called name from dir
+func ReadNamedTemplate(dir, name string) (string, error) {
+	if !IsTemplateName(name) {
+		return "", fmt.Errorf("invalid template name %q", name)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, name+templateExt))
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("template %q not found in %s", name, dir)
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
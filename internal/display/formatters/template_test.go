@@ -0,0 +1,145 @@
+package formatters
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arkeo/arkeo/internal/timeline"
+)
+
+func TestBuildTemplateData(t *testing.T) {
+	monday := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	tuesday := monday.AddDate(0, 0, 1)
+	meeting := 45 * time.Minute
+
+	activitiesByDay := map[time.Time][]timeline.Activity{
+		monday: {
+			{ID: "2", Type: timeline.ActivityTypeCalendar, Title: "Planning", Source: "calendar", Timestamp: monday.Add(13 * time.Hour), Duration: &meeting},
+			{ID: "1", Type: timeline.ActivityTypeGitCommit, Title: "Fix login", Source: "github", Timestamp: monday.Add(9 * time.Hour)},
+			{ID: "3", Type: timeline.ActivityTypeGitCommit, Title: "Add tests", Source: "github", Timestamp: monday.Add(13*time.Hour + 30*time.Minute)},
+		},
+	}
+
+	data := BuildTemplateData(activitiesByDay, []time.Time{monday, tuesday})
+
+	if !data.Start.Equal(monday) || !data.End.Equal(tuesday) {
+		t.Errorf("Expected %s to %s, got %s to %s", monday, tuesday, data.Start, data.End)
+	}
+	if len(data.Days) != 2 {
+		t.Fatalf("Expected 2 days, got %d", len(data.Days))
+	}
+
+	day := data.Days[0]
+	var ids []string
+	for _, a := range day.Activities {
+		ids = append(ids, a.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"1", "2", "3"}) {
+		t.Errorf("Expected activities by time, got %v", ids)
+	}
+	if day.Summary.TotalActivities != 3 || day.Summary.BySource["github"] != 2 {
+		t.Errorf("Unexpected summary: %+v", day.Summary)
+	}
+	if day.Span != 4*time.Hour+30*time.Minute {
+		t.Errorf("Expected a span of 4h30m, got %s", day.Span)
+	}
+	if len(day.Gaps) != 1 || day.Gaps[0].Duration != 4*time.Hour || !day.Gaps[0].Start.Equal(monday.Add(9*time.Hour)) {
+		t.Errorf("Expected one 4h gap from 09:00, got %+v", day.Gaps)
+	}
+
+	if len(data.Days[1].Activities) != 0 || len(data.Days[1].Gaps) != 0 {
+		t.Errorf("Expected an empty Tuesday, got %+v", data.Days[1])
+	}
+	if len(data.Activities) != 3 || data.Summary.TotalActivities != 3 {
+		t.Errorf("Expected 3 activities in total, got %d", data.Summary.TotalActivities)
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	date := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	data := BuildTemplateData(map[time.Time][]timeline.Activity{date: standupActivities(date)}, []time.Time{date})
+
+	templateText := `{{range .Days}}{{.Date.Format "Mon Jan 2"}}: {{plural .Summary.TotalActivities "event"}} over {{duration .Span}}, meetings {{hours (totalDuration .Activities)}}h
+{{range groupBy "metadata.repository" .Activities}}{{if .Key}}- {{.Key}}: {{len .Activities}}
+{{end}}{{end}}{{range .Gaps}}gap {{.Start.Format "15:04"}} {{duration .Duration}}
+{{end}}{{end}}{{range .Activities}}{{if .Duration}}{{sourceLabel .Source}} {{.Title | truncate 5}} {{duration .Duration}}
+{{end}}{{end}}{{upper "done"}}`
+
+	var buf bytes.Buffer
+	if err := RenderTemplate(&buf, data, templateText); err != nil {
+		t.Fatalf("RenderTemplate failed: %v", err)
+	}
+
+	expected := `Mon Jan 15: 14 events over 7h30m, meetings 0.50h
+- acme/api: 3
+- acme/web: 1
+CAL Plan… 30m
+DONE`
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+
+	// Mistakes are reported instead of rendering half a report
+	for _, bad := range []string{`{{range .Days}`, `{{groupBy "owner" .Activities}}`, `{{duration "1h"}}`} {
+		buf.Reset()
+		if err := RenderTemplate(&buf, data, bad); err == nil {
+			t.Errorf("Expected an error for %q", bad)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		n        int
+		s        string
+		expected string
+	}{
+		{10, "short", "short"},
+		{5, "exact", "exact"},
+		{4, "Planning", "Pla…"},
+		{3, "Größe", "Gr…"},
+		{1, "long", "…"},
+	}
+	for _, tt := range tests {
+		if got := truncate(tt.n, tt.s); got != tt.expected {
+			t.Errorf("truncate(%d, %q): expected %q, got %q", tt.n, tt.s, tt.expected, got)
+		}
+	}
+}
+
+func TestNamedTemplates(t *testing.T) {
+	dir := t.TempDir()
+
+	names, err := NamedTemplates(filepath.Join(dir, "missing"))
+	if err != nil || len(names) != 0 {
+		t.Errorf("Expected no templates in a missing directory, got %v, %v", names, err)
+	}
+
+	for _, name := range []string{"weekly.tmpl", "daily.tmpl", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	names, err = NamedTemplates(dir)
+	if err != nil || !reflect.DeepEqual(names, []string{"daily", "weekly"}) {
+		t.Errorf("Expected [daily weekly], got %v, %v", names, err)
+	}
+
+	text, err := ReadNamedTemplate(dir, "weekly")
+	if err != nil || text != "weekly.tmpl" {
+		t.Errorf("Expected the weekly template, got %q, %v", text, err)
+	}
+	if _, err := ReadNamedTemplate(dir, "monthly"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected a missing template error, got %v", err)
+	}
+	for _, name := range []string{"../weekly", "weekly.tmpl", "..", ""} {
+		if _, err := ReadNamedTemplate(dir, name); err == nil {
+			t.Errorf("Expected %q to be rejected", name)
+		}
+	}
+}
@@ -14,8 +14,15 @@ import (
 // TimelineOptions controls how the timeline is displayed
 type TimelineOptions struct {
 	MaxItems int
-	Format   string      // "table" or "json"
+	Format   string      // "table", "json", "markdown", "template", "csv" or "tsv"
 	Dates    []time.Time // Empty or single date = single day mode, multiple dates = week mode
+
+	// text/template for the template format (formatters.TemplateData) or the
+	// markdown format (formatters.Standup, DefaultStandupTemplate when empty)
+	Template string
+
+	// Columns of the csv and tsv formats (formatters.DefaultColumns when empty)
+	Columns []string
 }
 
 // DefaultTimelineOptions returns sensible defaults for timeline display
@@ -35,6 +42,11 @@ func DisplayTimeline(activities []timeline.Activity, opts TimelineOptions) error
 		return fmt.Errorf("at least one date must be provided")
 	}
 
+	// Templates get all days at once, whether there is one or several
+	if opts.Format == "template" {
+		return displayTemplate(activities, opts)
+	}
+
 	// Single day mode
 	if len(opts.Dates) == 1 {
 		return displaySingleDay(activities, opts.Dates[0], opts)
@@ -69,6 +81,10 @@ func displaySingleDay(activities []timeline.Activity, date time.Time, opts Timel
 	switch opts.Format {
 	case "json":
 		return formatters.DisplayJSON(tl, dayActivities)
+	case "markdown":
+		return formatters.DisplayStandup(formatters.BuildStandup(dateStart, dayActivities, time.Time{}, nil), opts.Template)
+	case "csv", "tsv":
+		return formatters.DisplayDelimited(dayActivities, opts.Columns, delimiter(opts.Format))
 	default:
 		return formatters.DisplayTable(tl, dayActivities, opts.Format)
 	}
@@ -83,6 +99,10 @@ func displayMultipleDays(activities []timeline.Activity, dates []time.Time, opts
 	switch opts.Format {
 	case "json":
 		return displayMultipleDaysJSON(activitiesByDay, dates, opts)
+	case "markdown":
+		return displayMultipleDaysMarkdown(activitiesByDay, dates, opts)
+	case "csv", "tsv":
+		return displayMultipleDaysDelimited(activitiesByDay, dates, opts)
 	default:
 		return displayMultipleDaysTable(activitiesByDay, dates, opts)
 	}
@@ -214,6 +234,77 @@ func displayMultipleDaysTable(activitiesByDay map[time.Time][]timeline.Activity,
 	return nil
 }
 
+// displayMultipleDaysMarkdown outputs one standup report per day with activities
+func displayMultipleDaysMarkdown(activitiesByDay map[time.Time][]timeline.Activity, dates []time.Time, opts TimelineOptions) error {
+	first := true
+	for _, date := range dates {
+		dayActivities := activitiesByDay[date.Truncate(24*time.Hour)]
+		if len(dayActivities) == 0 {
+			continue
+		}
+		if !first {
+			fmt.Println()
+		}
+		first = false
+
+		standup := formatters.BuildStandup(date.Truncate(24*time.Hour), dayActivities, time.Time{}, nil)
+		if err := formatters.DisplayStandup(standup, opts.Template); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// displayMultipleDaysDelimited outputs the activities of all days as one
+// CSV or TSV table, day by day
+func displayMultipleDaysDelimited(activitiesByDay map[time.Time][]timeline.Activity, dates []time.Time, opts TimelineOptions) error {
+	var activities []timeline.Activity
+	for _, date := range dates {
+		dayActivities := activitiesByDay[date.Truncate(24*time.Hour)]
+
+		// Sort before truncating so that MaxItems keeps the earliest activities
+		sortActivitiesByTime(dayActivities)
+		if opts.MaxItems > 0 && len(dayActivities) > opts.MaxItems {
+			dayActivities = dayActivities[:opts.MaxItems]
+		}
+		activities = append(activities, dayActivities...)
+	}
+	return formatters.DisplayDelimited(activities, opts.Columns, delimiter(opts.Format))
+}
+
+// displayTemplate renders all days of the timeline with a user template
+func displayTemplate(activities []timeline.Activity, opts TimelineOptions) error {
+	if opts.Template == "" {
+		return fmt.Errorf("the template format needs a template")
+	}
+
+	dates := make([]time.Time, len(opts.Dates))
+	for i, date := range opts.Dates {
+		dates[i] = date.Truncate(24 * time.Hour)
+	}
+
+	activitiesByDay := groupActivitiesByDay(activities, dates)
+	for date, dayActivities := range activitiesByDay {
+		sortActivitiesByTime(dayActivities)
+		if opts.MaxItems > 0 && len(dayActivities) > opts.MaxItems {
+			activitiesByDay[date] = dayActivities[:opts.MaxItems]
+		}
+	}
+
+	return formatters.DisplayTemplate(formatters.BuildTemplateData(activitiesByDay, dates), opts.Template)
+}
+
+// DisplayStandup prints a standup report of the activities on date,
+// followed by the calendar events on today, the day of the standup
+func DisplayStandup(activities []timeline.Activity, date, today time.Time, templateText string) error {
+	activitiesByDay := groupActivitiesByDay(activities, []time.Time{date, today})
+	standup := formatters.BuildStandup(date.Truncate(24*time.Hour),
+		activitiesByDay[date.Truncate(24*time.Hour)],
+		today.Truncate(24*time.Hour),
+		activitiesByDay[today.Truncate(24*time.Hour)])
+	return formatters.DisplayStandup(standup, templateText)
+}
+
 // Helper functions
 
 // groupActivitiesByDay groups activities by their date (ignoring time and timezone).
@@ -243,6 +334,14 @@ func groupActivitiesByDay(activities []timeline.Activity, dates []time.Time) map
 	return activitiesByDay
 }
 
+// delimiter returns the field separator of the csv or tsv format
+func delimiter(format string) rune {
+	if format == "tsv" {
+		return '\t'
+	}
+	return ','
+}
+
 // sortActivitiesByTime sorts activities by timestamp
 func sortActivitiesByTime(activities []timeline.Activity) {
 	sort.Slice(activities, func(i, j int) bool {
@@ -174,6 +174,135 @@ func TestDisplayTimeline_JSONFormat(t *testing.T) {
 
 // Helper functions
 
+func TestDisplayTimeline_MarkdownFormat(t *testing.T) {
+	tl := createTestTimeline()
+	opts := DefaultTimelineOptions()
+	opts.Format = "markdown"
+	opts.Dates = []time.Time{tl.Date}
+
+	output := captureOutput(func() {
+		if err := DisplayTimeline(tl.Activities, opts); err != nil {
+			t.Errorf("DisplayTimeline failed: %v", err)
+		}
+	})
+
+	expected := `**Monday, January 15**
+- [Fix authentication bug](https://github.com/example/repo/commit/abc123)
+- [Add unit tests](https://github.com/example/repo/commit/def456)
+- Meetings: Morning standup
+`
+	if output != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, output)
+	}
+
+	// Several days give one report each
+	opts.Dates = []time.Time{tl.Date.AddDate(0, 0, -1), tl.Date}
+	opts.Template = "{{.Date.Format \"Jan 2\"}}: {{len .Items}}\n"
+	output = captureOutput(func() {
+		if err := DisplayTimeline(tl.Activities, opts); err != nil {
+			t.Errorf("DisplayTimeline failed: %v", err)
+		}
+	})
+	if output != "Jan 15: 3\n" {
+		t.Errorf("Expected only the day with activities, got %q", output)
+	}
+}
+
+func TestDisplayTimeline_TemplateFormat(t *testing.T) {
+	tl := createTestTimeline()
+	opts := DefaultTimelineOptions()
+	opts.Format = "template"
+	opts.MaxItems = 2
+	opts.Dates = []time.Time{tl.Date.AddDate(0, 0, -1), tl.Date}
+	opts.Template = `{{range .Days}}{{.Date.Format "Mon"}} {{len .Activities}}
+{{end}}{{.Summary.TotalActivities}}
+`
+
+	output := captureOutput(func() {
+		if err := DisplayTimeline(tl.Activities, opts); err != nil {
+			t.Errorf("DisplayTimeline failed: %v", err)
+		}
+	})
+
+	// Days without activities are kept, MaxItems applies to each day
+	if output != "Sun 0\nMon 2\n2\n" {
+		t.Errorf("Expected both days with at most 2 activities, got %q", output)
+	}
+
+	opts.Template = ""
+	if err := DisplayTimeline(tl.Activities, opts); err == nil {
+		t.Error("Expected an error without a template")
+	}
+}
+
+func TestDisplayTimeline_CSVFormat(t *testing.T) {
+	tl := createTestTimeline()
+	opts := DefaultTimelineOptions()
+	opts.Format = "csv"
+	opts.Columns = []string{"date", "time", "source", "title"}
+
+	for _, dates := range [][]time.Time{
+		{tl.Date},
+		{tl.Date.AddDate(0, 0, -1), tl.Date},
+	} {
+		opts.Dates = dates
+		output := captureOutput(func() {
+			if err := DisplayTimeline(tl.Activities, opts); err != nil {
+				t.Errorf("DisplayTimeline failed: %v", err)
+			}
+		})
+
+		lines := strings.Split(strings.TrimSpace(output), "\n")
+		if len(lines) != len(tl.Activities)+1 {
+			t.Fatalf("Expected a header and %d rows for %d day(s), got:\n%s", len(tl.Activities), len(dates), output)
+		}
+		if lines[0] != "date,time,source,title" {
+			t.Errorf("Expected the header row, got %q", lines[0])
+		}
+		if !strings.HasPrefix(lines[1], "2024-01-15,") {
+			t.Errorf("Expected rows by time, got %q", lines[1])
+		}
+	}
+
+	opts.Format = "tsv"
+	opts.Dates = []time.Time{tl.Date}
+	output := captureOutput(func() {
+		if err := DisplayTimeline(tl.Activities, opts); err != nil {
+			t.Errorf("DisplayTimeline failed: %v", err)
+		}
+	})
+	if !strings.HasPrefix(output, "date\ttime\tsource\ttitle\n") {
+		t.Errorf("Expected tab-separated output, got %q", output)
+	}
+}
+
+func TestDisplayStandup(t *testing.T) {
+	friday := time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC)
+	monday := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	activities := []timeline.Activity{
+		{ID: "1", Type: timeline.ActivityTypeGitCommit, Title: "Ship it", Source: "github", Timestamp: friday.Add(16 * time.Hour),
+			Metadata: map[string]string{"repository": "acme/api"}},
+		{ID: "2", Type: timeline.ActivityTypeCalendar, Title: "Sprint planning", Source: "calendar", Timestamp: monday.Add(10 * time.Hour)},
+		{ID: "3", Type: timeline.ActivityTypeGitCommit, Title: "Monday commit", Source: "github", Timestamp: monday.Add(9 * time.Hour)},
+	}
+
+	output := captureOutput(func() {
+		if err := DisplayStandup(activities, friday, monday, ""); err != nil {
+			t.Errorf("DisplayStandup failed: %v", err)
+		}
+	})
+
+	expected := `**Friday, January 12**
+- acme/api: 1 commit
+
+**Today**
+- 10:00 Sprint planning
+`
+	if output != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, output)
+	}
+}
+
 func captureOutput(f func()) string {
 	old := os.Stdout
 	r, w, _ := os.Pipe()
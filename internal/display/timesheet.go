@@ -0,0 +1,194 @@
+package display
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/arkeo/arkeo/internal/display/colors"
+	"github.com/arkeo/arkeo/internal/timesheet"
+)
+
+// DisplayTimesheet renders a project × day timesheet in the given format
+// ("table", "csv" or "json")
+func DisplayTimesheet(ts *timesheet.Timesheet, format string) error {
+	switch format {
+	case "json":
+		return writeTimesheetJSON(os.Stdout, ts)
+	case "csv":
+		return writeTimesheetCSV(os.Stdout, ts)
+	case "table", "":
+		displayTimesheetTable(ts)
+		return nil
+	default:
+		return fmt.Errorf("unsupported timesheet format: %s (use table, csv or json)", format)
+	}
+}
+
+// writeTimesheetJSON writes the timesheet as indented JSON
+func writeTimesheetJSON(w io.Writer, ts *timesheet.Timesheet) error {
+	type jsonRow struct {
+		Project string             `json:"project"`
+		Hours   map[string]float64 `json:"hours"`
+		Total   float64            `json:"total"`
+	}
+	type jsonTimesheet struct {
+		Days      []string           `json:"days"`
+		Projects  []jsonRow          `json:"projects"`
+		DayTotals map[string]float64 `json:"day_totals"`
+		Total     float64            `json:"total"`
+		Blocks    []timesheet.Block  `json:"blocks"`
+	}
+
+	out := jsonTimesheet{
+		Days:      timesheetDayKeys(ts),
+		Projects:  make([]jsonRow, 0, len(ts.Rows)),
+		DayTotals: ts.DayTotals,
+		Total:     ts.Total,
+		Blocks:    ts.Blocks,
+	}
+	for _, row := range ts.Rows {
+		out.Projects = append(out.Projects, jsonRow(row))
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal timesheet: %w", err)
+	}
+
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// writeTimesheetCSV writes one row per project with a column per day
+func writeTimesheetCSV(w io.Writer, ts *timesheet.Timesheet) error {
+	days := timesheetDayKeys(ts)
+	cw := csv.NewWriter(w)
+
+	header := append([]string{"project"}, days...)
+	header = append(header, "total")
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, row := range ts.Rows {
+		record := []string{row.Project}
+		for _, day := range days {
+			record = append(record, formatHours(row.Hours[day]))
+		}
+		record = append(record, formatHours(row.Total))
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	totals := []string{"total"}
+	for _, day := range days {
+		totals = append(totals, formatHours(ts.DayTotals[day]))
+	}
+	totals = append(totals, formatHours(ts.Total))
+	if err := cw.Write(totals); err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// displayTimesheetTable prints the timesheet as an aligned, colored grid
+func displayTimesheetTable(ts *timesheet.Timesheet) {
+	if len(ts.Days) > 0 {
+		first := ts.Days[0]
+		last := ts.Days[len(ts.Days)-1]
+		var title string
+		if len(ts.Days) == 5 && first.Weekday() == time.Monday {
+			title = fmt.Sprintf("Timesheet for Week of %s", first.Format("Monday, January 2, 2006"))
+		} else {
+			title = fmt.Sprintf("Timesheet for %s – %s", first.Format("January 2, 2006"), last.Format("January 2, 2006"))
+		}
+		fmt.Printf("%s\n", colors.Colorize(title, colors.Bold+colors.Blue))
+	}
+
+	if len(ts.Rows) == 0 {
+		fmt.Printf("No work found for the selected days.\n")
+		return
+	}
+	fmt.Println()
+
+	projectWidth := len("Project")
+	for _, row := range ts.Rows {
+		if n := utf8.RuneCountInString(row.Project); n > projectWidth {
+			projectWidth = n
+		}
+	}
+	if projectWidth > 40 {
+		projectWidth = 40
+	}
+	const cellWidth = 7
+
+	// Header: one column per day, labelled "Mon 03"
+	var header strings.Builder
+	header.WriteString(padRight("Project", projectWidth))
+	for _, day := range ts.Days {
+		header.WriteString(fmt.Sprintf(" %*s", cellWidth, day.Format("Mon 02")))
+	}
+	header.WriteString(fmt.Sprintf(" %*s", cellWidth, "Total"))
+	fmt.Println(colors.Colorize(header.String(), colors.Bold))
+
+	for _, row := range ts.Rows {
+		fmt.Print(padRight(truncateRunes(row.Project, projectWidth), projectWidth))
+		for _, day := range ts.Days {
+			hours := row.Hours[day.Format("2006-01-02")]
+			cell := fmt.Sprintf(" %*s", cellWidth, formatHours(hours))
+			if hours == 0 {
+				cell = colors.Colorize(fmt.Sprintf(" %*s", cellWidth, "-"), colors.DarkGray)
+			}
+			fmt.Print(cell)
+		}
+		fmt.Println(colors.Colorize(fmt.Sprintf(" %*s", cellWidth, formatHours(row.Total)), colors.Bold))
+	}
+
+	var footer strings.Builder
+	footer.WriteString(padRight("Total", projectWidth))
+	for _, day := range ts.Days {
+		footer.WriteString(fmt.Sprintf(" %*s", cellWidth, formatHours(ts.DayTotals[day.Format("2006-01-02")])))
+	}
+	footer.WriteString(fmt.Sprintf(" %*s", cellWidth, formatHours(ts.Total)))
+	fmt.Println(colors.Colorize(footer.String(), colors.Bold+colors.Green))
+}
+
+// timesheetDayKeys returns the timesheet days formatted as YYYY-MM-DD
+func timesheetDayKeys(ts *timesheet.Timesheet) []string {
+	keys := make([]string, len(ts.Days))
+	for i, day := range ts.Days {
+		keys[i] = day.Format("2006-01-02")
+	}
+	return keys
+}
+
+// formatHours formats hours with up to two decimals ("1.5", "0.25", "2")
+func formatHours(hours float64) string {
+	return strconv.FormatFloat(math.Round(hours*100)/100, 'f', -1, 64)
+}
+
+func padRight(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+func truncateRunes(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:max-1]) + "…"
+}
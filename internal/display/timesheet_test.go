@@ -0,0 +1,90 @@
+package display
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arkeo/arkeo/internal/timesheet"
+)
+
+func createTestTimesheet() *timesheet.Timesheet {
+	return &timesheet.Timesheet{
+		Days: []time.Time{
+			time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC),
+		},
+		Rows: []timesheet.Row{
+			{Project: "acme/api", Hours: map[string]float64{"2024-03-04": 3.5, "2024-03-05": 2}, Total: 5.5},
+			{Project: "Meetings", Hours: map[string]float64{"2024-03-05": 0.25}, Total: 0.25},
+		},
+		DayTotals: map[string]float64{"2024-03-04": 3.5, "2024-03-05": 2.25},
+		Total:     5.75,
+	}
+}
+
+func TestWriteTimesheetCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeTimesheetCSV(&buf, createTestTimesheet()); err != nil {
+		t.Fatalf("writeTimesheetCSV failed: %v", err)
+	}
+
+	expected := "project,2024-03-04,2024-03-05,total\n" +
+		"acme/api,3.5,2,5.5\n" +
+		"Meetings,0,0.25,0.25\n" +
+		"total,3.5,2.25,5.75\n"
+	if buf.String() != expected {
+		t.Errorf("Expected CSV:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestWriteTimesheetJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeTimesheetJSON(&buf, createTestTimesheet()); err != nil {
+		t.Fatalf("writeTimesheetJSON failed: %v", err)
+	}
+
+	var result struct {
+		Days     []string `json:"days"`
+		Projects []struct {
+			Project string             `json:"project"`
+			Hours   map[string]float64 `json:"hours"`
+		} `json:"projects"`
+		Total float64 `json:"total"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+
+	if len(result.Days) != 2 || result.Days[0] != "2024-03-04" {
+		t.Errorf("Unexpected days: %v", result.Days)
+	}
+	if len(result.Projects) != 2 || result.Projects[0].Hours["2024-03-04"] != 3.5 {
+		t.Errorf("Unexpected projects: %+v", result.Projects)
+	}
+	if result.Total != 5.75 {
+		t.Errorf("Expected total 5.75, got %v", result.Total)
+	}
+}
+
+func TestDisplayTimesheet_Table(t *testing.T) {
+	output := captureOutput(func() {
+		if err := DisplayTimesheet(createTestTimesheet(), "table"); err != nil {
+			t.Errorf("DisplayTimesheet failed: %v", err)
+		}
+	})
+
+	for _, expected := range []string{"Timesheet for March 4, 2024", "acme/api", "Mon 04", "Tue 05", "5.75"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got %q", expected, output)
+		}
+	}
+}
+
+func TestDisplayTimesheet_UnsupportedFormat(t *testing.T) {
+	if err := DisplayTimesheet(createTestTimesheet(), "xml"); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+}
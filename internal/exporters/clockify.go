@@ -0,0 +1,61 @@
+package exporters
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// clockifyBaseURL is the Clockify API root
+const clockifyBaseURL = "https://api.clockify.me/api/v1/"
+
+// ClockifySink exports time entries to Clockify. Project references are
+// Clockify project IDs, optionally followed by ":taskID".
+type ClockifySink struct {
+	config     map[string]interface{}
+	httpClient *http.Client
+}
+
+// NewClockifySink creates a new Clockify sink
+func NewClockifySink() *ClockifySink {
+	return &ClockifySink{httpClient: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Name returns the sink name
+func (c *ClockifySink) Name() string {
+	return "clockify"
+}
+
+// Configure requires api_key and workspace_id; base_url is optional
+func (c *ClockifySink) Configure(config map[string]interface{}) error {
+	if err := requireConfig(config, "api_key", "workspace_id"); err != nil {
+		return err
+	}
+	c.config = config
+	return nil
+}
+
+// CreateEntry creates a time entry in the workspace
+func (c *ClockifySink) CreateEntry(ctx context.Context, entry TimeEntry, projectRef string) error {
+	baseURL := configString(c.config, "base_url")
+	if baseURL == "" {
+		baseURL = clockifyBaseURL
+	}
+	apiURL := strings.TrimSuffix(baseURL, "/") + "/workspaces/" + configString(c.config, "workspace_id") + "/time-entries"
+
+	projectID, taskID := splitRef(projectRef)
+	body := map[string]interface{}{
+		"start":       entry.Start.UTC().Format("2006-01-02T15:04:05Z"),
+		"end":         entry.End.UTC().Format("2006-01-02T15:04:05Z"),
+		"projectId":   projectID,
+		"description": entry.Description,
+		"billable":    entry.Billable,
+	}
+	if taskID != "" {
+		body["taskId"] = taskID
+	}
+
+	headers := map[string]string{"X-Api-Key": configString(c.config, "api_key")}
+	return postJSON(ctx, c.httpClient, apiURL, headers, body)
+}
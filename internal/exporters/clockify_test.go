@@ -0,0 +1,35 @@
+package exporters
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestClockifySink_CreateEntry(t *testing.T) {
+	server, requests := captureServer(t, http.StatusCreated)
+	defer server.Close()
+
+	sink := NewClockifySink()
+	if err := sink.Configure(map[string]interface{}{"api_key": "secret", "workspace_id": "ws1", "base_url": server.URL}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	if err := sink.CreateEntry(context.Background(), testEntry(), "proj1:task1"); err != nil {
+		t.Fatalf("CreateEntry failed: %v", err)
+	}
+
+	req := (*requests)[0]
+	if req.Method != "POST" || req.Path != "/workspaces/ws1/time-entries" {
+		t.Errorf("Unexpected request %s %s", req.Method, req.Path)
+	}
+	if req.Header.Get("X-Api-Key") != "secret" {
+		t.Errorf("Expected X-Api-Key header, got %q", req.Header.Get("X-Api-Key"))
+	}
+	if req.Body["start"] != "2024-01-15T09:00:00Z" || req.Body["end"] != "2024-01-15T10:30:00Z" {
+		t.Errorf("Unexpected span: %v - %v", req.Body["start"], req.Body["end"])
+	}
+	if req.Body["projectId"] != "proj1" || req.Body["taskId"] != "task1" || req.Body["billable"] != true {
+		t.Errorf("Unexpected body: %v", req.Body)
+	}
+}
@@ -0,0 +1,329 @@
+package exporters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/arkeo/arkeo/internal/config"
+	"github.com/arkeo/arkeo/internal/rules"
+	"github.com/arkeo/arkeo/internal/timeline"
+	"github.com/arkeo/arkeo/internal/timesheet"
+)
+
+// Export statuses reported for each time entry
+const (
+	StatusNew      = "new"
+	StatusCreated  = "created"
+	StatusExists   = "exists"
+	StatusUnmapped = "unmapped"
+	StatusError    = "error"
+)
+
+// maxDescription is the longest description built from activity titles
+const maxDescription = 500
+
+// TimeEntry is a stretch of work on one project, as sent to a time
+// tracking tool
+type TimeEntry struct {
+	Project     string    `json:"project"`
+	Client      string    `json:"client,omitempty"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	Description string    `json:"description"`
+	Billable    bool      `json:"billable"`
+}
+
+// Duration returns the length of the entry
+func (e TimeEntry) Duration() time.Duration {
+	return e.End.Sub(e.Start)
+}
+
+// TimeEntrySink is a time tracking tool time entries can be exported to
+type TimeEntrySink interface {
+	// Name returns the name used with 'arkeo export --to'
+	Name() string
+
+	// Configure applies the exporter section of the config file
+	Configure(config map[string]interface{}) error
+
+	// CreateEntry creates the entry in the remote project identified by
+	// projectRef, a project ID from the project mapping
+	CreateEntry(ctx context.Context, entry TimeEntry, projectRef string) error
+}
+
+// ExportLog remembers how much time was exported to each remote project of
+// a sink per local day, so that exporting the same days again only sends
+// the time that is new, even when the blocks of a day have moved or grown
+// since. *cache.Cache implements it.
+type ExportLog interface {
+	ExportedDuration(sink, projectRef string, day time.Time) (time.Duration, error)
+	AddExported(sink, projectRef string, day time.Time, d time.Duration) error
+}
+
+// Result is the outcome of exporting a single time entry
+type Result struct {
+	Entry         TimeEntry `json:"entry"`
+	RemoteProject string    `json:"remote_project,omitempty"`
+	Status        string    `json:"status"`
+
+	// Existing is the part of the entry exported before. Only the rest of
+	// the entry, starting that much later, is sent.
+	Existing time.Duration `json:"existing,omitempty"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// sinkFactories creates the available sinks by name
+var sinkFactories = map[string]func() TimeEntrySink{
+	"clockify": func() TimeEntrySink { return NewClockifySink() },
+	"toggl":    func() TimeEntrySink { return NewTogglSink() },
+	"harvest":  func() TimeEntrySink { return NewHarvestSink() },
+	"kimai":    func() TimeEntrySink { return NewKimaiSink() },
+}
+
+// Names returns the names of the available sinks, sorted
+func Names() []string {
+	names := make([]string, 0, len(sinkFactories))
+	for name := range sinkFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New creates the named sink configured from its export section
+func New(name string, cfg config.ExportConfig) (TimeEntrySink, error) {
+	factory, ok := sinkFactories[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown exporter %q (available: %s)", name, strings.Join(Names(), ", "))
+	}
+	sink := factory()
+	if err := sink.Configure(cfg.Config); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return sink, nil
+}
+
+// Mapping resolves arkeo projects and clients to remote project IDs
+type Mapping struct {
+	entries []config.ExportProjectConfig
+}
+
+// NewMapping creates a mapping from the projects of an export section
+func NewMapping(projects []config.ExportProjectConfig) *Mapping {
+	return &Mapping{entries: projects}
+}
+
+// Resolve returns the remote project of an entry: the first mapping whose
+// project and client (when set) equal the entry's, case-insensitively
+func (m *Mapping) Resolve(entry TimeEntry) (string, bool) {
+	for _, p := range m.entries {
+		if p.ID == "" {
+			continue
+		}
+		if p.Project != "" && !strings.EqualFold(p.Project, entry.Project) {
+			continue
+		}
+		if p.Client != "" && !strings.EqualFold(p.Client, entry.Client) {
+			continue
+		}
+		return p.ID, true
+	}
+	return "", false
+}
+
+// Export sends the entries to the sink. Entries without a remote project
+// are skipped. Time the log has as exported before to the same remote
+// project on the same day is taken off the day's first entries: entries it
+// covers completely are reported with StatusExists, and an entry it covers
+// in part is sent without that part. Sent time is added to the log; without
+// a log every entry is sent in full. With dryRun nothing is sent and the
+// entries that would be are reported with StatusNew.
+func Export(ctx context.Context, sink TimeEntrySink, entries []TimeEntry, mapping *Mapping, exportLog ExportLog, dryRun bool) []Result {
+	// Time exported before that is not yet matched to an entry, by remote
+	// project and day
+	type dayKey struct{ projectRef, date string }
+	unmatched := make(map[dayKey]time.Duration)
+	loaded := make(map[dayKey]bool)
+
+	results := make([]Result, 0, len(entries))
+	for _, entry := range entries {
+		result := Result{Entry: entry}
+
+		projectRef, ok := mapping.Resolve(entry)
+		if !ok {
+			result.Status = StatusUnmapped
+			results = append(results, result)
+			continue
+		}
+		result.RemoteProject = projectRef
+
+		key := dayKey{projectRef, entry.Start.In(time.Local).Format("2006-01-02")}
+		if exportLog != nil && !loaded[key] {
+			exported, err := exportLog.ExportedDuration(sink.Name(), projectRef, entry.Start)
+			if err != nil {
+				result.Status = StatusError
+				result.Error = err.Error()
+				results = append(results, result)
+				continue
+			}
+			unmatched[key] = exported
+			loaded[key] = true
+		}
+
+		result.Existing = min(unmatched[key], entry.Duration())
+		unmatched[key] -= result.Existing
+
+		switch {
+		case result.Existing > 0 && result.Existing == entry.Duration():
+			result.Status = StatusExists
+		case dryRun:
+			result.Entry.Start = entry.Start.Add(result.Existing)
+			result.Status = StatusNew
+		default:
+			result.Entry.Start = entry.Start.Add(result.Existing)
+			if err := sink.CreateEntry(ctx, result.Entry, projectRef); err != nil {
+				result.Status = StatusError
+				result.Error = err.Error()
+				break
+			}
+			result.Status = StatusCreated
+			if exportLog != nil {
+				if err := exportLog.AddExported(sink.Name(), projectRef, entry.Start, result.Entry.Duration()); err != nil {
+					result.Error = fmt.Sprintf("created, but not recorded as exported: %v", err)
+				}
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// EntriesFromBlocks turns the work blocks of a timesheet into time entries.
+// Each block's client, billable flag and description come from the
+// activities of its project within the block. Durations are rounded to the
+// nearest increment; entries that round to nothing are dropped.
+func EntriesFromBlocks(ts *timesheet.Timesheet, activities []timeline.Activity, increment time.Duration) []TimeEntry {
+	sorted := make([]timeline.Activity, len(activities))
+	copy(sorted, activities)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	var entries []TimeEntry
+	for _, b := range ts.Blocks {
+		d := b.End.Sub(b.Start)
+		if increment > 0 {
+			d = d.Round(increment)
+		}
+		if d <= 0 {
+			continue
+		}
+
+		entry := TimeEntry{Project: b.Project, Start: b.Start, End: b.Start.Add(d)}
+
+		clients := make(map[string]int)
+		var titles []string
+		seen := make(map[string]bool)
+		for _, a := range sorted {
+			if a.Timestamp.Before(b.Start) || a.Timestamp.After(b.End) {
+				continue
+			}
+			if p := a.Metadata[rules.ProjectKey]; p != "" && p != b.Project {
+				continue
+			}
+			if c := a.Metadata[rules.ClientKey]; c != "" {
+				clients[c]++
+			}
+			if a.Metadata[rules.BillableKey] == "true" {
+				entry.Billable = true
+			}
+			if title := strings.TrimSpace(a.Title); title != "" && !seen[title] {
+				seen[title] = true
+				titles = append(titles, title)
+			}
+		}
+
+		for c, n := range clients {
+			if entry.Client == "" || n > clients[entry.Client] || (n == clients[entry.Client] && c < entry.Client) {
+				entry.Client = c
+			}
+		}
+
+		entry.Description = strings.Join(titles, "; ")
+		if runes := []rune(entry.Description); len(runes) > maxDescription {
+			entry.Description = string(runes[:maxDescription-3]) + "..."
+		}
+		if entry.Description == "" {
+			entry.Description = b.Project
+		}
+
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// configString returns a string value of an exporter config section
+func configString(config map[string]interface{}, key string) string {
+	if v, ok := config[key]; ok && v != nil {
+		if s, ok := v.(string); ok {
+			return strings.TrimSpace(s)
+		}
+		return fmt.Sprintf("%v", v)
+	}
+	return ""
+}
+
+// requireConfig returns an error naming the first missing key
+func requireConfig(config map[string]interface{}, keys ...string) error {
+	for _, key := range keys {
+		if configString(config, key) == "" {
+			return fmt.Errorf("%s is required", key)
+		}
+	}
+	return nil
+}
+
+// splitRef splits a "project:task" reference. The second part is empty
+// when the reference has none.
+func splitRef(ref string) (string, string) {
+	project, task, _ := strings.Cut(ref, ":")
+	return strings.TrimSpace(project), strings.TrimSpace(task)
+}
+
+// postJSON sends body as JSON and fails on any non-2xx response
+func postJSON(ctx context.Context, client *http.Client, apiURL string, headers map[string]string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "arkeo")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("API returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+package exporters
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/arkeo/arkeo/internal/cache"
+	"github.com/arkeo/arkeo/internal/config"
+	"github.com/arkeo/arkeo/internal/timeline"
+	"github.com/arkeo/arkeo/internal/timesheet"
+)
+
+// capturedRequest is the last request received by a capture server
+type capturedRequest struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   map[string]interface{}
+}
+
+// captureServer records the requests it receives and answers with status
+func captureServer(t *testing.T, status int) (*httptest.Server, *[]capturedRequest) {
+	t.Helper()
+	var requests []capturedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := capturedRequest{Method: r.Method, Path: r.URL.Path, Header: r.Header.Clone()}
+		if err := json.NewDecoder(r.Body).Decode(&req.Body); err != nil {
+			t.Errorf("Invalid JSON body: %v", err)
+		}
+		requests = append(requests, req)
+		w.WriteHeader(status)
+		w.Write([]byte(`{"id": "1"}`))
+	}))
+	return server, &requests
+}
+
+func testEntry() TimeEntry {
+	start := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	return TimeEntry{
+		Project:     "ACME API",
+		Client:      "Acme Corp",
+		Start:       start,
+		End:         start.Add(90 * time.Minute),
+		Description: "Fix login redirect",
+		Billable:    true,
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New("clockify", config.ExportConfig{Config: map[string]interface{}{"api_key": "k", "workspace_id": "w"}}); err != nil {
+		t.Errorf("Expected clockify sink, got error: %v", err)
+	}
+	if _, err := New("clockify", config.ExportConfig{Config: map[string]interface{}{"api_key": "k"}}); err == nil {
+		t.Error("Expected an error for a missing workspace_id")
+	}
+	if _, err := New("jira", config.ExportConfig{}); err == nil {
+		t.Error("Expected an error for an unknown exporter")
+	}
+}
+
+func TestMapping_Resolve(t *testing.T) {
+	mapping := NewMapping([]config.ExportProjectConfig{
+		{Project: "acme api", ID: "p-api"},
+		{Client: "Acme Corp", ID: "p-acme"},
+		{Project: "Unmapped", ID: ""},
+		{ID: "p-default"},
+	})
+
+	tests := []struct {
+		entry    TimeEntry
+		expected string
+	}{
+		{TimeEntry{Project: "ACME API", Client: "Acme Corp"}, "p-api"},
+		{TimeEntry{Project: "ACME Web", Client: "Acme Corp"}, "p-acme"},
+		{TimeEntry{Project: "Meetings"}, "p-default"},
+	}
+	for _, tt := range tests {
+		if got, _ := mapping.Resolve(tt.entry); got != tt.expected {
+			t.Errorf("Resolve(%s/%s) = %q, expected %q", tt.entry.Project, tt.entry.Client, got, tt.expected)
+		}
+	}
+
+	strict := NewMapping([]config.ExportProjectConfig{{Project: "ACME API", ID: "p-api"}})
+	if _, ok := strict.Resolve(TimeEntry{Project: "Meetings"}); ok {
+		t.Error("Expected no mapping without a catch-all entry")
+	}
+}
+
+func TestExport(t *testing.T) {
+	server, requests := captureServer(t, http.StatusCreated)
+	defer server.Close()
+
+	sink := NewClockifySink()
+	sink.Configure(map[string]interface{}{"api_key": "k", "workspace_id": "w", "base_url": server.URL})
+
+	mapping := NewMapping([]config.ExportProjectConfig{{Project: "ACME API", ID: "p-api"}})
+	entries := []TimeEntry{testEntry(), {Project: "Meetings", Start: testEntry().Start, End: testEntry().End}}
+
+	results := Export(context.Background(), sink, entries, mapping, nil, true)
+	if results[0].Status != StatusNew || results[1].Status != StatusUnmapped || len(*requests) != 0 {
+		t.Errorf("Unexpected dry run results %+v with %d requests", results, len(*requests))
+	}
+
+	results = Export(context.Background(), sink, entries, mapping, nil, false)
+	if results[0].Status != StatusCreated || results[0].RemoteProject != "p-api" {
+		t.Errorf("Expected created entry, got %+v", results[0])
+	}
+	if results[1].Status != StatusUnmapped || len(*requests) != 1 {
+		t.Errorf("Expected unmapped entry to be skipped, got %+v with %d requests", results[1], len(*requests))
+	}
+}
+
+func TestExport_Error(t *testing.T) {
+	server, _ := captureServer(t, http.StatusForbidden)
+	defer server.Close()
+
+	sink := NewClockifySink()
+	sink.Configure(map[string]interface{}{"api_key": "k", "workspace_id": "w", "base_url": server.URL})
+
+	mapping := NewMapping([]config.ExportProjectConfig{{ID: "p-default"}})
+	results := Export(context.Background(), sink, []TimeEntry{testEntry()}, mapping, nil, false)
+	if results[0].Status != StatusError || results[0].Error == "" {
+		t.Errorf("Expected an error result, got %+v", results[0])
+	}
+}
+
+func TestExport_Rerun(t *testing.T) {
+	server, requests := captureServer(t, http.StatusCreated)
+	defer server.Close()
+
+	sink := NewClockifySink()
+	sink.Configure(map[string]interface{}{"api_key": "k", "workspace_id": "w", "base_url": server.URL})
+
+	exportLog, err := cache.New(filepath.Join(t.TempDir(), "cache.db"))
+	if err != nil {
+		t.Fatalf("Failed to open cache: %v", err)
+	}
+	defer exportLog.Close()
+
+	mapping := NewMapping([]config.ExportProjectConfig{{Project: "ACME API", ID: "p-api"}})
+	entries := []TimeEntry{testEntry()}
+
+	results := Export(context.Background(), sink, entries, mapping, exportLog, false)
+	if results[0].Status != StatusCreated || len(*requests) != 1 {
+		t.Fatalf("Expected the entry to be created, got %+v with %d requests", results[0], len(*requests))
+	}
+
+	// Exporting the same days again creates nothing
+	for _, dryRun := range []bool{true, false} {
+		results = Export(context.Background(), sink, entries, mapping, exportLog, dryRun)
+		if results[0].Status != StatusExists {
+			t.Errorf("Expected the entry to exist (dry run %v), got %s", dryRun, results[0].Status)
+		}
+	}
+	if len(*requests) != 1 {
+		t.Errorf("Expected no additional entries, got %d requests", len(*requests))
+	}
+
+	// The same block exported to another remote project is new
+	other := NewMapping([]config.ExportProjectConfig{{ID: "p-other"}})
+	if results = Export(context.Background(), sink, entries, other, exportLog, true); results[0].Status != StatusNew {
+		t.Errorf("Expected the entry to be new for another project, got %s", results[0].Status)
+	}
+
+	// A block that moved keeps its time: nothing is new
+	moved := testEntry()
+	moved.Start, moved.End = moved.Start.Add(-10*time.Minute), moved.End.Add(-10*time.Minute)
+	results = Export(context.Background(), sink, []TimeEntry{moved}, mapping, exportLog, false)
+	if results[0].Status != StatusExists || len(*requests) != 1 {
+		t.Errorf("Expected a moved block to exist, got %+v with %d requests", results[0], len(*requests))
+	}
+
+	// A block that grew, or a new block the same day, only sends the new time
+	grown := testEntry()
+	grown.End = grown.End.Add(30 * time.Minute)
+	later := testEntry()
+	later.Start, later.End = later.Start.Add(4*time.Hour), later.End.Add(4*time.Hour)
+	results = Export(context.Background(), sink, []TimeEntry{grown, later}, mapping, exportLog, false)
+	if results[0].Status != StatusCreated || results[0].Existing != 90*time.Minute || results[0].Entry.Duration() != 30*time.Minute {
+		t.Errorf("Expected 30 minutes of the grown block to be created, got %+v", results[0])
+	}
+	if !results[0].Entry.Start.Equal(grown.Start.Add(90 * time.Minute)) {
+		t.Errorf("Expected the new time to start after the exported part, got %v", results[0].Entry.Start)
+	}
+	if results[1].Status != StatusCreated || results[1].Existing != 0 || results[1].Entry.Duration() != 90*time.Minute {
+		t.Errorf("Expected the later block to be created in full, got %+v", results[1])
+	}
+	if len(*requests) != 3 {
+		t.Errorf("Expected 3 requests, got %d", len(*requests))
+	}
+
+	results = Export(context.Background(), sink, []TimeEntry{grown, later}, mapping, exportLog, false)
+	if results[0].Status != StatusExists || results[1].Status != StatusExists || len(*requests) != 3 {
+		t.Errorf("Expected the day to be exported completely, got %+v with %d requests", results, len(*requests))
+	}
+}
+
+func TestEntriesFromBlocks(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 1, 15, hour, minute, 0, 0, time.Local)
+	}
+	activities := []timeline.Activity{
+		{Type: timeline.ActivityTypeGitCommit, Title: "Fix login redirect", Timestamp: at(9, 30),
+			Metadata: map[string]string{"project": "ACME API", "client": "Acme Corp", "billable": "true"}},
+		{Type: timeline.ActivityTypeGitCommit, Title: "Add tests", Timestamp: at(10, 2),
+			Metadata: map[string]string{"project": "ACME API", "client": "Acme Corp", "billable": "true"}},
+		{Type: timeline.ActivityTypeGitCommit, Title: "Update docs", Timestamp: at(15, 0),
+			Metadata: map[string]string{"project": "Internal"}},
+	}
+
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	opts := timesheet.Options{Increment: 15 * time.Minute, MaxGap: 30 * time.Minute, PointDuration: 15 * time.Minute}
+	ts := timesheet.Build(activities, []time.Time{day}, opts)
+	entries := EntriesFromBlocks(ts, activities, 15*time.Minute)
+
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d: %+v", len(entries), entries)
+	}
+
+	acme := entries[0]
+	// 09:15 to 10:02 rounds to 45 minutes
+	if !acme.Start.Equal(at(9, 15)) || acme.Duration() != 45*time.Minute {
+		t.Errorf("Unexpected ACME entry span %v - %v", acme.Start, acme.End)
+	}
+	if acme.Client != "Acme Corp" || !acme.Billable || acme.Description != "Fix login redirect; Add tests" {
+		t.Errorf("Unexpected ACME entry: %+v", acme)
+	}
+	if entries[1].Project != "Internal" || entries[1].Billable {
+		t.Errorf("Unexpected second entry: %+v", entries[1])
+	}
+}
@@ -0,0 +1,75 @@
+package exporters
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// harvestBaseURL is the Harvest API root
+const harvestBaseURL = "https://api.harvestapp.com/v2/"
+
+// HarvestSink exports time entries to Harvest. Project references are
+// "projectID:taskID", as Harvest books time on a task of a project.
+type HarvestSink struct {
+	config     map[string]interface{}
+	httpClient *http.Client
+}
+
+// NewHarvestSink creates a new Harvest sink
+func NewHarvestSink() *HarvestSink {
+	return &HarvestSink{httpClient: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Name returns the sink name
+func (h *HarvestSink) Name() string {
+	return "harvest"
+}
+
+// Configure requires access_token and account_id; base_url is optional
+func (h *HarvestSink) Configure(config map[string]interface{}) error {
+	if err := requireConfig(config, "access_token", "account_id"); err != nil {
+		return err
+	}
+	h.config = config
+	return nil
+}
+
+// CreateEntry creates a time entry with the entry's hours on its day.
+// Harvest accounts tracking time via start and end times derive them from
+// the hours.
+func (h *HarvestSink) CreateEntry(ctx context.Context, entry TimeEntry, projectRef string) error {
+	project, task := splitRef(projectRef)
+	projectID, err := strconv.ParseInt(project, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid Harvest project ID %q", project)
+	}
+	taskID, err := strconv.ParseInt(task, 10, 64)
+	if err != nil {
+		return fmt.Errorf("harvest project reference %q must be \"projectID:taskID\"", projectRef)
+	}
+
+	baseURL := configString(h.config, "base_url")
+	if baseURL == "" {
+		baseURL = harvestBaseURL
+	}
+	apiURL := strings.TrimSuffix(baseURL, "/") + "/time_entries"
+
+	body := map[string]interface{}{
+		"project_id": projectID,
+		"task_id":    taskID,
+		"spent_date": entry.Start.In(time.Local).Format("2006-01-02"),
+		"hours":      math.Round(entry.Duration().Hours()*100) / 100,
+		"notes":      entry.Description,
+	}
+
+	headers := map[string]string{
+		"Authorization":      "Bearer " + configString(h.config, "access_token"),
+		"Harvest-Account-Id": configString(h.config, "account_id"),
+	}
+	return postJSON(ctx, h.httpClient, apiURL, headers, body)
+}
@@ -0,0 +1,46 @@
+package exporters
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestHarvestSink_CreateEntry(t *testing.T) {
+	server, requests := captureServer(t, http.StatusCreated)
+	defer server.Close()
+
+	sink := NewHarvestSink()
+	if err := sink.Configure(map[string]interface{}{"access_token": "secret", "account_id": "987", "base_url": server.URL}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	entry := testEntry()
+	entry.Start = time.Date(2024, 1, 15, 9, 0, 0, 0, time.Local)
+	entry.End = entry.Start.Add(100 * time.Minute)
+
+	if err := sink.CreateEntry(context.Background(), entry, "111:222"); err != nil {
+		t.Fatalf("CreateEntry failed: %v", err)
+	}
+	if err := sink.CreateEntry(context.Background(), entry, "111"); err == nil {
+		t.Error("Expected an error for a reference without task ID")
+	}
+
+	req := (*requests)[0]
+	if req.Path != "/time_entries" {
+		t.Errorf("Unexpected path %s", req.Path)
+	}
+	if req.Header.Get("Authorization") != "Bearer secret" || req.Header.Get("Harvest-Account-Id") != "987" {
+		t.Errorf("Unexpected auth headers: %v", req.Header)
+	}
+	if req.Header.Get("User-Agent") == "" {
+		t.Error("Harvest requires a User-Agent header")
+	}
+	if req.Body["project_id"] != float64(111) || req.Body["task_id"] != float64(222) {
+		t.Errorf("Unexpected IDs: %v", req.Body)
+	}
+	if req.Body["spent_date"] != "2024-01-15" || req.Body["hours"] != 1.67 || req.Body["notes"] != "Fix login redirect" {
+		t.Errorf("Unexpected body: %v", req.Body)
+	}
+}
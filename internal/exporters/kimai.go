@@ -0,0 +1,69 @@
+package exporters
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// KimaiSink exports time entries to a Kimai server. Project references are
+// "projectID:activityID", as Kimai books time on an activity of a project.
+type KimaiSink struct {
+	config     map[string]interface{}
+	httpClient *http.Client
+}
+
+// NewKimaiSink creates a new Kimai sink
+func NewKimaiSink() *KimaiSink {
+	return &KimaiSink{httpClient: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Name returns the sink name
+func (k *KimaiSink) Name() string {
+	return "kimai"
+}
+
+// Configure requires base_url and an API token. With username set the
+// token is sent as a legacy API password (X-AUTH-USER/X-AUTH-TOKEN),
+// otherwise as a bearer token.
+func (k *KimaiSink) Configure(config map[string]interface{}) error {
+	if err := requireConfig(config, "base_url", "token"); err != nil {
+		return err
+	}
+	k.config = config
+	return nil
+}
+
+// CreateEntry creates a timesheet record. Kimai expects local times.
+func (k *KimaiSink) CreateEntry(ctx context.Context, entry TimeEntry, projectRef string) error {
+	project, activity := splitRef(projectRef)
+	projectID, err := strconv.ParseInt(project, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid Kimai project ID %q", project)
+	}
+	activityID, err := strconv.ParseInt(activity, 10, 64)
+	if err != nil {
+		return fmt.Errorf("kimai project reference %q must be \"projectID:activityID\"", projectRef)
+	}
+
+	apiURL := strings.TrimSuffix(configString(k.config, "base_url"), "/") + "/api/timesheets"
+
+	body := map[string]interface{}{
+		"begin":       entry.Start.In(time.Local).Format("2006-01-02T15:04:05"),
+		"end":         entry.End.In(time.Local).Format("2006-01-02T15:04:05"),
+		"project":     projectID,
+		"activity":    activityID,
+		"description": entry.Description,
+		"billable":    entry.Billable,
+	}
+
+	token := configString(k.config, "token")
+	headers := map[string]string{"Authorization": "Bearer " + token}
+	if username := configString(k.config, "username"); username != "" {
+		headers = map[string]string{"X-AUTH-USER": username, "X-AUTH-TOKEN": token}
+	}
+	return postJSON(ctx, k.httpClient, apiURL, headers, body)
+}
@@ -0,0 +1,57 @@
+package exporters
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestKimaiSink_CreateEntry(t *testing.T) {
+	server, requests := captureServer(t, http.StatusOK)
+	defer server.Close()
+
+	sink := NewKimaiSink()
+	if err := sink.Configure(map[string]interface{}{"base_url": server.URL + "/", "token": "secret"}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	entry := testEntry()
+	entry.Start = time.Date(2024, 1, 15, 9, 0, 0, 0, time.Local)
+	entry.End = entry.Start.Add(90 * time.Minute)
+
+	if err := sink.CreateEntry(context.Background(), entry, "5:7"); err != nil {
+		t.Fatalf("CreateEntry failed: %v", err)
+	}
+
+	req := (*requests)[0]
+	if req.Path != "/api/timesheets" || req.Header.Get("Authorization") != "Bearer secret" {
+		t.Errorf("Unexpected request %s with auth %q", req.Path, req.Header.Get("Authorization"))
+	}
+	if req.Body["begin"] != "2024-01-15T09:00:00" || req.Body["end"] != "2024-01-15T10:30:00" {
+		t.Errorf("Expected local times, got %v - %v", req.Body["begin"], req.Body["end"])
+	}
+	if req.Body["project"] != float64(5) || req.Body["activity"] != float64(7) {
+		t.Errorf("Unexpected body: %v", req.Body)
+	}
+}
+
+func TestKimaiSink_LegacyAuth(t *testing.T) {
+	server, requests := captureServer(t, http.StatusOK)
+	defer server.Close()
+
+	sink := NewKimaiSink()
+	sink.Configure(map[string]interface{}{"base_url": server.URL, "token": "api-password", "username": "jdoe"})
+
+	if err := sink.CreateEntry(context.Background(), testEntry(), "5:7"); err != nil {
+		t.Fatalf("CreateEntry failed: %v", err)
+	}
+
+	req := (*requests)[0]
+	if req.Header.Get("X-AUTH-USER") != "jdoe" || req.Header.Get("X-AUTH-TOKEN") != "api-password" {
+		t.Errorf("Expected legacy auth headers, got %v", req.Header)
+	}
+	if req.Header.Get("Authorization") != "" {
+		t.Error("Expected no bearer token with legacy auth")
+	}
+}
@@ -0,0 +1,74 @@
+package exporters
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// togglBaseURL is the Toggl Track API root
+const togglBaseURL = "https://api.track.toggl.com/api/v9/"
+
+// TogglSink exports time entries to Toggl Track. Project references are
+// numeric Toggl project IDs.
+type TogglSink struct {
+	config     map[string]interface{}
+	httpClient *http.Client
+}
+
+// NewTogglSink creates a new Toggl Track sink
+func NewTogglSink() *TogglSink {
+	return &TogglSink{httpClient: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Name returns the sink name
+func (t *TogglSink) Name() string {
+	return "toggl"
+}
+
+// Configure requires api_token and a numeric workspace_id; base_url is optional
+func (t *TogglSink) Configure(config map[string]interface{}) error {
+	if err := requireConfig(config, "api_token", "workspace_id"); err != nil {
+		return err
+	}
+	if _, err := strconv.ParseInt(configString(config, "workspace_id"), 10, 64); err != nil {
+		return fmt.Errorf("workspace_id must be numeric")
+	}
+	t.config = config
+	return nil
+}
+
+// CreateEntry creates a time entry in the workspace
+func (t *TogglSink) CreateEntry(ctx context.Context, entry TimeEntry, projectRef string) error {
+	workspaceID, _ := strconv.ParseInt(configString(t.config, "workspace_id"), 10, 64)
+	projectID, err := strconv.ParseInt(strings.TrimSpace(projectRef), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid Toggl project ID %q", projectRef)
+	}
+
+	baseURL := configString(t.config, "base_url")
+	if baseURL == "" {
+		baseURL = togglBaseURL
+	}
+	apiURL := fmt.Sprintf("%s/workspaces/%d/time_entries", strings.TrimSuffix(baseURL, "/"), workspaceID)
+
+	body := map[string]interface{}{
+		"created_with": "arkeo",
+		"workspace_id": workspaceID,
+		"project_id":   projectID,
+		"description":  entry.Description,
+		"start":        entry.Start.UTC().Format(time.RFC3339),
+		"stop":         entry.End.UTC().Format(time.RFC3339),
+		"duration":     int64(entry.Duration().Seconds()),
+		"billable":     entry.Billable,
+	}
+
+	// The API token is the user name, with "api_token" as password
+	credentials := base64.StdEncoding.EncodeToString([]byte(configString(t.config, "api_token") + ":api_token"))
+	headers := map[string]string{"Authorization": "Basic " + credentials}
+	return postJSON(ctx, t.httpClient, apiURL, headers, body)
+}
@@ -0,0 +1,45 @@
+package exporters
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestTogglSink_Configure(t *testing.T) {
+	sink := NewTogglSink()
+	if err := sink.Configure(map[string]interface{}{"api_token": "t", "workspace_id": "acme"}); err == nil {
+		t.Error("Expected an error for a non-numeric workspace_id")
+	}
+}
+
+func TestTogglSink_CreateEntry(t *testing.T) {
+	server, requests := captureServer(t, http.StatusOK)
+	defer server.Close()
+
+	sink := NewTogglSink()
+	if err := sink.Configure(map[string]interface{}{"api_token": "secret", "workspace_id": 42, "base_url": server.URL}); err != nil {
+		t.Fatalf("Configure failed: %v", err)
+	}
+
+	if err := sink.CreateEntry(context.Background(), testEntry(), "1234"); err != nil {
+		t.Fatalf("CreateEntry failed: %v", err)
+	}
+	if err := sink.CreateEntry(context.Background(), testEntry(), "acme"); err == nil {
+		t.Error("Expected an error for a non-numeric project ID")
+	}
+
+	req := (*requests)[0]
+	if req.Path != "/workspaces/42/time_entries" {
+		t.Errorf("Unexpected path %s", req.Path)
+	}
+	if user, pass, ok := (&http.Request{Header: req.Header}).BasicAuth(); !ok || user != "secret" || pass != "api_token" {
+		t.Errorf("Expected basic auth with the API token, got %q/%q", user, pass)
+	}
+	if req.Body["project_id"] != float64(1234) || req.Body["workspace_id"] != float64(42) || req.Body["duration"] != float64(5400) {
+		t.Errorf("Unexpected body: %v", req.Body)
+	}
+	if req.Body["start"] != "2024-01-15T09:00:00Z" || req.Body["created_with"] != "arkeo" {
+		t.Errorf("Unexpected body: %v", req.Body)
+	}
+}
@@ -0,0 +1,312 @@
+// Package prefetch keeps the activity cache warm in the background, so that
+// opening the timeline for the previous working day doesn't have to wait for
+// every connector.
+package prefetch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/arkeo/arkeo/internal/cache"
+	"github.com/arkeo/arkeo/internal/config"
+	"github.com/arkeo/arkeo/internal/connectors"
+	"github.com/arkeo/arkeo/internal/utils"
+)
+
+// StatusFile is the name of the status file in the config directory
+const StatusFile = "prefetch-status.json"
+
+// Options controls what a prefetch run fetches and how fast
+type Options struct {
+	// Time between two runs
+	Interval time.Duration
+
+	// Days before the previous working day that are checked for gaps
+	BackfillDays int
+
+	// Most missing days fetched from one connector in a run (0 = no limit)
+	MaxDaysPerRun int
+
+	// Pause between two requests to the same connector
+	Delay time.Duration
+
+	// Per-connector pauses overriding Delay
+	ConnectorDelays map[string]time.Duration
+}
+
+// OptionsFromConfig converts the prefetch section of the config file
+func OptionsFromConfig(cfg config.PrefetchConfig) Options {
+	opts := Options{
+		Interval:        time.Duration(cfg.IntervalMinutes) * time.Minute,
+		BackfillDays:    cfg.BackfillDays,
+		MaxDaysPerRun:   cfg.MaxDaysPerRun,
+		Delay:           time.Duration(cfg.DelaySeconds) * time.Second,
+		ConnectorDelays: make(map[string]time.Duration, len(cfg.ConnectorDelays)),
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = time.Hour
+	}
+	for name, seconds := range cfg.ConnectorDelays {
+		opts.ConnectorDelays[name] = time.Duration(seconds) * time.Second
+	}
+	return opts
+}
+
+// Status describes the last prefetch run
+type Status struct {
+	Running    bool      `json:"running"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	NextRun    time.Time `json:"next_run"`
+
+	// Connector days fetched and activities stored by the run
+	Days       int `json:"days"`
+	Activities int `json:"activities"`
+
+	Failures []Failure `json:"failures,omitempty"`
+}
+
+// Failure is a connector that couldn't be fetched for a day
+type Failure struct {
+	Connector string `json:"connector"`
+	Date      string `json:"date"`
+	Error     string `json:"error"`
+}
+
+// Prefetcher fetches the previous working day and the days missing from
+// the cache on a schedule
+type Prefetcher struct {
+	cache      *cache.Cache
+	connectors func() map[string]connectors.Connector
+	opts       Options
+	statusPath string
+	now        func() time.Time
+
+	mu     sync.Mutex
+	status Status
+}
+
+// New creates a prefetcher storing into activityCache. enabledConnectors
+// is called at the start of every run to get the connectors to fetch. The
+// status of each run is written to statusPath when it is set.
+func New(activityCache *cache.Cache, enabledConnectors func() map[string]connectors.Connector, opts Options, statusPath string) *Prefetcher {
+	return &Prefetcher{
+		cache:      activityCache,
+		connectors: enabledConnectors,
+		opts:       opts,
+		statusPath: statusPath,
+		now:        time.Now,
+	}
+}
+
+// Run prefetches right away and then every interval until ctx is done
+func (p *Prefetcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		p.RunOnce(ctx)
+
+		p.mu.Lock()
+		p.status.NextRun = p.now().Add(p.opts.Interval)
+		status := p.status
+		p.mu.Unlock()
+		p.saveStatus(status)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce refreshes the previous working day and backfills missing days
+// for every enabled connector. Connectors run in parallel; the requests to
+// one connector are made one after the other, with the configured delay
+// in between.
+func (p *Prefetcher) RunOnce(ctx context.Context) Status {
+	p.mu.Lock()
+	p.status = Status{Running: true, StartedAt: p.now()}
+	status := p.status
+	p.mu.Unlock()
+	p.saveStatus(status)
+
+	enabled := p.connectors()
+	if p.cache != nil {
+		p.cache.SetOutputVersions(connectors.OutputVersions(enabled))
+	}
+	names := make([]string, 0, len(enabled))
+	for name := range enabled {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var wg sync.WaitGroup
+	for _, name := range names {
+		wg.Add(1)
+		go func(name string, conn connectors.Connector) {
+			defer wg.Done()
+			p.prefetchConnector(ctx, name, conn)
+		}(name, enabled[name])
+	}
+	wg.Wait()
+
+	p.mu.Lock()
+	p.status.Running = false
+	p.status.FinishedAt = p.now()
+	status = p.status
+	p.mu.Unlock()
+	p.saveStatus(status)
+
+	log.Printf("Prefetch: fetched %d connector day(s), %d activities, %d failure(s)",
+		status.Days, status.Activities, len(status.Failures))
+	return status
+}
+
+// Status returns the status of the current or last run
+func (p *Prefetcher) Status() Status {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.status
+}
+
+// prefetchConnector fetches the days one connector needs. A connector that
+// supports range requests gets a single request for all of them.
+func (p *Prefetcher) prefetchConnector(ctx context.Context, name string, conn connectors.Connector) {
+	days := p.daysToFetch(name)
+	if len(days) == 0 {
+		return
+	}
+
+	var batches [][]time.Time
+	if _, ok := conn.(connectors.RangeConnector); ok {
+		batches = [][]time.Time{days}
+	} else {
+		for _, day := range days {
+			batches = append(batches, []time.Time{day})
+		}
+	}
+
+	delay := p.opts.Delay
+	if d, ok := p.opts.ConnectorDelays[name]; ok {
+		delay = d
+	}
+
+	executor := utils.NewParallelExecutor()
+	for i, batch := range batches {
+		if i > 0 && delay > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		results := executor.FetchActivitiesRangeParallel(ctx, map[string]utils.Connector{name: conn}, batch)
+		for _, result := range results {
+			for _, day := range result.Days {
+				if day.Error == nil && p.cache != nil {
+					day.Error = p.cache.StoreDay(day.Date, name, day.Activities)
+				}
+				p.record(name, day)
+			}
+		}
+	}
+}
+
+// daysToFetch returns the previous working day, which is always refreshed,
+// followed by the days of the backfill window that the connector has no
+// cache entry for, newest first
+func (p *Prefetcher) daysToFetch(name string) []time.Time {
+	previous := PreviousWorkingDay(p.now())
+	days := []time.Time{previous}
+
+	missing := 0
+	for i := 1; i <= p.opts.BackfillDays; i++ {
+		if p.opts.MaxDaysPerRun > 0 && missing >= p.opts.MaxDaysPerRun {
+			break
+		}
+		day := previous.AddDate(0, 0, -i)
+		if p.cache != nil && p.cache.HasDay(day, []string{name}) {
+			continue
+		}
+		days = append(days, day)
+		missing++
+	}
+	return days
+}
+
+// record adds the outcome of fetching one connector day to the status
+func (p *Prefetcher) record(name string, day utils.DayActivities) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if day.Error != nil {
+		log.Printf("Prefetch: %s %s: %v", name, day.Date.Format("2006-01-02"), day.Error)
+		p.status.Failures = append(p.status.Failures, Failure{
+			Connector: name,
+			Date:      day.Date.Format("2006-01-02"),
+			Error:     day.Error.Error(),
+		})
+		return
+	}
+	p.status.Days++
+	p.status.Activities += len(day.Activities)
+}
+
+// saveStatus writes the status file, replacing it atomically so readers
+// never see a partial file
+func (p *Prefetcher) saveStatus(status Status) {
+	if p.statusPath == "" {
+		return
+	}
+
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return
+	}
+	tmp := p.statusPath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		log.Printf("Prefetch: could not write status: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, p.statusPath); err != nil {
+		log.Printf("Prefetch: could not write status: %v", err)
+	}
+}
+
+// LoadStatus reads the status written by a prefetcher, possibly running
+// in another process
+func LoadStatus(path string) (Status, error) {
+	var status Status
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return status, err
+	}
+	if err := json.Unmarshal(data, &status); err != nil {
+		return status, fmt.Errorf("invalid status file %s: %w", filepath.Base(path), err)
+	}
+	return status, nil
+}
+
+// PreviousWorkingDay returns the last Monday to Friday before now's local
+// date, as a date at midnight UTC like the dates parsed from the command line
+func PreviousWorkingDay(now time.Time) time.Time {
+	local := now.In(time.Local)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, -1)
+	for day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day
+}
@@ -0,0 +1,133 @@
+package prefetch
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/arkeo/arkeo/internal/cache"
+	"github.com/arkeo/arkeo/internal/connectors"
+	"github.com/arkeo/arkeo/internal/timeline"
+)
+
+// fakeConnector returns one activity per day and fails on the days in fail
+type fakeConnector struct {
+	*connectors.BaseConnector
+	fail map[string]bool
+
+	mu    sync.Mutex
+	calls []string
+}
+
+func newFakeConnector(name string, fail ...string) *fakeConnector {
+	f := &fakeConnector{BaseConnector: connectors.NewBaseConnector(name, "Fake"), fail: make(map[string]bool)}
+	for _, day := range fail {
+		f.fail[day] = true
+	}
+	return f
+}
+
+func (f *fakeConnector) GetActivities(ctx context.Context, date time.Time) ([]timeline.Activity, error) {
+	day := date.Format("2006-01-02")
+	f.mu.Lock()
+	f.calls = append(f.calls, day)
+	f.mu.Unlock()
+
+	if f.fail[day] {
+		return nil, errors.New("rate limited")
+	}
+	return []timeline.Activity{{
+		ID:        f.Name() + "-" + day,
+		Title:     "Work on " + day,
+		Source:    f.Name(),
+		Timestamp: time.Date(date.Year(), date.Month(), date.Day(), 10, 0, 0, 0, time.Local),
+	}}, nil
+}
+
+func (f *fakeConnector) TestConnection(ctx context.Context) error {
+	return nil
+}
+
+func TestPreviousWorkingDay(t *testing.T) {
+	tests := []struct {
+		now      time.Time
+		expected string
+	}{
+		{time.Date(2024, 1, 15, 9, 0, 0, 0, time.Local), "2024-01-12"}, // Monday
+		{time.Date(2024, 1, 16, 9, 0, 0, 0, time.Local), "2024-01-15"}, // Tuesday
+		{time.Date(2024, 1, 14, 9, 0, 0, 0, time.Local), "2024-01-12"}, // Sunday
+	}
+
+	for _, tt := range tests {
+		if got := PreviousWorkingDay(tt.now).Format("2006-01-02"); got != tt.expected {
+			t.Errorf("PreviousWorkingDay(%s) = %s, expected %s", tt.now.Format("Mon 2006-01-02"), got, tt.expected)
+		}
+	}
+}
+
+func TestPrefetcher_RunOnce(t *testing.T) {
+	dir := t.TempDir()
+	activityCache, err := cache.New(filepath.Join(dir, "cache.db"))
+	if err != nil {
+		t.Fatalf("Failed to open cache: %v", err)
+	}
+	defer activityCache.Close()
+
+	// The 11th is already cached and must not be fetched again
+	cachedDay := time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC)
+	if err := activityCache.StoreDay(cachedDay, "fake", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	fake := newFakeConnector("fake", "2024-01-09")
+	statusPath := filepath.Join(dir, StatusFile)
+	p := New(activityCache, func() map[string]connectors.Connector {
+		return map[string]connectors.Connector{"fake": fake}
+	}, Options{Interval: time.Hour, BackfillDays: 5, MaxDaysPerRun: 3}, statusPath)
+	p.now = func() time.Time { return time.Date(2024, 1, 15, 9, 0, 0, 0, time.Local) }
+
+	status := p.RunOnce(context.Background())
+
+	expected := []string{"2024-01-12", "2024-01-10", "2024-01-09", "2024-01-08"}
+	if len(fake.calls) != len(expected) {
+		t.Fatalf("Expected calls for %v, got %v", expected, fake.calls)
+	}
+	for i, day := range expected {
+		if fake.calls[i] != day {
+			t.Errorf("Call %d: expected %s, got %s", i, day, fake.calls[i])
+		}
+	}
+
+	if status.Running || status.Days != 3 || status.Activities != 3 {
+		t.Errorf("Unexpected status: %+v", status)
+	}
+	if len(status.Failures) != 1 || status.Failures[0].Date != "2024-01-09" || status.Failures[0].Connector != "fake" {
+		t.Errorf("Expected the failure on the 9th to be recorded, got %+v", status.Failures)
+	}
+
+	if !activityCache.HasDay(time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC), []string{"fake"}) {
+		t.Error("Expected the previous working day to be cached")
+	}
+	if activityCache.HasDay(time.Date(2024, 1, 9, 0, 0, 0, 0, time.UTC), []string{"fake"}) {
+		t.Error("Expected the failed day not to be cached")
+	}
+
+	saved, err := LoadStatus(statusPath)
+	if err != nil {
+		t.Fatalf("LoadStatus failed: %v", err)
+	}
+	if saved.FinishedAt.IsZero() || saved.Days != 3 || len(saved.Failures) != 1 {
+		t.Errorf("Unexpected saved status: %+v", saved)
+	}
+
+	// The next run only refreshes the previous working day and retries the
+	// failed day
+	fake.calls = nil
+	p.RunOnce(context.Background())
+	if len(fake.calls) != 3 || fake.calls[0] != "2024-01-12" || fake.calls[1] != "2024-01-09" {
+		t.Errorf("Expected 2024-01-12, 2024-01-09 and one older day, got %v", fake.calls)
+	}
+}
@@ -0,0 +1,206 @@
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/arkeo/arkeo/internal/config"
+	"github.com/arkeo/arkeo/internal/timeline"
+)
+
+// Metadata keys written by the rule engine
+const (
+	ProjectKey  = "project"
+	ClientKey   = "client"
+	BillableKey = "billable"
+	RuleKey     = "rule"
+)
+
+// Engine tags activities using the rules from the configuration
+type Engine struct {
+	rules []rule
+}
+
+// rule is a compiled RuleConfig
+type rule struct {
+	name       string
+	config     config.RuleConfig
+	conditions []condition
+}
+
+// condition matches one activity field against a pattern
+type condition struct {
+	field   string
+	pattern *regexp.Regexp
+}
+
+// New compiles the configured rules. It fails if any pattern is invalid.
+func New(configs []config.RuleConfig) (*Engine, error) {
+	engine := &Engine{}
+
+	for i, cfg := range configs {
+		name := cfg.Name
+		if name == "" {
+			name = fmt.Sprintf("rule %d", i+1)
+		}
+
+		fields := map[string]string{
+			"type":        cfg.Match.Type,
+			"source":      cfg.Match.Source,
+			"title":       cfg.Match.Title,
+			"description": cfg.Match.Description,
+			"url":         cfg.Match.URL,
+			"text":        cfg.Match.Text,
+		}
+		for key, value := range cfg.Match.Metadata {
+			fields["metadata."+key] = value
+		}
+
+		r := rule{name: name, config: cfg}
+		for field, value := range fields {
+			if value == "" {
+				continue
+			}
+			pattern, err := compilePattern(value)
+			if err != nil {
+				return nil, fmt.Errorf("rule %q: invalid %s pattern %q: %w", name, field, value, err)
+			}
+			r.conditions = append(r.conditions, condition{field: field, pattern: pattern})
+		}
+
+		// Keep conditions in a stable order for predictable descriptions
+		sort.Slice(r.conditions, func(a, b int) bool {
+			return r.conditions[a].field < r.conditions[b].field
+		})
+
+		engine.rules = append(engine.rules, r)
+	}
+
+	return engine, nil
+}
+
+// Len returns the number of rules
+func (e *Engine) Len() int {
+	return len(e.rules)
+}
+
+// Match returns the name of the first rule matching the activity
+func (e *Engine) Match(activity timeline.Activity) (string, bool) {
+	if r := e.match(activity); r != nil {
+		return r.name, true
+	}
+	return "", false
+}
+
+// Apply tags each activity with the project, client and billable flag of
+// the first matching rule, and records the rule name. It returns the number
+// of activities that matched a rule.
+func (e *Engine) Apply(activities []timeline.Activity) int {
+	matched := 0
+
+	for i := range activities {
+		r := e.match(activities[i])
+		if r == nil {
+			continue
+		}
+		matched++
+
+		// Copy the metadata so activities sharing a map aren't affected
+		metadata := make(map[string]string, len(activities[i].Metadata)+4)
+		for k, v := range activities[i].Metadata {
+			metadata[k] = v
+		}
+		if r.config.Project != "" {
+			metadata[ProjectKey] = r.config.Project
+		}
+		if r.config.Client != "" {
+			metadata[ClientKey] = r.config.Client
+		}
+		if r.config.Billable != nil {
+			metadata[BillableKey] = strconv.FormatBool(*r.config.Billable)
+		}
+		metadata[RuleKey] = r.name
+		activities[i].Metadata = metadata
+	}
+
+	return matched
+}
+
+// match returns the first rule whose conditions all hold for the activity
+func (e *Engine) match(activity timeline.Activity) *rule {
+	for i := range e.rules {
+		if e.rules[i].matches(activity) {
+			return &e.rules[i]
+		}
+	}
+	return nil
+}
+
+// matches reports whether all conditions of the rule hold. A rule without
+// conditions matches every activity.
+func (r *rule) matches(activity timeline.Activity) bool {
+	for _, c := range r.conditions {
+		if c.field == "text" {
+			if !c.pattern.MatchString(activity.Title) &&
+				!c.pattern.MatchString(activity.Description) &&
+				!c.pattern.MatchString(activity.URL) {
+				return false
+			}
+			continue
+		}
+
+		if !c.pattern.MatchString(fieldValue(activity, c.field)) {
+			return false
+		}
+	}
+	return true
+}
+
+// fieldValue returns the value of an activity field named as in RuleMatch
+func fieldValue(activity timeline.Activity, field string) string {
+	switch field {
+	case "type":
+		return string(activity.Type)
+	case "source":
+		return activity.Source
+	case "title":
+		return activity.Title
+	case "description":
+		return activity.Description
+	case "url":
+		return activity.URL
+	}
+
+	if key, ok := strings.CutPrefix(field, "metadata."); ok {
+		return activity.Metadata[key]
+	}
+	return ""
+}
+
+// compilePattern turns a rule value into a regular expression. Values
+// wrapped in slashes are regular expressions used as-is; anything else is
+// a case-insensitive glob where * matches any run of characters and ?
+// matches a single character.
+func compilePattern(value string) (*regexp.Regexp, error) {
+	if len(value) >= 2 && strings.HasPrefix(value, "/") && strings.HasSuffix(value, "/") {
+		return regexp.Compile(value[1 : len(value)-1])
+	}
+
+	var b strings.Builder
+	b.WriteString("(?is)^")
+	for _, r := range value {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
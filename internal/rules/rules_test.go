@@ -0,0 +1,188 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/arkeo/arkeo/internal/config"
+	"github.com/arkeo/arkeo/internal/timeline"
+)
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func testRules() []config.RuleConfig {
+	return []config.RuleConfig{
+		{
+			Name:     "acme-repos",
+			Match:    config.RuleMatch{Metadata: map[string]string{"repository": "acme/*"}},
+			Project:  "ACME API",
+			Client:   "Acme Corp",
+			Billable: boolPtr(true),
+		},
+		{
+			Name:    "acme-issues",
+			Match:   config.RuleMatch{Source: "youtrack", Metadata: map[string]string{"issue_key": "/^ACME-\\d+$/"}},
+			Project: "ACME API",
+			Client:  "Acme Corp",
+		},
+		{
+			Name:   "acme-meetings",
+			Match:  config.RuleMatch{Type: "calendar", Title: "*acme*"},
+			Client: "Acme Corp",
+		},
+		{
+			Name:     "acme-jira",
+			Match:    config.RuleMatch{Text: "*acme.atlassian.net*"},
+			Client:   "Acme Corp",
+			Billable: boolPtr(false),
+		},
+	}
+}
+
+func TestNew_InvalidPattern(t *testing.T) {
+	_, err := New([]config.RuleConfig{{Name: "bad", Match: config.RuleMatch{Title: "/([/"}}})
+	if err == nil {
+		t.Error("Expected error for invalid regular expression")
+	}
+}
+
+func TestEngine_Match(t *testing.T) {
+	engine, err := New(testRules())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		activity timeline.Activity
+		expected string
+	}{
+		{
+			name:     "repository glob",
+			activity: timeline.Activity{Source: "github", Metadata: map[string]string{"repository": "acme/api"}},
+			expected: "acme-repos",
+		},
+		{
+			name:     "glob does not match other owners",
+			activity: timeline.Activity{Source: "github", Metadata: map[string]string{"repository": "other/acme"}},
+			expected: "",
+		},
+		{
+			name:     "issue key regex",
+			activity: timeline.Activity{Source: "youtrack", Metadata: map[string]string{"issue_key": "ACME-123"}},
+			expected: "acme-issues",
+		},
+		{
+			name:     "all conditions must match",
+			activity: timeline.Activity{Source: "jira", Metadata: map[string]string{"issue_key": "ACME-123"}},
+			expected: "",
+		},
+		{
+			name:     "case-insensitive title glob",
+			activity: timeline.Activity{Type: timeline.ActivityTypeCalendar, Title: "ACME sync"},
+			expected: "acme-meetings",
+		},
+		{
+			name:     "text matches URL",
+			activity: timeline.Activity{Title: "Visited site", URL: "https://acme.atlassian.net"},
+			expected: "acme-jira",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, ok := engine.Match(tt.activity)
+			if tt.expected == "" {
+				if ok {
+					t.Errorf("Expected no match, got %s", name)
+				}
+				return
+			}
+			if name != tt.expected {
+				t.Errorf("Expected rule %s, got %q", tt.expected, name)
+			}
+		})
+	}
+}
+
+func TestEngine_Apply(t *testing.T) {
+	engine, err := New(testRules())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	shared := map[string]string{"repository": "acme/api"}
+	activities := []timeline.Activity{
+		{Source: "github", Metadata: shared},
+		{Type: timeline.ActivityTypeCalendar, Title: "ACME sync"},
+		{Source: "github", Title: "Unrelated"},
+	}
+
+	if matched := engine.Apply(activities); matched != 2 {
+		t.Errorf("Expected 2 matched activities, got %d", matched)
+	}
+
+	first := activities[0].Metadata
+	if first[ProjectKey] != "ACME API" || first[ClientKey] != "Acme Corp" || first[BillableKey] != "true" || first[RuleKey] != "acme-repos" {
+		t.Errorf("Unexpected metadata: %v", first)
+	}
+	if _, ok := shared[ProjectKey]; ok {
+		t.Error("Expected the original metadata map to be left untouched")
+	}
+
+	second := activities[1].Metadata
+	if second[ClientKey] != "Acme Corp" {
+		t.Errorf("Expected client to be set, got %v", second)
+	}
+	if _, ok := second[ProjectKey]; ok {
+		t.Error("Expected project to stay unset when the rule doesn't assign it")
+	}
+	if _, ok := second[BillableKey]; ok {
+		t.Error("Expected billable to stay unset when the rule doesn't assign it")
+	}
+
+	if activities[2].Metadata != nil {
+		t.Errorf("Expected unmatched activity to be left alone, got %v", activities[2].Metadata)
+	}
+}
+
+func TestEngine_EmptyMatchMatchesEverything(t *testing.T) {
+	engine, err := New([]config.RuleConfig{{Billable: boolPtr(false)}})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	name, ok := engine.Match(timeline.Activity{Title: "Anything"})
+	if !ok || name != "rule 1" {
+		t.Errorf("Expected catch-all rule to match as 'rule 1', got %q (%v)", name, ok)
+	}
+}
+
+func TestCompilePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		value   string
+		match   bool
+	}{
+		{"acme/*", "acme/api", true},
+		{"acme/*", "ACME/API", true},
+		{"acme/*", "xacme/api", false},
+		{"ACME-???", "ACME-123", true},
+		{"ACME-???", "ACME-1234", false},
+		{"a.b", "axb", false},
+		{"/^acme/", "acme-sync", true},
+		{"/^acme/", "ACME", false},
+		{"/(?i)^acme/", "ACME", true},
+	}
+
+	for _, tt := range tests {
+		re, err := compilePattern(tt.pattern)
+		if err != nil {
+			t.Fatalf("compilePattern(%q) failed: %v", tt.pattern, err)
+		}
+		if got := re.MatchString(tt.value); got != tt.match {
+			t.Errorf("Pattern %q on %q: expected %v, got %v", tt.pattern, tt.value, tt.match, got)
+		}
+	}
+}
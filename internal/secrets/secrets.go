@@ -0,0 +1,221 @@
+// Package secrets resolves references to secrets kept outside the config
+// file. A secret config value can be written as
+//
+//	env:GITLAB_TOKEN          the environment variable GITLAB_TOKEN
+//	cmd:pass show work/gitlab the first line printed by a shell command
+//	keyring:arkeo/gitlab      the OS keyring entry with service arkeo and account gitlab
+//
+// Any other value is the secret itself. References are resolved in memory
+// when connectors are configured; the config file keeps the reference.
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arkeo/arkeo/internal/connectors"
+)
+
+// Backends a secret can come from
+const (
+	BackendEnv     = "env"
+	BackendCmd     = "cmd"
+	BackendKeyring = "keyring"
+	BackendConfig  = "config"
+)
+
+// commandTimeout bounds password manager commands, which may wait for an
+// agent to unlock
+const commandTimeout = 30 * time.Second
+
+// defaultKeyringService is used for keyring references without a service
+const defaultKeyringService = "arkeo"
+
+var (
+	mu       sync.Mutex
+	resolved = make(map[string]string)
+)
+
+// Backend returns where a secret config value comes from: env, cmd or
+// keyring for references, config for a secret stored as is, and an empty
+// string when no value is set
+func Backend(value string) string {
+	if backend, _, ok := parseReference(value); ok {
+		return backend
+	}
+	if strings.TrimSpace(value) == "" {
+		return ""
+	}
+	return BackendConfig
+}
+
+// IsReference reports whether value refers to a secret stored elsewhere
+func IsReference(value string) bool {
+	_, _, ok := parseReference(value)
+	return ok
+}
+
+// RunsProgram reports whether resolving value runs a program on this
+// machine: a cmd: reference runs a shell command and a keyring: reference
+// the keyring tool. Such references can only come from the config file,
+// never from the web UI.
+func RunsProgram(value string) bool {
+	backend, _, ok := parseReference(value)
+	return ok && (backend == BackendCmd || backend == BackendKeyring)
+}
+
+// Resolve returns the secret a config value stands for. Values that aren't
+// references are returned unchanged. Results of commands and keyring
+// lookups are remembered for the lifetime of the process.
+func Resolve(ctx context.Context, value string) (string, error) {
+	backend, target, ok := parseReference(value)
+	if !ok {
+		return value, nil
+	}
+
+	if backend == BackendEnv {
+		secret, exists := os.LookupEnv(target)
+		if !exists || secret == "" {
+			return "", fmt.Errorf("environment variable %s is not set", target)
+		}
+		return secret, nil
+	}
+
+	mu.Lock()
+	secret, cached := resolved[value]
+	mu.Unlock()
+	if cached {
+		return secret, nil
+	}
+
+	var err error
+	switch backend {
+	case BackendCmd:
+		secret, err = runCommand(ctx, target)
+	case BackendKeyring:
+		secret, err = lookupKeyring(ctx, target)
+	}
+	if err != nil {
+		return "", err
+	}
+	if secret == "" {
+		return "", fmt.Errorf("%s:%s returned an empty secret", backend, target)
+	}
+
+	mu.Lock()
+	resolved[value] = secret
+	mu.Unlock()
+	return secret, nil
+}
+
+// ResolveConfig returns a copy of a connector config in which the values of
+// the secret fields are resolved. The config passed in is left untouched,
+// so the references are what gets saved.
+func ResolveConfig(ctx context.Context, config map[string]interface{}, fields []connectors.ConfigField) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(config))
+	for k, v := range config {
+		result[k] = v
+	}
+
+	for _, field := range fields {
+		if field.Type != "secret" {
+			continue
+		}
+		value, ok := result[field.Key].(string)
+		if !ok || !IsReference(value) {
+			continue
+		}
+		secret, err := Resolve(ctx, value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", field.Key, err)
+		}
+		result[field.Key] = secret
+	}
+
+	return result, nil
+}
+
+// parseReference splits a reference into its backend and target
+func parseReference(value string) (string, string, bool) {
+	prefix, target, ok := strings.Cut(strings.TrimSpace(value), ":")
+	if !ok {
+		return "", "", false
+	}
+	target = strings.TrimSpace(target)
+	switch prefix {
+	case BackendEnv, BackendCmd, BackendKeyring:
+		return prefix, target, target != ""
+	}
+	return "", "", false
+}
+
+// runCommand runs a shell command and returns the first line of its output,
+// which is where password managers like pass print the password
+func runCommand(ctx context.Context, command string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Stdin = os.Stdin // allow pinentry and similar prompts
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("cmd:%s failed: %w: %s", command, err, msg)
+		}
+		return "", fmt.Errorf("cmd:%s failed: %w", command, err)
+	}
+
+	line, _, _ := strings.Cut(stdout.String(), "\n")
+	return strings.TrimSpace(line), nil
+}
+
+// lookupKeyring reads a password from the OS keyring. The target is
+// service/account, or just account for the arkeo service. On macOS the
+// login keychain is used, elsewhere the Secret Service (GNOME Keyring,
+// KWallet) through secret-tool.
+func lookupKeyring(ctx context.Context, target string) (string, error) {
+	service, account, ok := strings.Cut(target, "/")
+	if !ok {
+		service, account = defaultKeyringService, target
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.CommandContext(ctx, "security", "find-generic-password", "-s", service, "-a", account, "-w")
+	case "windows":
+		return "", fmt.Errorf("keyring references are not supported on Windows, use cmd: instead")
+	default:
+		cmd = exec.CommandContext(ctx, "secret-tool", "lookup", "service", service, "username", account)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("keyring entry %s/%s not found: %s", service, account, msg)
+		}
+		return "", fmt.Errorf("keyring entry %s/%s not found: %w", service, account, err)
+	}
+
+	return strings.TrimRight(stdout.String(), "\r\n"), nil
+}
@@ -0,0 +1,110 @@
+package secrets
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/arkeo/arkeo/internal/connectors"
+)
+
+func TestBackend(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"env:GITLAB_TOKEN", BackendEnv},
+		{"cmd:pass show work/gitlab", BackendCmd},
+		{"keyring:arkeo/gitlab", BackendKeyring},
+		{"glpat-abcdef", BackendConfig},
+		{"https://example.com", BackendConfig},
+		{"env:", BackendConfig},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Backend(tt.value); got != tt.expected {
+			t.Errorf("Backend(%q) = %q, expected %q", tt.value, got, tt.expected)
+		}
+	}
+}
+
+func TestRunsProgram(t *testing.T) {
+	for value, expected := range map[string]bool{
+		"cmd:pass show work/gitlab": true,
+		" cmd: curl evil.example":   true,
+		"keyring:arkeo/gitlab":      true,
+		"env:GITLAB_TOKEN":          false,
+		"glpat-abcdef":              false,
+		"cmd:":                      false,
+	} {
+		if got := RunsProgram(value); got != expected {
+			t.Errorf("RunsProgram(%q) = %v, expected %v", value, got, expected)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	t.Setenv("ARKEO_TEST_TOKEN", "from-env")
+
+	secret, err := Resolve(context.Background(), "env:ARKEO_TEST_TOKEN")
+	if err != nil || secret != "from-env" {
+		t.Errorf("Expected from-env, got %q (%v)", secret, err)
+	}
+
+	if _, err := Resolve(context.Background(), "env:ARKEO_TEST_UNSET"); err == nil {
+		t.Error("Expected an error for an unset environment variable")
+	}
+
+	secret, err = Resolve(context.Background(), "plain-token")
+	if err != nil || secret != "plain-token" {
+		t.Errorf("Expected the plain value unchanged, got %q (%v)", secret, err)
+	}
+
+	if runtime.GOOS == "windows" {
+		return
+	}
+
+	// Like pass, only the first line is the password
+	secret, err = Resolve(context.Background(), "cmd:printf 'from-cmd\\nurl: example.com\\n'")
+	if err != nil || secret != "from-cmd" {
+		t.Errorf("Expected from-cmd, got %q (%v)", secret, err)
+	}
+
+	if _, err := Resolve(context.Background(), "cmd:echo locked >&2; exit 1"); err == nil {
+		t.Error("Expected an error for a failing command")
+	}
+}
+
+func TestResolveConfig(t *testing.T) {
+	t.Setenv("ARKEO_TEST_TOKEN", "from-env")
+
+	config := map[string]interface{}{
+		"base_url": "env:NOT_A_SECRET",
+		"token":    "env:ARKEO_TEST_TOKEN",
+		"timeout":  30,
+	}
+	fields := []connectors.ConfigField{
+		{Key: "base_url", Type: "string"},
+		{Key: "token", Type: "secret"},
+	}
+
+	resolved, err := ResolveConfig(context.Background(), config, fields)
+	if err != nil {
+		t.Fatalf("ResolveConfig failed: %v", err)
+	}
+	if resolved["token"] != "from-env" {
+		t.Errorf("Expected the token to be resolved, got %v", resolved["token"])
+	}
+	if resolved["base_url"] != "env:NOT_A_SECRET" {
+		t.Errorf("Expected fields that aren't secrets to be left alone, got %v", resolved["base_url"])
+	}
+	if config["token"] != "env:ARKEO_TEST_TOKEN" {
+		t.Errorf("Expected the original config to keep the reference, got %v", config["token"])
+	}
+
+	config["token"] = "env:ARKEO_TEST_UNSET"
+	if _, err := ResolveConfig(context.Background(), config, fields); err == nil {
+		t.Error("Expected an error for an unresolvable secret")
+	}
+}
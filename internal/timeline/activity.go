@@ -192,6 +192,16 @@ func (a *Activity) FormatDuration() string {
 	}
 
 	duration := *a.Duration
+
+	// All-day calendar events span whole days; rounding absorbs 23 and 25
+	// hour days around daylight saving changes
+	if a.Metadata["all_day"] == "true" {
+		if days := int((duration + 12*time.Hour) / (24 * time.Hour)); days > 1 {
+			return fmt.Sprintf("%d days", days)
+		}
+		return "all day"
+	}
+
 	if duration < time.Minute {
 		return fmt.Sprintf("%ds", int(duration.Seconds()))
 	} else if duration < time.Hour {
@@ -99,6 +99,17 @@ func TestActivity_FormatDuration(t *testing.T) {
 	}
 }
 
+func TestActivity_FormatDuration_AllDay(t *testing.T) {
+	allDay := map[string]string{"all_day": "true"}
+
+	if got := (&Activity{Duration: durationPtr(23 * time.Hour), Metadata: allDay}).FormatDuration(); got != "all day" {
+		t.Errorf("Expected %q, got %q", "all day", got)
+	}
+	if got := (&Activity{Duration: durationPtr(72 * time.Hour), Metadata: allDay}).FormatDuration(); got != "3 days" {
+		t.Errorf("Expected %q, got %q", "3 days", got)
+	}
+}
+
 func TestTimeline_AddActivity(t *testing.T) {
 	timeline := NewTimeline(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC))
 
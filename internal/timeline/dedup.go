@@ -0,0 +1,441 @@
+package timeline
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Signals used to recognise the same piece of work reported by two sources
+const (
+	DedupBySHA      = "sha"
+	DedupByIssueKey = "issue_key"
+	DedupByURL      = "url"
+	DedupByTitle    = "title"
+)
+
+// Metadata keys written when activities are merged
+const (
+	// MetadataSources lists every source that reported a merged activity
+	MetadataSources = "sources"
+
+	// MetadataMergedIDs lists the IDs of the activities merged into this one
+	MetadataMergedIDs = "merged_ids"
+)
+
+// nonAlnumRegex matches runs of characters ignored when comparing titles
+var nonAlnumRegex = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+
+// DedupPair overrides the deduplication settings for one pair of sources
+type DedupPair struct {
+	Sources  [2]string
+	Disabled bool
+	// Match lists the signals for this pair; nil uses DedupOptions.Match
+	Match []string
+	// Window overrides DedupOptions.Window when non-zero
+	Window time.Duration
+}
+
+// DedupOptions controls how duplicates from different sources are merged
+type DedupOptions struct {
+	// Window is the largest time difference between two duplicates
+	Window time.Duration
+
+	// TitleSimilarity is the minimum similarity (0-1) for titles to count
+	// as near-identical
+	TitleSimilarity float64
+
+	// Match lists the signals used for pairs without an override.
+	// DedupByIssueKey is ignored here: an activity merely mentioning an
+	// issue is not the issue's activity, so issue keys only merge the pairs
+	// that list them.
+	Match []string
+
+	// Pairs holds per source pair overrides
+	Pairs []DedupPair
+}
+
+// DefaultDedupOptions returns sensible defaults for deduplication
+func DefaultDedupOptions() DedupOptions {
+	return DedupOptions{
+		Window:          10 * time.Minute,
+		TitleSimilarity: 0.9,
+		Match:           []string{DedupBySHA, DedupByURL, DedupByTitle},
+	}
+}
+
+// dedupKeys holds the normalised identifiers of one activity
+type dedupKeys struct {
+	shas      []string
+	issueKeys []string
+	url       string
+	title     string
+}
+
+// Deduplicate merges activities from different sources that describe the
+// same piece of work: matching commit SHAs, issue keys, URLs or
+// near-identical titles within the configured time window. Each group keeps
+// its richest record, completed with the metadata of the others and the
+// list of contributing sources. It returns the remaining activities in
+// chronological order and the number of duplicates merged away.
+func Deduplicate(activities []Activity, opts DedupOptions) ([]Activity, int) {
+	if len(activities) < 2 {
+		return activities, 0
+	}
+
+	sorted := make([]Activity, len(activities))
+	copy(sorted, activities)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	maxWindow := opts.Window
+	for _, p := range opts.Pairs {
+		if p.Window > maxWindow {
+			maxWindow = p.Window
+		}
+	}
+
+	keys := make([]dedupKeys, len(sorted))
+	for i, a := range sorted {
+		keys[i] = extractDedupKeys(a)
+	}
+
+	// Union-find over activities that are duplicates of each other. Each
+	// group holds at most one activity per source: two activities from the
+	// same source are distinct work, even when both match a third.
+	parent := make([]int, len(sorted))
+	sources := make([]map[string]bool, len(sorted))
+	for i := range parent {
+		parent[i] = i
+		sources[i] = map[string]bool{sorted[i].Source: true}
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i := range sorted {
+		for j := i + 1; j < len(sorted); j++ {
+			gap := sorted[j].Timestamp.Sub(sorted[i].Timestamp)
+			if gap > maxWindow {
+				break
+			}
+			if sorted[i].Source == sorted[j].Source {
+				continue
+			}
+
+			match, window, ok := opts.pairSettings(sorted[i].Source, sorted[j].Source)
+			if !ok || gap > window {
+				continue
+			}
+			if !isDuplicate(sorted[i], sorted[j], keys[i], keys[j], match, opts.TitleSimilarity) {
+				continue
+			}
+			ri, rj := find(i), find(j)
+			if ri == rj || shareSource(sources[ri], sources[rj]) {
+				continue
+			}
+			parent[rj] = ri
+			for source := range sources[rj] {
+				sources[ri][source] = true
+			}
+		}
+	}
+
+	groups := make(map[int][]int)
+	var roots []int
+	for i := range sorted {
+		root := find(i)
+		if _, ok := groups[root]; !ok {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], i)
+	}
+
+	if len(roots) == len(sorted) {
+		return sorted, 0
+	}
+
+	result := make([]Activity, 0, len(roots))
+	for _, root := range roots {
+		members := groups[root]
+		if len(members) == 1 {
+			result = append(result, sorted[members[0]])
+			continue
+		}
+
+		group := make([]Activity, len(members))
+		for k, idx := range members {
+			group[k] = sorted[idx]
+		}
+		result = append(result, mergeActivities(group))
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Timestamp.Before(result[j].Timestamp)
+	})
+
+	return result, len(sorted) - len(result)
+}
+
+// shareSource reports whether two groups have a source in common
+func shareSource(a, b map[string]bool) bool {
+	for source := range a {
+		if b[source] {
+			return true
+		}
+	}
+	return false
+}
+
+// pairSettings returns the signals and window used for a pair of sources,
+// or false when deduplication is disabled for that pair. Issue keys are only
+// used when the pair's own signals include them.
+func (o DedupOptions) pairSettings(a, b string) ([]string, time.Duration, bool) {
+	defaultMatch := make([]string, 0, len(o.Match))
+	for _, signal := range o.Match {
+		if signal != DedupByIssueKey {
+			defaultMatch = append(defaultMatch, signal)
+		}
+	}
+
+	for _, p := range o.Pairs {
+		if (p.Sources[0] == a && p.Sources[1] == b) || (p.Sources[0] == b && p.Sources[1] == a) {
+			if p.Disabled {
+				return nil, 0, false
+			}
+			match := p.Match
+			if match == nil {
+				match = defaultMatch
+			}
+			window := p.Window
+			if window == 0 {
+				window = o.Window
+			}
+			return match, window, len(match) > 0
+		}
+	}
+	return defaultMatch, o.Window, len(defaultMatch) > 0
+}
+
+// isDuplicate reports whether any of the enabled signals match
+func isDuplicate(a, b Activity, ka, kb dedupKeys, match []string, similarity float64) bool {
+	for _, signal := range match {
+		switch signal {
+		case DedupBySHA:
+			if shasMatch(ka.shas, kb.shas) {
+				return true
+			}
+		case DedupByIssueKey:
+			// A commit mentioning an issue is related work, not the same
+			// event, so issue keys only merge non-commit activities
+			if a.Type != ActivityTypeGitCommit && b.Type != ActivityTypeGitCommit && anyEqual(ka.issueKeys, kb.issueKeys) {
+				return true
+			}
+		case DedupByURL:
+			if ka.url != "" && ka.url == kb.url {
+				return true
+			}
+		case DedupByTitle:
+			if len(ka.title) >= 8 && len(kb.title) >= 8 && titleSimilarity(ka.title, kb.title) >= similarity {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// extractDedupKeys collects the identifiers used to compare an activity
+func extractDedupKeys(a Activity) dedupKeys {
+	var k dedupKeys
+
+	for _, field := range []string{"sha", "commit_sha"} {
+		if sha := strings.ToLower(a.Metadata[field]); len(sha) >= 7 {
+			k.shas = append(k.shas, sha)
+		}
+	}
+	// GitLab push events carry the head commit of single-commit pushes
+	if a.Metadata["commit_count"] == "1" {
+		if sha := strings.ToLower(a.Metadata["commit_to"]); len(sha) >= 7 {
+			k.shas = append(k.shas, sha)
+		}
+	}
+
+	if key := strings.ToUpper(a.Metadata["issue_key"]); key != "" {
+		k.issueKeys = append(k.issueKeys, key)
+	}
+	for _, key := range IssueKeyPattern.FindAllString(a.Title, -1) {
+		if !contains(k.issueKeys, key) {
+			k.issueKeys = append(k.issueKeys, key)
+		}
+	}
+
+	k.url = normalizeURL(a.URL)
+	k.title = strings.TrimSpace(nonAlnumRegex.ReplaceAllString(strings.ToLower(a.Title), " "))
+
+	return k
+}
+
+// normalizeURL lowercases the host and drops the fragment and trailing slash
+// so that links to the same page compare equal
+func normalizeURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return strings.TrimSuffix(raw, "/")
+	}
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment = ""
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	// A bare domain says nothing about which piece of work it was
+	if u.Path == "" && u.RawQuery == "" {
+		return ""
+	}
+	return u.String()
+}
+
+// shasMatch reports whether two SHA lists share a commit, allowing
+// abbreviated SHAs to match their full form
+func shasMatch(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if strings.HasPrefix(x, y) || strings.HasPrefix(y, x) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func anyEqual(a, b []string) bool {
+	for _, x := range a {
+		if contains(b, x) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// titleSimilarity returns 1 minus the normalised edit distance between two titles
+func titleSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// levenshtein computes the edit distance between two rune slices
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// mergeActivities merges a group of duplicates into its richest record
+func mergeActivities(group []Activity) Activity {
+	best := 0
+	for i := 1; i < len(group); i++ {
+		if richness(group[i]) > richness(group[best]) {
+			best = i
+		}
+	}
+
+	merged := group[best]
+	metadata := make(map[string]string)
+	for k, v := range merged.Metadata {
+		metadata[k] = v
+	}
+
+	var sources, mergedIDs []string
+	for i, a := range group {
+		if !contains(sources, a.Source) {
+			sources = append(sources, a.Source)
+		}
+		if i == best {
+			continue
+		}
+		mergedIDs = append(mergedIDs, a.ID)
+
+		// Fill in whatever the richest record lacks
+		for k, v := range a.Metadata {
+			if _, ok := metadata[k]; !ok {
+				metadata[k] = v
+			}
+		}
+		if merged.Duration == nil && a.Duration != nil {
+			merged.Duration = a.Duration
+		}
+		if merged.URL == "" {
+			merged.URL = a.URL
+		}
+		if merged.Description == "" {
+			merged.Description = a.Description
+		}
+		// Keep the earliest report of the work
+		if a.Timestamp.Before(merged.Timestamp) {
+			merged.Timestamp = a.Timestamp
+		}
+	}
+
+	sort.Strings(sources)
+	metadata[MetadataSources] = strings.Join(sources, ",")
+	metadata[MetadataMergedIDs] = strings.Join(mergedIDs, ",")
+	merged.Metadata = metadata
+
+	return merged
+}
+
+// richness scores how much information an activity carries
+func richness(a Activity) int {
+	score := len(a.Metadata) * 2
+	if a.Duration != nil {
+		score += 3
+	}
+	if a.URL != "" {
+		score += 2
+	}
+	if a.Description != "" {
+		score += 2
+	}
+	score += len(a.Title) / 20
+	return score
+}
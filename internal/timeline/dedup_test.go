@@ -0,0 +1,172 @@
+package timeline
+
+import (
+	"testing"
+	"time"
+)
+
+func dedupTime(minute int) time.Time {
+	return time.Date(2024, 1, 15, 10, minute, 0, 0, time.UTC)
+}
+
+func TestDeduplicate_SHA(t *testing.T) {
+	activities := []Activity{
+		{ID: "gh-1", Source: "github", Type: ActivityTypeGitCommit, Title: "Commit: Fix login", Timestamp: dedupTime(0),
+			URL: "https://github.com/acme/api/commit/abc1234def", Metadata: map[string]string{"sha": "abc1234def5678", "repository": "acme/api"}},
+		{ID: "gl-1", Source: "gitlab", Type: ActivityTypeGitCommit, Title: "Pushed to main", Timestamp: dedupTime(2),
+			Metadata: map[string]string{"commit_to": "ABC1234DEF5678", "commit_count": "1"}},
+		{ID: "git-1", Source: "local_git", Type: ActivityTypeGitCommit, Title: "Committed on main", Timestamp: dedupTime(1),
+			Metadata: map[string]string{"sha": "abc1234", "branch": "main", "action": "commit", "path": "/src/api"}},
+	}
+
+	result, merged := Deduplicate(activities, DefaultDedupOptions())
+
+	if merged != 2 {
+		t.Fatalf("Expected 2 duplicates merged, got %d", merged)
+	}
+	if len(result) != 1 {
+		t.Fatalf("Expected 1 activity, got %d", len(result))
+	}
+
+	a := result[0]
+	if a.ID != "git-1" {
+		t.Errorf("Expected the richest record (git-1) to be kept, got %s", a.ID)
+	}
+	if a.Metadata[MetadataSources] != "github,gitlab,local_git" {
+		t.Errorf("Expected all sources recorded, got %q", a.Metadata[MetadataSources])
+	}
+	if a.Metadata["repository"] != "acme/api" {
+		t.Errorf("Expected metadata from other records to be merged in, got %v", a.Metadata)
+	}
+	if a.URL == "" {
+		t.Error("Expected URL to be filled from another record")
+	}
+	if !a.Timestamp.Equal(dedupTime(0)) {
+		t.Errorf("Expected the earliest timestamp, got %v", a.Timestamp)
+	}
+}
+
+func TestDeduplicate_IssueKeyAndTitle(t *testing.T) {
+	activities := []Activity{
+		{ID: "yt-1", Source: "youtrack", Type: ActivityTypeYouTrack, Title: "Updated ACME-12", Timestamp: dedupTime(0),
+			Metadata: map[string]string{"issue_key": "ACME-12"}},
+		{ID: "hook-1", Source: "webhooks", Type: ActivityTypeCustom, Title: "ACME-12 moved to Done", Timestamp: dedupTime(3)},
+		// Same issue key on a commit is related work, not a duplicate
+		{ID: "gh-1", Source: "github", Type: ActivityTypeGitCommit, Title: "Commit: ACME-12 fix", Timestamp: dedupTime(4)},
+		// A page about the issue is not the issue's activity
+		{ID: "b-1", Source: "browser_history", Type: ActivityTypeBrowser, Title: "ACME-12 Fix login - YouTrack", Timestamp: dedupTime(5)},
+		// Near-identical title from another source
+		{ID: "cal-1", Source: "calendar", Type: ActivityTypeCalendar, Title: "Sprint planning meeting", Timestamp: dedupTime(30)},
+		{ID: "hook-2", Source: "webhooks", Type: ActivityTypeCustom, Title: "Sprint planning meeting!", Timestamp: dedupTime(31)},
+	}
+
+	// Issue keys only merge the pairs that list them
+	opts := DefaultDedupOptions()
+	if _, merged := Deduplicate(activities, opts); merged != 1 {
+		t.Errorf("Expected only the title duplicate merged by default, got %d", merged)
+	}
+
+	opts.Pairs = []DedupPair{{Sources: [2]string{"youtrack", "webhooks"}, Match: []string{DedupByIssueKey, DedupByTitle}}}
+	result, merged := Deduplicate(activities, opts)
+
+	if merged != 2 {
+		t.Fatalf("Expected 2 duplicates merged, got %d", merged)
+	}
+	if len(result) != 4 {
+		t.Fatalf("Expected 4 activities, got %d", len(result))
+	}
+	if result[0].Metadata[MetadataSources] != "webhooks,youtrack" {
+		t.Errorf("Expected issue key duplicates merged, got sources %q", result[0].Metadata[MetadataSources])
+	}
+	if result[1].ID != "gh-1" || result[1].Metadata != nil {
+		t.Errorf("Expected commit to be left alone, got %+v", result[1])
+	}
+}
+
+func TestDeduplicate_SameSourceChain(t *testing.T) {
+	// Two changes on one issue, each re-reported by a webhook: two pieces
+	// of work, not one, even though the webhooks link them all
+	activities := []Activity{
+		{ID: "yt-1", Source: "youtrack", Type: ActivityTypeYouTrack, Title: "Updated ACME-1", Timestamp: dedupTime(0),
+			Metadata: map[string]string{"issue_key": "ACME-1"}},
+		{ID: "hook-1", Source: "webhooks", Type: ActivityTypeCustom, Title: "ACME-1 moved to Review", Timestamp: dedupTime(1)},
+		{ID: "yt-2", Source: "youtrack", Type: ActivityTypeYouTrack, Title: "Commented on ACME-1", Timestamp: dedupTime(5),
+			Metadata: map[string]string{"issue_key": "ACME-1"}},
+		{ID: "hook-2", Source: "webhooks", Type: ActivityTypeCustom, Title: "ACME-1 commented", Timestamp: dedupTime(6)},
+	}
+
+	opts := DefaultDedupOptions()
+	opts.Pairs = []DedupPair{{Sources: [2]string{"youtrack", "webhooks"}, Match: []string{DedupByIssueKey}}}
+	result, merged := Deduplicate(activities, opts)
+
+	if merged != 2 || len(result) != 2 {
+		t.Fatalf("Expected 2 activities with 2 duplicates merged, got %d activities and %d merged", len(result), merged)
+	}
+	for _, a := range result {
+		if a.Metadata[MetadataSources] != "webhooks,youtrack" {
+			t.Errorf("Expected one YouTrack change and its webhook per activity, got %s from %q", a.ID, a.Metadata[MetadataSources])
+		}
+	}
+}
+
+func TestDeduplicate_WindowAndSameSource(t *testing.T) {
+	activities := []Activity{
+		{ID: "1", Source: "webhooks", Title: "Deployed release 1.2.3", Timestamp: dedupTime(0)},
+		{ID: "2", Source: "webhooks", Title: "Deployed release 1.2.3", Timestamp: dedupTime(1)},
+		{ID: "3", Source: "calendar", Title: "Deployed release 1.2.3", Timestamp: dedupTime(45)},
+	}
+
+	result, merged := Deduplicate(activities, DefaultDedupOptions())
+	if merged != 0 || len(result) != 3 {
+		t.Errorf("Expected no merges for same source or outside the window, got %d merged", merged)
+	}
+}
+
+func TestDeduplicate_PairOverrides(t *testing.T) {
+	activities := []Activity{
+		{ID: "1", Source: "github", Title: "Fix flaky login test", Timestamp: dedupTime(0), URL: "https://example.com/a"},
+		{ID: "2", Source: "gitlab", Title: "Fix flaky login test", Timestamp: dedupTime(20), URL: "https://example.com/a"},
+	}
+
+	opts := DefaultDedupOptions()
+	if _, merged := Deduplicate(activities, opts); merged != 0 {
+		t.Errorf("Expected no merge outside the default window, got %d", merged)
+	}
+
+	opts.Pairs = []DedupPair{{Sources: [2]string{"gitlab", "github"}, Match: []string{DedupByURL}, Window: 30 * time.Minute}}
+	if _, merged := Deduplicate(activities, opts); merged != 1 {
+		t.Errorf("Expected the pair window override to merge, got %d", merged)
+	}
+
+	opts.Pairs = []DedupPair{{Sources: [2]string{"github", "gitlab"}, Disabled: true, Window: 30 * time.Minute}}
+	if _, merged := Deduplicate(activities, opts); merged != 0 {
+		t.Errorf("Expected disabled pair not to merge, got %d", merged)
+	}
+}
+
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"https://GitHub.com/acme/api/pull/1/", "https://github.com/acme/api/pull/1"},
+		{"https://github.com/acme/api/pull/1#discussion", "https://github.com/acme/api/pull/1"},
+		{"https://github.com", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeURL(tt.input); got != tt.expected {
+			t.Errorf("normalizeURL(%q) = %q, expected %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestTitleSimilarity(t *testing.T) {
+	if s := titleSimilarity("fix login bug", "fix login bug"); s != 1 {
+		t.Errorf("Expected identical titles to have similarity 1, got %v", s)
+	}
+	if s := titleSimilarity("fix login bug", "fix logout bug"); s >= 0.9 {
+		t.Errorf("Expected different titles below 0.9, got %v", s)
+	}
+}
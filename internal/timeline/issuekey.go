@@ -0,0 +1,20 @@
+package timeline
+
+import (
+	"regexp"
+	"strings"
+)
+
+// IssueKeyPattern matches issue keys such as ACME-123 in titles: a project
+// short name of at least two capital letters, digits or underscores, a dash
+// and the issue number. It also matches look-alikes such as UTF-8, so keys
+// found in text should be checked against known projects before they are
+// used to change an issue.
+var IssueKeyPattern = regexp.MustCompile(`\b[A-Z][A-Z0-9_]+-[0-9]+\b`)
+
+// IssueKeyProject returns the project short name of an issue key, ACME for
+// ACME-123
+func IssueKeyProject(key string) string {
+	project, _, _ := strings.Cut(key, "-")
+	return project
+}
@@ -0,0 +1,418 @@
+package timesheet
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/arkeo/arkeo/internal/timeline"
+)
+
+const (
+	// UnassignedProject is used for work blocks that no activity could
+	// attribute to a project
+	UnassignedProject = "(unassigned)"
+
+	// MeetingsProject is used for calendar events whose title doesn't
+	// mention any known project
+	MeetingsProject = "Meetings"
+)
+
+// Options controls how activities are turned into work blocks and hours
+type Options struct {
+	// Increment is the rounding increment applied to each project/day cell.
+	// Zero disables rounding.
+	Increment time.Duration
+
+	// MaxGap is the longest pause between two activities that still counts
+	// as a single work block
+	MaxGap time.Duration
+
+	// PointDuration is the time credited before activities that have no
+	// duration of their own, such as commits or issue updates
+	PointDuration time.Duration
+
+	// IssueProjects are the short names of the YouTrack projects whose
+	// issue keys are taken from titles and from the metadata of other
+	// sources when time is attributed to issues. Keys of YouTrack
+	// activities are always used.
+	IssueProjects map[string]bool
+}
+
+// Block is a contiguous stretch of work attributed to a single project
+type Block struct {
+	Project string    `json:"project"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+}
+
+// Row holds the hours spent on one project, keyed by day (YYYY-MM-DD)
+type Row struct {
+	Project string             `json:"project"`
+	Hours   map[string]float64 `json:"hours"`
+	Total   float64            `json:"total"`
+}
+
+// Timesheet is a project × day grid of hours
+type Timesheet struct {
+	Days      []time.Time        `json:"days"`
+	Rows      []Row              `json:"rows"`
+	DayTotals map[string]float64 `json:"day_totals"`
+	Total     float64            `json:"total"`
+	Blocks    []Block            `json:"blocks"`
+}
+
+// interval is a stretch of time covered by a single activity
+type interval struct {
+	start    time.Time
+	end      time.Time
+	project  string
+	priority int
+}
+
+// Build infers work blocks from the activities on each of the given days and
+// sums them into a project × day grid of hours. Days are matched against the
+// local date of each activity, like the timeline display does.
+func Build(activities []timeline.Activity, days []time.Time, opts Options) *Timesheet {
+	ts := &Timesheet{
+		Days:      days,
+		DayTotals: make(map[string]float64),
+		Blocks:    []Block{},
+	}
+
+	known := knownProjects(activities)
+
+	byDay := make(map[string][]timeline.Activity)
+	for _, a := range activities {
+		key := a.Timestamp.In(time.Local).Format("2006-01-02")
+		byDay[key] = append(byDay[key], a)
+	}
+
+	rows := make(map[string]*Row)
+	for _, day := range days {
+		key := day.Format("2006-01-02")
+		minutes, blocks := buildDay(byDay[key], day, opts, known)
+		ts.Blocks = append(ts.Blocks, blocks...)
+
+		for project, m := range minutes {
+			hours := roundHours(m, opts.Increment)
+			if hours == 0 {
+				continue
+			}
+			row, ok := rows[project]
+			if !ok {
+				row = &Row{Project: project, Hours: make(map[string]float64)}
+				rows[project] = row
+			}
+			row.Hours[key] += hours
+			row.Total += hours
+			ts.DayTotals[key] += hours
+			ts.Total += hours
+		}
+	}
+
+	ts.Rows = make([]Row, 0, len(rows))
+	for _, row := range rows {
+		ts.Rows = append(ts.Rows, *row)
+	}
+	sort.Slice(ts.Rows, func(i, j int) bool {
+		if ts.Rows[i].Total != ts.Rows[j].Total {
+			return ts.Rows[i].Total > ts.Rows[j].Total
+		}
+		return ts.Rows[i].Project < ts.Rows[j].Project
+	})
+
+	return ts
+}
+
+// buildDay returns the minutes worked per project on a single local day,
+// along with the work blocks that make them up
+func buildDay(activities []timeline.Activity, day time.Time, opts Options, known []string) (map[string]int, []Block) {
+	dayStart := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.Local)
+	dayEnd := dayStart.AddDate(0, 0, 1)
+	slots := int(dayEnd.Sub(dayStart) / time.Minute)
+
+	sort.Slice(activities, func(i, j int) bool {
+		return activities[i].Timestamp.Before(activities[j].Timestamp)
+	})
+
+	idle := make([]bool, slots)
+	for _, iv := range idleIntervals(activities, dayEnd) {
+		for m := minuteFloor(iv.start, dayStart, slots); m < minuteCeil(iv.end, dayStart, slots); m++ {
+			idle[m] = true
+		}
+	}
+
+	intervals := workIntervals(activities, dayStart, dayEnd, opts, known)
+	if len(intervals) == 0 {
+		return nil, nil
+	}
+
+	// Attribute each minute to the highest priority activity covering it
+	project := make([]string, slots)
+	priority := make([]int, slots)
+	for i := range priority {
+		priority[i] = -1
+	}
+	for _, iv := range intervals {
+		for m := minuteFloor(iv.start, dayStart, slots); m < minuteCeil(iv.end, dayStart, slots); m++ {
+			if iv.priority > priority[m] {
+				priority[m] = iv.priority
+				project[m] = iv.project
+			}
+		}
+	}
+
+	minutes := make(map[string]int)
+	var blocks []Block
+
+	for _, b := range mergeIntervals(intervals, opts.MaxGap) {
+		from := minuteFloor(b.start, dayStart, slots)
+		to := minuteCeil(b.end, dayStart, slots)
+
+		// Minutes in the block without a project of their own (gaps
+		// between activities, unattributed browsing) go to the project
+		// that dominates the block
+		counts := make(map[string]int)
+		for m := from; m < to; m++ {
+			if !idle[m] && project[m] != "" {
+				counts[project[m]]++
+			}
+		}
+		fallback := dominantProject(counts)
+
+		current := ""
+		var blockStart int
+		for m := from; m <= to; m++ {
+			p := ""
+			if m < to && !idle[m] {
+				p = project[m]
+				if p == "" {
+					p = fallback
+				}
+				minutes[p]++
+			}
+			if p != current {
+				if current != "" {
+					blocks = append(blocks, Block{
+						Project: current,
+						Start:   dayStart.Add(time.Duration(blockStart) * time.Minute),
+						End:     dayStart.Add(time.Duration(m) * time.Minute),
+					})
+				}
+				current = p
+				blockStart = m
+			}
+		}
+	}
+
+	return minutes, blocks
+}
+
+// idleIntervals derives the periods the computer was locked or asleep from
+// the lock state events of the macos_system and linux_session connectors
+func idleIntervals(activities []timeline.Activity, dayEnd time.Time) []interval {
+	var idle []interval
+	var idleSince *time.Time
+
+	for _, a := range activities {
+		switch a.Metadata["lock_state"] {
+		case "1":
+			if idleSince == nil {
+				ts := a.Timestamp
+				idleSince = &ts
+			}
+		case "0":
+			if idleSince != nil {
+				idle = append(idle, interval{start: *idleSince, end: a.Timestamp})
+				idleSince = nil
+			}
+		}
+	}
+
+	if idleSince != nil {
+		idle = append(idle, interval{start: *idleSince, end: dayEnd})
+	}
+
+	return idle
+}
+
+// workIntervals converts activities into the time spans they account for.
+// Activities with a duration cover it; the others are credited with
+// PointDuration leading up to them.
+func workIntervals(activities []timeline.Activity, dayStart, dayEnd time.Time, opts Options, known []string) []interval {
+	var intervals []interval
+
+	for _, a := range activities {
+		if _, ok := a.Metadata["lock_state"]; ok {
+			continue
+		}
+		if a.Type == timeline.ActivityTypeCalendar && strings.EqualFold(a.Metadata["status"], "CANCELLED") {
+			continue
+		}
+
+		start, end := a.Timestamp, a.Timestamp
+		if a.Duration != nil && *a.Duration > 0 {
+			// All-day events say nothing about when work happened
+			if *a.Duration >= 24*time.Hour {
+				continue
+			}
+			end = start.Add(*a.Duration)
+		} else {
+			start = start.Add(-opts.PointDuration)
+		}
+
+		if start.Before(dayStart) {
+			start = dayStart
+		}
+		if end.After(dayEnd) {
+			end = dayEnd
+		}
+		if !end.After(start) {
+			continue
+		}
+
+		project := projectFor(a, known)
+		priority := 0
+		if a.Type == timeline.ActivityTypeCalendar {
+			priority = 2
+		} else if project != "" {
+			priority = 1
+		}
+
+		intervals = append(intervals, interval{start: start, end: end, project: project, priority: priority})
+	}
+
+	return intervals
+}
+
+// mergeIntervals merges intervals separated by at most maxGap into blocks
+func mergeIntervals(intervals []interval, maxGap time.Duration) []interval {
+	sorted := make([]interval, len(intervals))
+	copy(sorted, intervals)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].start.Before(sorted[j].start)
+	})
+
+	var merged []interval
+	for _, iv := range sorted {
+		if len(merged) > 0 {
+			last := &merged[len(merged)-1]
+			if iv.start.Sub(last.end) <= maxGap {
+				if iv.end.After(last.end) {
+					last.end = iv.end
+				}
+				continue
+			}
+		}
+		merged = append(merged, interval{start: iv.start, end: iv.end})
+	}
+
+	return merged
+}
+
+// projectFor returns the project an activity belongs to, or "" when it
+// carries no hint
+func projectFor(a timeline.Activity, known []string) string {
+	if p := a.Metadata["project"]; p != "" {
+		return p
+	}
+	if p := a.Metadata["repository"]; p != "" {
+		return p
+	}
+
+	if a.Type == timeline.ActivityTypeCalendar {
+		title := strings.ToLower(a.Title)
+		for _, p := range known {
+			if name := shortProjectName(p); len(name) >= 3 && strings.Contains(title, name) {
+				return p
+			}
+		}
+		return MeetingsProject
+	}
+
+	return ""
+}
+
+// knownProjects returns the projects referenced by non-calendar activities,
+// longest name first so calendar titles match the most specific one
+func knownProjects(activities []timeline.Activity) []string {
+	seen := make(map[string]bool)
+	var projects []string
+	for _, a := range activities {
+		if a.Type == timeline.ActivityTypeCalendar {
+			continue
+		}
+		if p := projectFor(a, nil); p != "" && !seen[p] {
+			seen[p] = true
+			projects = append(projects, p)
+		}
+	}
+
+	sort.Slice(projects, func(i, j int) bool {
+		if len(projects[i]) != len(projects[j]) {
+			return len(projects[i]) > len(projects[j])
+		}
+		return projects[i] < projects[j]
+	})
+	return projects
+}
+
+// shortProjectName strips the owner from repository paths such as
+// "acme/billing-api", lowercased for matching
+func shortProjectName(project string) string {
+	if i := strings.LastIndex(project, "/"); i >= 0 {
+		project = project[i+1:]
+	}
+	return strings.ToLower(project)
+}
+
+// dominantProject returns the project with the most minutes, or
+// UnassignedProject when there is none
+func dominantProject(counts map[string]int) string {
+	best := UnassignedProject
+	bestCount := 0
+	for p, c := range counts {
+		if c > bestCount || (c == bestCount && p < best) {
+			best = p
+			bestCount = c
+		}
+	}
+	return best
+}
+
+// roundHours converts minutes to hours rounded to the nearest increment
+func roundHours(minutes int, increment time.Duration) float64 {
+	if increment <= 0 {
+		return math.Round(float64(minutes)/60*100) / 100
+	}
+	inc := increment.Minutes()
+	return math.Round(float64(minutes)/inc) * inc / 60
+}
+
+// minuteFloor returns the minute slot containing t, clamped to the day
+func minuteFloor(t, dayStart time.Time, slots int) int {
+	m := int(t.Sub(dayStart) / time.Minute)
+	return clampSlot(m, slots)
+}
+
+// minuteCeil returns the first minute slot starting at or after t, clamped to the day
+func minuteCeil(t, dayStart time.Time, slots int) int {
+	d := t.Sub(dayStart)
+	m := int(d / time.Minute)
+	if d%time.Minute != 0 {
+		m++
+	}
+	return clampSlot(m, slots)
+}
+
+func clampSlot(m, slots int) int {
+	if m < 0 {
+		return 0
+	}
+	if m > slots {
+		return slots
+	}
+	return m
+}
@@ -0,0 +1,156 @@
+package timesheet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arkeo/arkeo/internal/timeline"
+)
+
+func at(day, hour, minute int) time.Time {
+	return time.Date(2024, 3, day, hour, minute, 0, 0, time.Local)
+}
+
+func dur(d time.Duration) *time.Duration {
+	return &d
+}
+
+// testOptions are the options of the default timesheet config
+func testOptions() Options {
+	return Options{Increment: 15 * time.Minute, MaxGap: 30 * time.Minute, PointDuration: 15 * time.Minute}
+}
+
+func testDays() []time.Time {
+	return []time.Time{
+		time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func findRow(ts *Timesheet, project string) *Row {
+	for i := range ts.Rows {
+		if ts.Rows[i].Project == project {
+			return &ts.Rows[i]
+		}
+	}
+	return nil
+}
+
+func TestBuild_CommitCluster(t *testing.T) {
+	activities := []timeline.Activity{
+		{Type: timeline.ActivityTypeGitCommit, Timestamp: at(4, 9, 30), Metadata: map[string]string{"repository": "acme/api"}},
+		{Type: timeline.ActivityTypeGitCommit, Timestamp: at(4, 10, 0), Metadata: map[string]string{"repository": "acme/api"}},
+		{Type: timeline.ActivityTypeGitCommit, Timestamp: at(4, 10, 45), Metadata: map[string]string{"repository": "acme/api"}},
+	}
+
+	ts := Build(activities, testDays(), testOptions())
+
+	row := findRow(ts, "acme/api")
+	if row == nil {
+		t.Fatal("Expected a row for acme/api")
+	}
+	// 09:15 (first commit minus 15m) to 10:45
+	if row.Hours["2024-03-04"] != 1.5 {
+		t.Errorf("Expected 1.5 hours, got %v", row.Hours["2024-03-04"])
+	}
+	if ts.Total != 1.5 {
+		t.Errorf("Expected total 1.5, got %v", ts.Total)
+	}
+}
+
+func TestBuild_SeparateBlocksAndDays(t *testing.T) {
+	activities := []timeline.Activity{
+		{Type: timeline.ActivityTypeGitCommit, Timestamp: at(4, 9, 0), Metadata: map[string]string{"repository": "acme/api"}},
+		{Type: timeline.ActivityTypeGitCommit, Timestamp: at(4, 14, 0), Metadata: map[string]string{"repository": "acme/api"}},
+		{Type: timeline.ActivityTypeYouTrack, Timestamp: at(5, 11, 0), Metadata: map[string]string{"project": "Billing"}},
+	}
+
+	ts := Build(activities, testDays(), testOptions())
+
+	if got := findRow(ts, "acme/api").Hours["2024-03-04"]; got != 0.5 {
+		t.Errorf("Expected two 15 minute blocks (0.5h), got %v", got)
+	}
+	if got := findRow(ts, "Billing").Hours["2024-03-05"]; got != 0.25 {
+		t.Errorf("Expected 0.25h for Billing, got %v", got)
+	}
+	if ts.DayTotals["2024-03-04"] != 0.5 || ts.DayTotals["2024-03-05"] != 0.25 {
+		t.Errorf("Unexpected day totals: %v", ts.DayTotals)
+	}
+}
+
+func TestBuild_CalendarAndBrowser(t *testing.T) {
+	activities := []timeline.Activity{
+		{Type: timeline.ActivityTypeGitCommit, Timestamp: at(4, 9, 0), Metadata: map[string]string{"repository": "acme/billing"}},
+		// Unattributed browsing inside the block goes to the dominant project
+		{Type: timeline.ActivityTypeBrowser, Timestamp: at(4, 9, 0), Duration: dur(30 * time.Minute)},
+		{Type: timeline.ActivityTypeCalendar, Title: "Billing sync", Timestamp: at(4, 11, 0), Duration: dur(time.Hour)},
+		{Type: timeline.ActivityTypeCalendar, Title: "All hands", Timestamp: at(4, 14, 0), Duration: dur(30 * time.Minute)},
+		{Type: timeline.ActivityTypeCalendar, Title: "Holiday", Timestamp: at(4, 0, 0), Duration: dur(24 * time.Hour)},
+	}
+
+	ts := Build(activities, testDays(), testOptions())
+
+	if got := findRow(ts, "acme/billing").Hours["2024-03-04"]; got != 1.75 {
+		t.Errorf("Expected 1.75h for acme/billing, got %v", got)
+	}
+	if got := findRow(ts, MeetingsProject).Hours["2024-03-04"]; got != 0.5 {
+		t.Errorf("Expected 0.5h of meetings, got %v", got)
+	}
+	if len(ts.Rows) != 2 {
+		t.Errorf("Expected 2 rows, got %d", len(ts.Rows))
+	}
+}
+
+func TestBuild_IdleTimeExcluded(t *testing.T) {
+	activities := []timeline.Activity{
+		{Type: timeline.ActivityTypeApplication, Timestamp: at(4, 9, 0), Duration: dur(3 * time.Hour), Metadata: map[string]string{"project": "arkeo"}},
+		{Type: timeline.ActivityTypeSystem, Timestamp: at(4, 10, 0), Metadata: map[string]string{"lock_state": "1"}},
+		{Type: timeline.ActivityTypeSystem, Timestamp: at(4, 11, 0), Metadata: map[string]string{"lock_state": "0"}},
+	}
+
+	ts := Build(activities, testDays(), testOptions())
+
+	if got := findRow(ts, "arkeo").Hours["2024-03-04"]; got != 2 {
+		t.Errorf("Expected 2h after removing idle time, got %v", got)
+	}
+	if len(ts.Blocks) != 2 {
+		t.Errorf("Expected idle time to split the work into 2 blocks, got %d", len(ts.Blocks))
+	}
+}
+
+func TestBuild_UnassignedAndRounding(t *testing.T) {
+	activities := []timeline.Activity{
+		{Type: timeline.ActivityTypeBrowser, Timestamp: at(4, 9, 0), Duration: dur(50 * time.Minute)},
+	}
+
+	ts := Build(activities, testDays(), testOptions())
+	if got := findRow(ts, UnassignedProject).Hours["2024-03-04"]; got != 0.75 {
+		t.Errorf("Expected 50 minutes to round to 0.75h, got %v", got)
+	}
+
+	opts := testOptions()
+	opts.Increment = time.Hour
+	ts = Build(activities, testDays(), opts)
+	if got := findRow(ts, UnassignedProject).Hours["2024-03-04"]; got != 1 {
+		t.Errorf("Expected 50 minutes to round to 1h, got %v", got)
+	}
+}
+
+func TestRoundHours(t *testing.T) {
+	tests := []struct {
+		minutes   int
+		increment time.Duration
+		expected  float64
+	}{
+		{7, 15 * time.Minute, 0},
+		{8, 15 * time.Minute, 0.25},
+		{95, 30 * time.Minute, 1.5},
+		{20, 0, 0.33},
+	}
+
+	for _, tt := range tests {
+		if got := roundHours(tt.minutes, tt.increment); got != tt.expected {
+			t.Errorf("roundHours(%d, %v) = %v, expected %v", tt.minutes, tt.increment, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package timesheet
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/arkeo/arkeo/internal/timeline"
+)
+
+// maxEntryText is the longest work item text built from activity titles
+const maxEntryText = 500
+
+// IssueEntry is the time spent on one issue on one local day, ready to be
+// logged to an issue tracker
+type IssueEntry struct {
+	IssueKey string        `json:"issue"`
+	Date     time.Time     `json:"date"`
+	Duration time.Duration `json:"duration"`
+	Text     string        `json:"text"`
+
+	// ActivityIDs lists the activities the time was derived from
+	ActivityIDs []string `json:"activity_ids"`
+}
+
+// IssueKey returns the YouTrack issue an activity refers to: the issue_key
+// metadata of YouTrack activities, or else the first issue key in its
+// metadata or title whose project is one of projects. Keys of other
+// trackers, such as Jira, and look-alikes such as UTF-8 are ignored.
+func IssueKey(a timeline.Activity, projects map[string]bool) string {
+	key := strings.ToUpper(a.Metadata["issue_key"])
+	if key != "" && a.Type == timeline.ActivityTypeYouTrack {
+		return key
+	}
+
+	candidates := timeline.IssueKeyPattern.FindAllString(a.Title, -1)
+	if key != "" {
+		candidates = append([]string{key}, candidates...)
+	}
+	for _, candidate := range candidates {
+		if projects[timeline.IssueKeyProject(candidate)] {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// IssueEntriesFromActivities credits each activity that refers to an issue
+// with its own duration, or PointDuration when it has none, and sums them
+// per issue and day
+func IssueEntriesFromActivities(activities []timeline.Activity, opts Options) []IssueEntry {
+	acc := newEntryAccumulator()
+	for _, a := range activities {
+		key := IssueKey(a, opts.IssueProjects)
+		if key == "" {
+			continue
+		}
+		d := opts.PointDuration
+		if a.Duration != nil && *a.Duration > 0 {
+			// All-day events say nothing about time spent on an issue
+			if *a.Duration >= 24*time.Hour {
+				continue
+			}
+			d = *a.Duration
+		}
+		acc.add(key, a.Timestamp, d, a)
+	}
+	return acc.entries(opts.Increment)
+}
+
+// IssueEntriesFromBlocks attributes each inferred work block of the
+// timesheet to the issue most of its activities refer to, and sums the
+// blocks per issue and day. Blocks without any issue are left out.
+func IssueEntriesFromBlocks(ts *Timesheet, activities []timeline.Activity, opts Options) []IssueEntry {
+	sorted := make([]timeline.Activity, len(activities))
+	copy(sorted, activities)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	acc := newEntryAccumulator()
+	for _, b := range ts.Blocks {
+		counts := make(map[string]int)
+		var inBlock []timeline.Activity
+		for _, a := range sorted {
+			if a.Timestamp.Before(b.Start) || a.Timestamp.After(b.End) {
+				continue
+			}
+			if key := IssueKey(a, opts.IssueProjects); key != "" {
+				counts[key]++
+				inBlock = append(inBlock, a)
+			}
+		}
+		if len(counts) == 0 {
+			continue
+		}
+
+		key := ""
+		for k, c := range counts {
+			if key == "" || c > counts[key] || (c == counts[key] && k < key) {
+				key = k
+			}
+		}
+
+		first := true
+		for _, a := range inBlock {
+			if IssueKey(a, opts.IssueProjects) != key {
+				continue
+			}
+			d := time.Duration(0)
+			if first {
+				d = b.End.Sub(b.Start)
+				first = false
+			}
+			acc.add(key, b.Start, d, a)
+		}
+	}
+	return acc.entries(opts.Increment)
+}
+
+// entryAccumulator sums time and collects titles per issue and day
+type entryAccumulator struct {
+	byKey  map[string]*IssueEntry
+	titles map[string][]string
+	order  []string
+}
+
+func newEntryAccumulator() *entryAccumulator {
+	return &entryAccumulator{
+		byKey:  make(map[string]*IssueEntry),
+		titles: make(map[string][]string),
+	}
+}
+
+func (acc *entryAccumulator) add(issueKey string, at time.Time, d time.Duration, a timeline.Activity) {
+	local := at.In(time.Local)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.Local)
+	id := issueKey + "|" + day.Format("2006-01-02")
+
+	entry, ok := acc.byKey[id]
+	if !ok {
+		entry = &IssueEntry{IssueKey: issueKey, Date: day}
+		acc.byKey[id] = entry
+		acc.order = append(acc.order, id)
+	}
+	entry.Duration += d
+	if a.ID != "" {
+		entry.ActivityIDs = append(entry.ActivityIDs, a.ID)
+	}
+
+	title := strings.TrimSpace(a.Title)
+	for _, t := range acc.titles[id] {
+		if t == title {
+			return
+		}
+	}
+	if title != "" {
+		acc.titles[id] = append(acc.titles[id], title)
+	}
+}
+
+// entries returns the accumulated entries with their durations rounded to
+// the nearest increment, dropping the ones that round to nothing, ordered
+// by day and issue
+func (acc *entryAccumulator) entries(increment time.Duration) []IssueEntry {
+	result := make([]IssueEntry, 0, len(acc.order))
+	for _, id := range acc.order {
+		entry := *acc.byKey[id]
+		if increment > 0 {
+			entry.Duration = entry.Duration.Round(increment)
+		} else {
+			entry.Duration = entry.Duration.Round(time.Minute)
+		}
+		if entry.Duration <= 0 {
+			continue
+		}
+
+		entry.Text = strings.Join(acc.titles[id], "; ")
+		if runes := []rune(entry.Text); len(runes) > maxEntryText {
+			entry.Text = string(runes[:maxEntryText-3]) + "..."
+		}
+		result = append(result, entry)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if !result[i].Date.Equal(result[j].Date) {
+			return result[i].Date.Before(result[j].Date)
+		}
+		return result[i].IssueKey < result[j].IssueKey
+	})
+	return result
+}
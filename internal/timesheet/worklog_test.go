@@ -0,0 +1,83 @@
+package timesheet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arkeo/arkeo/internal/timeline"
+)
+
+func TestIssueKey(t *testing.T) {
+	projects := map[string]bool{"ACME": true, "OPS": true}
+	tests := []struct {
+		activity timeline.Activity
+		expected string
+	}{
+		{timeline.Activity{Title: "Updated State in ACME-12: Login", Metadata: map[string]string{"issue_key": "acme-12"}}, "ACME-12"},
+		{timeline.Activity{Type: timeline.ActivityTypeYouTrack, Title: "Commented on WEB-3", Metadata: map[string]string{"issue_key": "WEB-3"}}, "WEB-3"},
+		{timeline.Activity{Type: timeline.ActivityTypeJira, Title: "Commented on PAY-9", Metadata: map[string]string{"issue_key": "PAY-9"}}, ""},
+		{timeline.Activity{Title: "Merge branch 'OPS-7-fix-deploy' into main"}, "OPS-7"},
+		{timeline.Activity{Title: "Read files as UTF-8, hash with SHA-256 (ACME-4)"}, "ACME-4"},
+		{timeline.Activity{Title: "Parse ISO-8601 dates"}, ""},
+		{timeline.Activity{Title: "Planning meeting"}, ""},
+	}
+
+	for _, tt := range tests {
+		if got := IssueKey(tt.activity, projects); got != tt.expected {
+			t.Errorf("IssueKey(%q) = %q, expected %q", tt.activity.Title, got, tt.expected)
+		}
+	}
+}
+
+func TestIssueEntriesFromActivities(t *testing.T) {
+	activities := []timeline.Activity{
+		{ID: "cal-1", Type: timeline.ActivityTypeCalendar, Title: "ACME-12 design review", Timestamp: at(4, 10, 0), Duration: dur(50 * time.Minute)},
+		{ID: "yt-1", Type: timeline.ActivityTypeYouTrack, Title: "Commented on ACME-12: Login", Timestamp: at(4, 14, 0), Metadata: map[string]string{"issue_key": "ACME-12"}},
+		{ID: "yt-2", Type: timeline.ActivityTypeYouTrack, Title: "Commented on OPS-3: Deploy", Timestamp: at(5, 9, 0), Metadata: map[string]string{"issue_key": "OPS-3"}},
+		{ID: "git-1", Type: timeline.ActivityTypeGitCommit, Title: "Refactor session handling", Timestamp: at(4, 15, 0)},
+	}
+
+	opts := testOptions()
+	opts.IssueProjects = map[string]bool{"ACME": true}
+	entries := IssueEntriesFromActivities(activities, opts)
+
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d: %+v", len(entries), entries)
+	}
+	// 50m meeting + 15m for the comment, rounded to the quarter hour
+	if entries[0].IssueKey != "ACME-12" || entries[0].Duration != time.Hour {
+		t.Errorf("Unexpected first entry: %+v", entries[0])
+	}
+	if entries[0].Text != "ACME-12 design review; Commented on ACME-12: Login" {
+		t.Errorf("Unexpected text: %q", entries[0].Text)
+	}
+	if len(entries[0].ActivityIDs) != 2 {
+		t.Errorf("Expected 2 activity IDs, got %v", entries[0].ActivityIDs)
+	}
+	if entries[1].IssueKey != "OPS-3" || entries[1].Date.Day() != 5 || entries[1].Duration != 15*time.Minute {
+		t.Errorf("Unexpected second entry: %+v", entries[1])
+	}
+}
+
+func TestIssueEntriesFromBlocks(t *testing.T) {
+	activities := []timeline.Activity{
+		{ID: "yt-1", Type: timeline.ActivityTypeYouTrack, Title: "Updated State in ACME-12: Login", Timestamp: at(4, 9, 30), Metadata: map[string]string{"issue_key": "ACME-12", "project": "Acme"}},
+		{ID: "git-1", Type: timeline.ActivityTypeGitCommit, Title: "ACME-12 fix redirect", Timestamp: at(4, 10, 0), Metadata: map[string]string{"project": "Acme"}},
+		{ID: "git-2", Type: timeline.ActivityTypeGitCommit, Title: "OPS-1 bump version", Timestamp: at(4, 10, 30), Metadata: map[string]string{"project": "Acme"}},
+		{ID: "git-3", Type: timeline.ActivityTypeGitCommit, Title: "Tidy up", Timestamp: at(4, 16, 0), Metadata: map[string]string{"project": "Other"}},
+	}
+
+	opts := testOptions()
+	opts.IssueProjects = map[string]bool{"ACME": true, "OPS": true}
+	ts := Build(activities, testDays(), opts)
+	entries := IssueEntriesFromBlocks(ts, activities, opts)
+
+	// The 09:15-10:30 Acme block goes to ACME-12, referenced twice; the
+	// afternoon block has no issue
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d: %+v", len(entries), entries)
+	}
+	if entries[0].IssueKey != "ACME-12" || entries[0].Duration != 75*time.Minute {
+		t.Errorf("Unexpected entry: %+v", entries[0])
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -23,6 +25,32 @@ type ConnectorResult struct {
 	Duration   time.Duration
 }
 
+// RangeConnector is implemented by connectors that can fetch several days
+// with a single call. It mirrors connectors.RangeConnector.
+type RangeConnector interface {
+	Connector
+	GetActivitiesRange(ctx context.Context, start, end time.Time) ([]timeline.Activity, error)
+}
+
+// DayActivities holds the activities a connector returned for one day
+type DayActivities struct {
+	Date       time.Time
+	Activities []timeline.Activity
+	Error      error
+}
+
+// ConnectorRangeResult represents the result of fetching several days from a
+// connector, split back into one entry per requested day
+type ConnectorRangeResult struct {
+	Name     string
+	Days     []DayActivities
+	Duration time.Duration
+}
+
+// MaxRangeDays is the most days fetched from a RangeConnector with a single
+// call; longer runs of days are split into several calls
+const MaxRangeDays = 31
+
 // ParallelExecutor handles parallel execution of multiple connectors
 type ParallelExecutor struct {
 	maxConcurrency int
@@ -88,6 +116,7 @@ func (pe *ParallelExecutor) FetchActivitiesParallelWithProgress(ctx context.Cont
 			start := time.Now()
 			activities, err := conn.GetActivities(connectorCtx, date)
 			duration := time.Since(start)
+			activities = AttributeActivities(connectorName, activities)
 
 			result := ConnectorResult{
 				Name:       connectorName,
@@ -124,6 +153,184 @@ func (pe *ParallelExecutor) FetchActivitiesParallelWithProgress(ctx context.Cont
 	return allResults
 }
 
+// FetchActivitiesRangeParallel fetches activities for several days from
+// multiple connectors in parallel. Connectors implementing RangeConnector are
+// called once per run of up to MaxRangeDays consecutive days, each call with
+// the executor's timeout; the others are called once per day.
+// Each result holds one DayActivities entry per requested day, in the order
+// of days.
+func (pe *ParallelExecutor) FetchActivitiesRangeParallel(ctx context.Context, connectorMap map[string]Connector, days []time.Time) []ConnectorRangeResult {
+	if len(days) == 0 {
+		return nil
+	}
+
+	daysByConnector := make(map[string][]time.Time, len(connectorMap))
+	for name := range connectorMap {
+		daysByConnector[name] = days
+	}
+	return pe.FetchDaysParallel(ctx, connectorMap, daysByConnector)
+}
+
+// FetchDaysParallel is like FetchActivitiesRangeParallel, but fetches each
+// connector only for its own days in daysByConnector. Connectors without
+// days are left out.
+func (pe *ParallelExecutor) FetchDaysParallel(ctx context.Context, connectorMap map[string]Connector, daysByConnector map[string][]time.Time) []ConnectorRangeResult {
+	semaphore := make(chan struct{}, pe.maxConcurrency)
+	results := make(chan ConnectorRangeResult, len(connectorMap))
+
+	var wg sync.WaitGroup
+
+	for name, connector := range connectorMap {
+		days := daysByConnector[name]
+		if len(days) == 0 {
+			continue
+		}
+		semaphore <- struct{}{}
+
+		wg.Add(1)
+		go func(connectorName string, conn Connector, days []time.Time) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			start := time.Now()
+			var dayResults []DayActivities
+
+			if rc, ok := conn.(RangeConnector); ok && len(days) > 1 {
+				// One call per run of consecutive days, so days in between
+				// aren't fetched again and each call gets its own timeout
+				byDate := make(map[string]DayActivities, len(days))
+				for _, run := range dayRuns(days, MaxRangeDays) {
+					var runResults []DayActivities
+					if len(run) == 1 {
+						runResults = []DayActivities{pe.fetchDay(ctx, connectorName, conn, run[0])}
+					} else {
+						connectorCtx, cancel := context.WithTimeout(ctx, pe.timeout)
+						activities, err := rc.GetActivitiesRange(connectorCtx, run[0], run[len(run)-1])
+						cancel()
+						if err != nil {
+							for _, day := range run {
+								runResults = append(runResults, DayActivities{Date: day, Error: err})
+							}
+						} else {
+							runResults = SplitActivitiesByDay(AttributeActivities(connectorName, activities), run)
+						}
+					}
+					for _, dayResult := range runResults {
+						byDate[dayResult.Date.Format("2006-01-02")] = dayResult
+					}
+				}
+				for _, day := range days {
+					dayResults = append(dayResults, byDate[day.Format("2006-01-02")])
+				}
+			} else {
+				for _, day := range days {
+					dayResults = append(dayResults, pe.fetchDay(ctx, connectorName, conn, day))
+				}
+			}
+
+			results <- ConnectorRangeResult{
+				Name:     connectorName,
+				Days:     dayResults,
+				Duration: time.Since(start),
+			}
+		}(name, connector, days)
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var allResults []ConnectorRangeResult
+	for result := range results {
+		allResults = append(allResults, result)
+	}
+
+	return allResults
+}
+
+// fetchDay fetches a single day from a connector
+func (pe *ParallelExecutor) fetchDay(ctx context.Context, name string, conn Connector, day time.Time) DayActivities {
+	connectorCtx, cancel := context.WithTimeout(ctx, pe.timeout)
+	defer cancel()
+	activities, err := conn.GetActivities(connectorCtx, day)
+	return DayActivities{Date: day, Activities: AttributeActivities(name, activities), Error: err}
+}
+
+// dayRuns sorts days and splits them into runs of consecutive days of at
+// most maxLen days each
+func dayRuns(days []time.Time, maxLen int) [][]time.Time {
+	sorted := append([]time.Time(nil), days...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+
+	var runs [][]time.Time
+	for i, day := range sorted {
+		if i > 0 && day.Format("2006-01-02") == sorted[i-1].Format("2006-01-02") {
+			continue
+		}
+		if n := len(runs); n > 0 {
+			run := runs[n-1]
+			next := run[len(run)-1].AddDate(0, 0, 1)
+			if len(run) < maxLen && day.Format("2006-01-02") == next.Format("2006-01-02") {
+				runs[n-1] = append(run, day)
+				continue
+			}
+		}
+		runs = append(runs, []time.Time{day})
+	}
+	return runs
+}
+
+// AttributeActivities sets the source of activities to the name of the
+// connector instance they were fetched from, so that two instances of the
+// same connector type (e.g. gitlab and gitlab:client-a) can be told apart.
+// IDs of renamed activities get the instance name as prefix instead of the
+// type, keeping them unique across instances. The activities are copied
+// before being changed, as connectors may hold on to them.
+func AttributeActivities(name string, activities []timeline.Activity) []timeline.Activity {
+	copied := false
+	for i := range activities {
+		if activities[i].Source == name {
+			continue
+		}
+		if !copied {
+			activities = append([]timeline.Activity(nil), activities...)
+			copied = true
+		}
+		a := &activities[i]
+		if a.ID != "" && a.Source != "" {
+			if strings.HasPrefix(a.ID, a.Source) {
+				a.ID = name + strings.TrimPrefix(a.ID, a.Source)
+			} else {
+				a.ID = name + "-" + a.ID
+			}
+		}
+		a.Source = name
+	}
+	return activities
+}
+
+// SplitActivitiesByDay assigns activities to the requested days by the local
+// date of their timestamp. Activities falling on a day that wasn't requested
+// are dropped, and every requested day gets an entry even if it is empty.
+func SplitActivitiesByDay(activities []timeline.Activity, days []time.Time) []DayActivities {
+	index := make(map[string]int, len(days))
+	dayResults := make([]DayActivities, len(days))
+	for i, day := range days {
+		dayResults[i] = DayActivities{Date: day, Activities: []timeline.Activity{}}
+		index[day.Format("2006-01-02")] = i
+	}
+
+	for _, activity := range activities {
+		key := activity.Timestamp.In(time.Local).Format("2006-01-02")
+		if i, ok := index[key]; ok {
+			dayResults[i].Activities = append(dayResults[i].Activities, activity)
+		}
+	}
+
+	return dayResults
+}
+
 // FetchAndCombineActivities fetches activities from multiple connectors and combines them
 // This is a convenience function that returns just the activities and handles error reporting
 func (pe *ParallelExecutor) FetchAndCombineActivities(ctx context.Context, connectorMap map[string]Connector, date time.Time, verbose bool) []timeline.Activity {
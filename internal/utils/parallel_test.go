@@ -407,3 +407,226 @@ func BenchmarkParallelExecutor_vs_Sequential(b *testing.B) {
 		}
 	})
 }
+
+// MockRangeConnector records range calls and returns a fixed set of activities
+type MockRangeConnector struct {
+	MockSlowConnector
+	rangeCalls int
+	rangeStart time.Time
+	rangeEnd   time.Time
+}
+
+func (m *MockRangeConnector) GetActivitiesRange(ctx context.Context, start, end time.Time) ([]timeline.Activity, error) {
+	m.mutex.Lock()
+	m.rangeCalls++
+	m.rangeStart = start
+	m.rangeEnd = end
+	m.mutex.Unlock()
+
+	if m.shouldFail {
+		return nil, errors.New("mock range error")
+	}
+	return m.activities, nil
+}
+
+func TestParallelExecutor_FetchActivitiesRangeParallel(t *testing.T) {
+	executor := NewParallelExecutor()
+	ctx := context.Background()
+	days := []time.Time{
+		time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 17, 0, 0, 0, 0, time.UTC),
+	}
+
+	rangeConn := &MockRangeConnector{MockSlowConnector: *NewMockSlowConnector("range", 0)}
+	rangeConn.SetActivities([]timeline.Activity{
+		{ID: "a", Timestamp: time.Date(2024, 1, 15, 10, 0, 0, 0, time.Local)},
+		{ID: "b", Timestamp: time.Date(2024, 1, 17, 9, 0, 0, 0, time.Local)},
+		{ID: "c", Timestamp: time.Date(2024, 1, 17, 11, 0, 0, 0, time.Local)},
+		{ID: "outside", Timestamp: time.Date(2024, 1, 20, 11, 0, 0, 0, time.Local)},
+	})
+
+	dayConn := NewMockSlowConnector("daily", 0)
+	dayConn.SetActivities([]timeline.Activity{{ID: "d"}})
+
+	results := executor.FetchActivitiesRangeParallel(ctx, map[string]Connector{
+		"range": rangeConn,
+		"daily": dayConn,
+	}, days)
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+
+	for _, result := range results {
+		if len(result.Days) != len(days) {
+			t.Fatalf("Expected %d days for %s, got %d", len(days), result.Name, len(result.Days))
+		}
+
+		switch result.Name {
+		case "range":
+			counts := []int{1, 0, 2}
+			for i, day := range result.Days {
+				if day.Error != nil {
+					t.Errorf("Unexpected error for %s: %v", day.Date.Format("2006-01-02"), day.Error)
+				}
+				if len(day.Activities) != counts[i] {
+					t.Errorf("Expected %d activities on %s, got %d", counts[i], day.Date.Format("2006-01-02"), len(day.Activities))
+				}
+			}
+		case "daily":
+			for _, day := range result.Days {
+				if len(day.Activities) != 1 {
+					t.Errorf("Expected 1 activity on %s, got %d", day.Date.Format("2006-01-02"), len(day.Activities))
+				}
+			}
+		}
+	}
+
+	if rangeConn.rangeCalls != 1 {
+		t.Errorf("Expected 1 range call, got %d", rangeConn.rangeCalls)
+	}
+	if rangeConn.GetCallCount() != 0 {
+		t.Errorf("Expected no per-day calls for range connector, got %d", rangeConn.GetCallCount())
+	}
+	if !rangeConn.rangeStart.Equal(days[0]) || !rangeConn.rangeEnd.Equal(days[2]) {
+		t.Errorf("Expected range %v - %v, got %v - %v", days[0], days[2], rangeConn.rangeStart, rangeConn.rangeEnd)
+	}
+	if dayConn.GetCallCount() != len(days) {
+		t.Errorf("Expected %d per-day calls, got %d", len(days), dayConn.GetCallCount())
+	}
+}
+
+func TestParallelExecutor_FetchActivitiesRangeParallel_Error(t *testing.T) {
+	executor := NewParallelExecutor()
+	days := []time.Time{
+		time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC),
+	}
+
+	rangeConn := &MockRangeConnector{MockSlowConnector: *NewMockSlowConnector("range", 0)}
+	rangeConn.SetShouldFail(true)
+
+	results := executor.FetchActivitiesRangeParallel(context.Background(), map[string]Connector{"range": rangeConn}, days)
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	for _, day := range results[0].Days {
+		if day.Error == nil {
+			t.Errorf("Expected error for %s", day.Date.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestSplitActivitiesByDay(t *testing.T) {
+	days := []time.Time{
+		time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC),
+	}
+	activities := []timeline.Activity{
+		{ID: "1", Timestamp: time.Date(2024, 3, 5, 23, 59, 0, 0, time.Local)},
+		{ID: "2", Timestamp: time.Date(2024, 3, 4, 0, 0, 0, 0, time.Local)},
+	}
+
+	split := SplitActivitiesByDay(activities, days)
+	if len(split) != 2 {
+		t.Fatalf("Expected 2 days, got %d", len(split))
+	}
+	if len(split[0].Activities) != 1 || split[0].Activities[0].ID != "2" {
+		t.Errorf("Expected activity 2 on first day, got %+v", split[0].Activities)
+	}
+	if len(split[1].Activities) != 1 || split[1].Activities[0].ID != "1" {
+		t.Errorf("Expected activity 1 on second day, got %+v", split[1].Activities)
+	}
+}
+
+func TestAttributeActivities(t *testing.T) {
+	activities := []timeline.Activity{
+		{ID: "gitlab-42", Source: "gitlab", Title: "Pushed to main"},
+		{ID: "abc", Source: "gitlab", Title: "Opened merge request"},
+	}
+
+	attributed := AttributeActivities("gitlab:client-a", activities)
+
+	if attributed[0].Source != "gitlab:client-a" || attributed[0].ID != "gitlab:client-a-42" {
+		t.Errorf("Expected source and ID of the instance, got %s and %s", attributed[0].Source, attributed[0].ID)
+	}
+	if attributed[1].ID != "gitlab:client-a-abc" {
+		t.Errorf("Expected the instance name as ID prefix, got %s", attributed[1].ID)
+	}
+	if activities[0].Source != "gitlab" {
+		t.Errorf("Expected the connector's activities to be left unchanged, got source %s", activities[0].Source)
+	}
+
+	unchanged := AttributeActivities("gitlab", activities)
+	if unchanged[0].ID != "gitlab-42" || unchanged[0].Source != "gitlab" {
+		t.Errorf("Expected activities of the default instance to be unchanged, got %+v", unchanged[0])
+	}
+}
+
+func TestParallelExecutor_FetchDaysParallel(t *testing.T) {
+	executor := NewParallelExecutor()
+	day1 := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC)
+
+	stale := NewMockSlowConnector("stale", 0)
+	fresh := NewMockSlowConnector("fresh", 0)
+
+	results := executor.FetchDaysParallel(context.Background(), map[string]Connector{
+		"stale": stale,
+		"fresh": fresh,
+	}, map[string][]time.Time{"stale": {day1, day2}})
+
+	if len(results) != 1 || results[0].Name != "stale" || len(results[0].Days) != 2 {
+		t.Fatalf("Expected 2 days for the stale connector only, got %+v", results)
+	}
+	if fresh.GetCallCount() != 0 {
+		t.Errorf("Expected no calls for a connector without days, got %d", fresh.GetCallCount())
+	}
+}
+
+func TestParallelExecutor_FetchActivitiesRangeParallel_Gaps(t *testing.T) {
+	executor := NewParallelExecutor()
+	days := []time.Time{
+		time.Date(2024, 1, 18, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 19, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC),
+	}
+
+	rangeConn := &MockRangeConnector{MockSlowConnector: *NewMockSlowConnector("range", 0)}
+	results := executor.FetchActivitiesRangeParallel(context.Background(), map[string]Connector{"range": rangeConn}, days)
+
+	// The cached 17th is not fetched again
+	if rangeConn.rangeCalls != 2 {
+		t.Errorf("Expected one range call per run of days, got %d", rangeConn.rangeCalls)
+	}
+	if !rangeConn.rangeStart.Equal(days[0]) || !rangeConn.rangeEnd.Equal(days[1]) {
+		t.Errorf("Expected the last range %v - %v, got %v - %v", days[0], days[1], rangeConn.rangeStart, rangeConn.rangeEnd)
+	}
+	for i, day := range results[0].Days {
+		if !day.Date.Equal(days[i]) {
+			t.Errorf("Expected day %d to be %v, got %v", i, days[i], day.Date)
+		}
+	}
+}
+
+func TestDayRuns(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var days []time.Time
+	for i := 0; i < 40; i++ {
+		days = append(days, start.AddDate(0, 0, i))
+	}
+	days = append(days, start.AddDate(0, 0, 45), start.AddDate(0, 0, 3))
+
+	runs := dayRuns(days, MaxRangeDays)
+	if len(runs) != 3 {
+		t.Fatalf("Expected 3 runs, got %d", len(runs))
+	}
+	if len(runs[0]) != MaxRangeDays || len(runs[1]) != 40-MaxRangeDays || len(runs[2]) != 1 {
+		t.Errorf("Unexpected run lengths %d, %d, %d", len(runs[0]), len(runs[1]), len(runs[2]))
+	}
+	if !runs[2][0].Equal(start.AddDate(0, 0, 45)) {
+		t.Errorf("Expected the last run to hold the 46th day, got %v", runs[2][0])
+	}
+}
@@ -1,15 +1,22 @@
 package web
 
 import (
+	"bytes"
 	"context"
 	"embed"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
+	"io/fs"
 	"log"
+	"mime"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"sort"
 	"strings"
@@ -18,7 +25,12 @@ import (
 	"github.com/arkeo/arkeo/internal/cache"
 	"github.com/arkeo/arkeo/internal/config"
 	"github.com/arkeo/arkeo/internal/connectors"
+	"github.com/arkeo/arkeo/internal/display/formatters"
+	"github.com/arkeo/arkeo/internal/prefetch"
+	"github.com/arkeo/arkeo/internal/rules"
+	"github.com/arkeo/arkeo/internal/secrets"
 	"github.com/arkeo/arkeo/internal/timeline"
+	"github.com/arkeo/arkeo/internal/timesheet"
 	"github.com/arkeo/arkeo/internal/utils"
 )
 
@@ -32,6 +44,7 @@ type Server struct {
 	cache         *cache.Cache
 	templates     map[string]*template.Template
 	httpServer    *http.Server
+	addr          string
 }
 
 // New creates a new web server.
@@ -59,22 +72,10 @@ func New(configManager *config.Manager, registry *connectors.ConnectorRegistry,
 
 // ListenAndServe starts the web server on the given address.
 func (s *Server) ListenAndServe(addr string) error {
-	mux := http.NewServeMux()
-	mux.HandleFunc("/", s.handleTimeline)
-	mux.HandleFunc("/connectors", s.handleConnectors)
-	mux.HandleFunc("/browser", s.handleBrowser)
-	mux.HandleFunc("/api/timeline", s.handleAPITimeline)
-	mux.HandleFunc("/api/cache/reset", s.handleAPICacheReset)
-	mux.HandleFunc("/api/connectors/enable", s.handleAPIConnectorToggle(true))
-	mux.HandleFunc("/api/connectors/disable", s.handleAPIConnectorToggle(false))
-	mux.HandleFunc("/api/connectors/test", s.handleAPIConnectorTest)
-	mux.HandleFunc("/api/connectors/config", s.handleAPIConnectorConfig)
-	mux.HandleFunc("/api/browser/domains", s.handleAPIBrowserDomains)
-	mux.HandleFunc("/api/browser/exclusions", s.handleAPIBrowserExclusions)
-
+	s.addr = addr
 	s.httpServer = &http.Server{
 		Addr:    addr,
-		Handler: mux,
+		Handler: s.routes(),
 	}
 
 	url := fmt.Sprintf("http://%s", addr)
@@ -86,6 +87,80 @@ func (s *Server) ListenAndServe(addr string) error {
 	return s.httpServer.ListenAndServe()
 }
 
+// routes returns the handler of all pages and API endpoints. Endpoints that
+// change the config or the cache, or run connectors, are protected from
+// other websites by protect.
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleTimeline)
+	mux.HandleFunc("/connectors", s.handleConnectors)
+	mux.HandleFunc("/browser", s.handleBrowser)
+	mux.HandleFunc("/api/timeline", s.handleAPITimeline)
+	mux.HandleFunc("/api/cache/reset", s.protect(false, s.handleAPICacheReset))
+	mux.HandleFunc("/api/connectors/enable", s.protect(false, s.handleAPIConnectorToggle(true)))
+	mux.HandleFunc("/api/connectors/disable", s.protect(false, s.handleAPIConnectorToggle(false)))
+	mux.HandleFunc("/api/connectors/test", s.protect(false, s.handleAPIConnectorTest))
+	mux.HandleFunc("/api/connectors/config", s.protect(true, s.handleAPIConnectorConfig))
+	mux.HandleFunc("/api/browser/domains", s.handleAPIBrowserDomains)
+	mux.HandleFunc("/api/browser/exclusions", s.protect(false, s.handleAPIBrowserExclusions))
+	mux.HandleFunc("/api/youtrack/log", s.protect(true, s.handleAPIYouTrackLog))
+	mux.HandleFunc("/api/prefetch/status", s.handleAPIPrefetchStatus)
+	mux.HandleFunc("/api/search", s.handleAPISearch)
+	return mux
+}
+
+// protect only lets requests through to a mutating handler when they come
+// from the web UI itself: a POST with a JSON body, sent to a local address
+// from a page of the same origin. Browsers don't send JSON cross-origin
+// without a preflight this server never answers, and a Host check stops DNS
+// rebinding. allowGet lets GET requests, which only read, through as they are.
+func (s *Server) protect(allowGet bool, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if allowGet && r.Method == http.MethodGet {
+			h(w, r)
+			return
+		}
+		if r.Method != http.MethodPost {
+			writeJSONErrorStatus(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+			writeJSONErrorStatus(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+			return
+		}
+		if !s.isLocalHost(r.Host) {
+			writeJSONErrorStatus(w, http.StatusForbidden, "Unexpected Host "+r.Host)
+			return
+		}
+		if origin := r.Header.Get("Origin"); origin != "" {
+			if u, err := url.Parse(origin); err != nil || u.Host != r.Host {
+				writeJSONErrorStatus(w, http.StatusForbidden, "Cross-origin requests are not allowed")
+				return
+			}
+		} else if r.Header.Get("Sec-Fetch-Site") == "cross-site" {
+			writeJSONErrorStatus(w, http.StatusForbidden, "Cross-origin requests are not allowed")
+			return
+		}
+		h(w, r)
+	}
+}
+
+// isLocalHost reports whether a Host header names this server directly: by
+// IP address, as localhost, or by the host it was told to listen on. Other
+// names may point here through DNS rebinding.
+func (s *Server) isLocalHost(hostport string) bool {
+	host, _, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host = hostport
+	}
+	host = strings.Trim(host, "[]")
+	if host == "localhost" || net.ParseIP(host) != nil {
+		return true
+	}
+	listenHost, _, err := net.SplitHostPort(s.addr)
+	return err == nil && listenHost != "" && strings.EqualFold(host, listenHost)
+}
+
 // openBrowser tries to open the default browser to the given URL.
 func openBrowser(url string) {
 	var cmd *exec.Cmd
@@ -126,18 +201,27 @@ func (s *Server) handleTimeline(w http.ResponseWriter, r *http.Request) {
 	if format == "" {
 		format = "table"
 	}
-	data := pageData{ActivePage: "timeline", Date: dateStr, Format: format}
+	data := pageData{ActivePage: "timeline", Date: dateStr, Format: format, Template: r.URL.Query().Get("template")}
+	if dir, err := s.templatesDir(); err == nil {
+		data.Templates, _ = formatters.NamedTemplates(dir)
+	}
 	s.renderPage(w, "timeline", data)
 }
 
 func (s *Server) handleConnectors(w http.ResponseWriter, r *http.Request) {
 	var connectorList []connectorInfo
 	for name, conn := range s.registry.List() {
-		connectorList = append(connectorList, connectorInfo{
+		_, isPlugin := conn.(*connectors.PluginConnector)
+		info := connectorInfo{
 			Name:        name,
 			Description: conn.Description(),
 			Enabled:     s.configManager.IsConnectorEnabled(name),
-		})
+			Plugin:      isPlugin,
+		}
+		if connectorType := s.configManager.GetConnectorType(name); connectorType != name {
+			info.Type = connectorType
+		}
+		connectorList = append(connectorList, info)
 	}
 	sort.Slice(connectorList, func(i, j int) bool { return connectorList[i].Name < connectorList[j].Name })
 
@@ -193,44 +277,18 @@ func (s *Server) handleAPITimeline(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	utilsConnectors := make(map[string]utils.Connector)
-	connectorNames := make([]string, 0, len(enabledConnectors))
-	for name, conn := range enabledConnectors {
-		utilsConnectors[name] = conn
-		connectorNames = append(connectorNames, name)
-	}
-
-	ctx := context.Background()
-	var dayActivities []timeline.Activity
-	isCached := false
-
-	if s.cache != nil && s.cache.HasDay(day, connectorNames) {
-		cached, err := s.cache.LoadDay(day)
-		if err == nil {
-			dayActivities = cached
-			isCached = true
-		}
-	}
-
-	if !isCached {
-		executor := utils.NewParallelExecutor()
-		results := executor.FetchActivitiesParallel(ctx, utilsConnectors, day)
-		for _, result := range results {
-			if result.Error != nil {
-				continue
-			}
-			dayActivities = append(dayActivities, result.Activities...)
-			if s.cache != nil {
-				s.cache.StoreDay(day, result.Name, result.Activities)
-			}
-		}
-	}
+	dayActivities, isCached := s.loadDay(context.Background(), enabledConnectors, day)
 
 	// Sort
 	sort.Slice(dayActivities, func(i, j int) bool {
 		return dayActivities[i].Timestamp.Before(dayActivities[j].Timestamp)
 	})
 
+	if format == "template" {
+		s.writeTemplateOutput(w, r.URL.Query().Get("template"), day, dayActivities, isCached)
+		return
+	}
+
 	// Build activity views
 	var activitiesView []activityView
 	var prevTime time.Time
@@ -240,6 +298,8 @@ func (s *Server) handleAPITimeline(w http.ResponseWriter, r *http.Request) {
 			SourceLabel: getSourceLabel(a.Source),
 			Title:       a.Title,
 			Description: a.Description,
+			Project:     a.Metadata[rules.ProjectKey],
+			Client:      a.Metadata[rules.ClientKey],
 		}
 		if a.Duration != nil {
 			av.Duration = a.FormatDuration()
@@ -310,6 +370,70 @@ func (s *Server) handleAPITimeline(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// loadDay returns the activities of a day from the cache, fetching those of
+// connectors without an up-to-date cache entry, merged and tagged like the
+// CLI does. It also reports whether they all came from the cache.
+func (s *Server) loadDay(ctx context.Context, enabledConnectors map[string]connectors.Connector, day time.Time) ([]timeline.Activity, bool) {
+	utilsConnectors := make(map[string]utils.Connector)
+	connectorNames := make([]string, 0, len(enabledConnectors))
+	for name, conn := range enabledConnectors {
+		utilsConnectors[name] = conn
+		connectorNames = append(connectorNames, name)
+	}
+	sort.Strings(connectorNames)
+
+	var dayActivities []timeline.Activity
+	isCached := false
+
+	if s.cache != nil {
+		s.cache.SetOutputVersions(connectors.OutputVersions(enabledConnectors))
+	}
+
+	// Fetch only the connectors that are not up to date in the cache
+	stale := make(map[string]utils.Connector)
+	for _, name := range connectorNames {
+		if s.cache != nil && s.cache.HasDay(day, []string{name}) {
+			cached, err := s.cache.LoadConnectorDay(day, name)
+			if err == nil {
+				dayActivities = append(dayActivities, cached...)
+				continue
+			}
+		}
+		stale[name] = utilsConnectors[name]
+	}
+	isCached = len(stale) == 0
+
+	if !isCached {
+		executor := utils.NewParallelExecutor()
+		results := executor.FetchActivitiesParallel(ctx, stale, day)
+		for _, result := range results {
+			if result.Error != nil {
+				continue
+			}
+			dayActivities = append(dayActivities, result.Activities...)
+			if s.cache != nil {
+				s.cache.StoreDay(day, result.Name, result.Activities)
+			}
+		}
+	}
+
+	// Merge the same work reported by several sources
+	if dedupConfig := s.configManager.GetConfig().Dedup; dedupConfig.Enabled {
+		dayActivities, _ = timeline.Deduplicate(dayActivities, dedupConfig.Options())
+	}
+
+	// Tag activities with projects and clients from the rules section
+	if ruleConfigs := s.configManager.GetConfig().Rules; len(ruleConfigs) > 0 {
+		if engine, err := rules.New(ruleConfigs); err != nil {
+			log.Printf("Rules not applied: %v", err)
+		} else {
+			engine.Apply(dayActivities)
+		}
+	}
+
+	return dayActivities, isCached
+}
+
 func (s *Server) handleAPICacheReset(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	if s.cache == nil {
@@ -400,14 +524,19 @@ func (s *Server) handleAPIConnectorTest(w http.ResponseWriter, r *http.Request)
 	}
 	configWithLogLevel["log_level"] = s.configManager.GetConfig().App.LogLevel
 
-	if err := conn.Configure(configWithLogLevel); err != nil {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	resolved, err := secrets.ResolveConfig(ctx, configWithLogLevel, conn.GetRequiredConfig())
+	if err != nil {
+		writeJSONError(w, "Secret error: "+err.Error())
+		return
+	}
+	if err := conn.Configure(resolved); err != nil {
 		writeJSONError(w, "Config error: "+err.Error())
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
 	if err := conn.TestConnection(ctx); err != nil {
 		writeJSONError(w, err.Error())
 		return
@@ -444,11 +573,20 @@ func (s *Server) handleAPIConnectorConfig(w http.ResponseWriter, r *http.Request
 			}
 		}
 
-		// Determine which fields are secrets
+		// Determine which fields are secrets. Secret values never leave the
+		// server: only references (env:, cmd:, keyring:) are returned, along
+		// with the backend each secret comes from.
 		secretFields := []string{}
+		secretBackends := make(map[string]string)
 		for _, field := range conn.GetRequiredConfig() {
 			if field.Type == "secret" {
 				secretFields = append(secretFields, field.Key)
+				if backend := secrets.Backend(fields[field.Key]); backend != "" {
+					secretBackends[field.Key] = backend
+				}
+				if !secrets.IsReference(fields[field.Key]) {
+					fields[field.Key] = ""
+				}
 			}
 			// Ensure all required config fields appear even if empty
 			if _, exists := fields[field.Key]; !exists {
@@ -456,9 +594,13 @@ func (s *Server) handleAPIConnectorConfig(w http.ResponseWriter, r *http.Request
 			}
 		}
 
+		// The program a plugin runs can only be changed in the config file
+		delete(fields, connectors.PluginCommandKey)
+
 		json.NewEncoder(w).Encode(map[string]interface{}{
-			"fields":        fields,
-			"secret_fields": secretFields,
+			"fields":          fields,
+			"secret_fields":   secretFields,
+			"secret_backends": secretBackends,
 		})
 		return
 	}
@@ -472,8 +614,27 @@ func (s *Server) handleAPIConnectorConfig(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	// Merge with existing config (don't overwrite fields not sent)
+	// References that run programs when resolved, and the program a plugin
+	// runs, are only taken from the config file; the page may send back the
+	// ones already there unchanged
 	connConfig, hasConfig := s.configManager.GetConnectorConfig(name)
+	if v, ok := body.Config[connectors.PluginCommandKey]; ok {
+		if current, _ := connConfig.Config[connectors.PluginCommandKey].(string); strings.TrimSpace(current) != strings.TrimSpace(v) {
+			writeJSONError(w, fmt.Sprintf("%s can only be set by editing %s", connectors.PluginCommandKey, s.configManager.GetConfigPath()))
+			return
+		}
+	}
+	for k, v := range body.Config {
+		if !secrets.RunsProgram(v) {
+			continue
+		}
+		if current, _ := connConfig.Config[k].(string); !hasConfig || strings.TrimSpace(current) != strings.TrimSpace(v) {
+			writeJSONError(w, fmt.Sprintf("%s: cmd: and keyring: references can only be set by editing %s", k, s.configManager.GetConfigPath()))
+			return
+		}
+	}
+
+	// Merge with existing config (don't overwrite fields not sent)
 	merged := make(map[string]interface{})
 	if hasConfig && connConfig.Config != nil {
 		for k, v := range connConfig.Config {
@@ -485,6 +646,7 @@ func (s *Server) handleAPIConnectorConfig(w http.ResponseWriter, r *http.Request
 	}
 
 	s.configManager.SetConnectorConfig(name, config.ConnectorConfig{
+		Type:    connConfig.Type,
 		Enabled: s.configManager.IsConnectorEnabled(name),
 		Config:  merged,
 	})
@@ -570,20 +732,277 @@ func (s *Server) handleAPIBrowserExclusions(w http.ResponseWriter, r *http.Reque
 	json.NewEncoder(w).Encode(map[string]string{"message": fmt.Sprintf("Saved %d excluded domains", len(body.Domains))})
 }
 
+// handleAPIYouTrackLog previews (GET) the YouTrack work items inferred from
+// the work blocks of a day, or creates (POST) the items selected in the
+// preview. Issues with time already logged that day are skipped either way.
+// The connector parameter selects another YouTrack instance than youtrack.
+func (s *Server) handleAPIYouTrackLog(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	connectorName := r.URL.Query().Get("connector")
+	if connectorName == "" {
+		connectorName = "youtrack"
+	}
+
+	enabledConnectors := getEnabledConnectors(s.configManager, s.registry)
+	youtrack, ok := enabledConnectors[connectorName].(*connectors.YouTrackConnector)
+	if !ok {
+		writeJSONError(w, fmt.Sprintf("The %s connector is not an enabled YouTrack connector", connectorName))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	var items []connectors.YouTrackWorkItem
+	dryRun := r.Method != "POST"
+
+	if dryRun {
+		dateStr := r.URL.Query().Get("date")
+		parsedDate, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			writeJSONError(w, "Invalid date")
+			return
+		}
+		day := parsedDate.Truncate(24 * time.Hour)
+		dayActivities, _ := s.loadDay(ctx, enabledConnectors, day)
+
+		tsConfig := s.configManager.GetConfig().Timesheet
+		opts := timesheet.Options{
+			Increment:     time.Duration(tsConfig.RoundMinutes) * time.Minute,
+			MaxGap:        time.Duration(tsConfig.GapMinutes) * time.Minute,
+			PointDuration: time.Duration(tsConfig.ActivityMinutes) * time.Minute,
+		}
+		projects, err := youtrack.ProjectShortNames(ctx)
+		if err != nil {
+			log.Printf("Could not list YouTrack projects, only using issues of YouTrack activities: %v", err)
+		}
+		opts.IssueProjects = projects
+		ts := timesheet.Build(dayActivities, []time.Time{day}, opts)
+		for _, e := range timesheet.IssueEntriesFromBlocks(ts, dayActivities, opts) {
+			items = append(items, connectors.YouTrackWorkItem{
+				IssueKey: e.IssueKey, Date: e.Date, Duration: e.Duration, Text: e.Text,
+			})
+		}
+	} else {
+		var body struct {
+			Items []workItemView `json:"items"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeJSONError(w, "Invalid request body")
+			return
+		}
+		for _, item := range body.Items {
+			date, err := time.ParseInLocation("2006-01-02", item.Date, time.Local)
+			if err != nil || item.Issue == "" || item.Minutes <= 0 {
+				writeJSONError(w, "Invalid work item")
+				return
+			}
+			items = append(items, connectors.YouTrackWorkItem{
+				IssueKey: item.Issue, Date: date, Duration: time.Duration(item.Minutes) * time.Minute, Text: item.Text,
+			})
+		}
+	}
+
+	results, err := youtrack.LogWorkItems(ctx, items, dryRun)
+	if err != nil {
+		writeJSONError(w, err.Error())
+		return
+	}
+
+	views := make([]workItemView, 0, len(results))
+	for _, res := range results {
+		view := workItemView{
+			Issue:    res.IssueKey,
+			Date:     res.Date.Format("2006-01-02"),
+			Minutes:  int(res.Duration / time.Minute),
+			Duration: formatDuration(res.Duration),
+			Text:     res.Text,
+			Status:   res.Status,
+			Error:    res.Error,
+		}
+		if res.Existing > 0 {
+			view.Existing = formatDuration(res.Existing)
+		}
+		views = append(views, view)
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{"items": views, "dry_run": dryRun})
+}
+
+// templatesDir returns the directory of the named timeline templates
+func (s *Server) templatesDir() (string, error) {
+	configDir, err := s.configManager.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, formatters.TemplatesDir), nil
+}
+
+// writeTemplateOutput renders a day with a named template. Only names are
+// accepted, so that the page cannot read other files.
+func (s *Server) writeTemplateOutput(w http.ResponseWriter, name string, day time.Time, activities []timeline.Activity, isCached bool) {
+	dir, err := s.templatesDir()
+	if err != nil {
+		writeJSONError(w, err.Error())
+		return
+	}
+	text, err := formatters.ReadNamedTemplate(dir, name)
+	if err != nil {
+		writeJSONError(w, err.Error())
+		return
+	}
+
+	data := formatters.BuildTemplateData(map[time.Time][]timeline.Activity{day: activities}, []time.Time{day})
+	var output bytes.Buffer
+	if err := formatters.RenderTemplate(&output, data, text); err != nil {
+		writeJSONError(w, err.Error())
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"date":         day.Format("2006-01-02"),
+		"date_display": day.Format("Monday, January 2, 2006"),
+		"template":     name,
+		"output":       output.String(),
+		"cached":       isCached,
+	})
+}
+
+// handleAPIPrefetchStatus returns the status of the last run of the
+// prefetcher, which may run in this process (--prefetch) or in arkeo daemon
+func (s *Server) handleAPIPrefetchStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	configDir, err := s.configManager.GetConfigDir()
+	if err != nil {
+		writeJSONError(w, err.Error())
+		return
+	}
+
+	status, err := prefetch.LoadStatus(filepath.Join(configDir, prefetch.StatusFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"synced": false})
+		return
+	}
+	if err != nil {
+		writeJSONError(w, err.Error())
+		return
+	}
+
+	view := map[string]interface{}{
+		"synced":     !status.FinishedAt.IsZero(),
+		"running":    status.Running,
+		"days":       status.Days,
+		"activities": status.Activities,
+		"failures":   status.Failures,
+	}
+	if !status.FinishedAt.IsZero() {
+		view["last_synced"] = status.FinishedAt.Format(time.RFC3339)
+	}
+	if !status.NextRun.IsZero() {
+		view["next_run"] = status.NextRun.Format(time.RFC3339)
+	}
+	json.NewEncoder(w).Encode(view)
+}
+
+// handleAPISearch searches the cached activities. Parameters: q, since and
+// until (YYYY-MM-DD), source (repeatable or comma-separated) and limit.
+func (s *Server) handleAPISearch(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if s.cache == nil {
+		writeJSONError(w, "Cache not available")
+		return
+	}
+
+	query := r.URL.Query()
+	opts := cache.SearchOptions{Limit: 50}
+	for _, param := range []struct {
+		name   string
+		target *time.Time
+	}{{"since", &opts.Since}, {"until", &opts.Until}} {
+		if value := query.Get(param.name); value != "" {
+			date, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				writeJSONError(w, "Invalid "+param.name+" date")
+				return
+			}
+			*param.target = date
+		}
+	}
+	for _, value := range query["source"] {
+		for _, source := range strings.Split(value, ",") {
+			if source = strings.TrimSpace(source); source != "" {
+				opts.Sources = append(opts.Sources, source)
+			}
+		}
+	}
+	if limitStr := query.Get("limit"); limitStr != "" {
+		fmt.Sscanf(limitStr, "%d", &opts.Limit)
+	}
+
+	results, err := s.cache.Search(query.Get("q"), opts)
+	if err != nil {
+		writeJSONError(w, err.Error())
+		return
+	}
+
+	type searchResult struct {
+		Date        string `json:"date"`
+		Time        string `json:"time"`
+		Source      string `json:"source"`
+		SourceLabel string `json:"source_label"`
+		Type        string `json:"type"`
+		Title       string `json:"title"`
+		Description string `json:"description,omitempty"`
+		URL         string `json:"url,omitempty"`
+	}
+
+	views := make([]searchResult, 0, len(results))
+	for _, a := range results {
+		views = append(views, searchResult{
+			Date:        a.Timestamp.Format("2006-01-02"),
+			Time:        a.Timestamp.Format("15:04"),
+			Source:      a.Source,
+			SourceLabel: getSourceLabel(a.Source),
+			Type:        string(a.Type),
+			Title:       a.Title,
+			Description: a.Description,
+			URL:         a.URL,
+		})
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{"results": views, "count": len(views)})
+}
+
 // --- Helpers ---
 
 func writeJSONError(w http.ResponseWriter, msg string) {
 	json.NewEncoder(w).Encode(map[string]string{"error": msg})
 }
 
+// writeJSONErrorStatus writes an error with an HTTP status other than 200
+func writeJSONErrorStatus(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	writeJSONError(w, msg)
+}
+
 func getSourceLabel(source string) string {
+	if connectorType, instance, ok := strings.Cut(source, ":"); ok {
+		return getSourceLabel(connectorType) + ":" + instance
+	}
 	labels := map[string]string{
 		"github":          "GH",
 		"gitlab":          "GL",
 		"calendar":        "CAL",
 		"youtrack":        "YT",
+		"jira":            "JRA",
 		"macos_system":    "MAC",
+		"linux_session":   "LNX",
 		"browser_history": "WEB",
+		"local_git":       "GIT",
+		"shell_history":   "SH",
 		"webhooks":        "HOOK",
 	}
 	if label, ok := labels[source]; ok {
@@ -616,7 +1035,12 @@ func getEnabledConnectors(configManager *config.Manager, registry *connectors.Co
 				configWithAppSettings[connectors.CommonConfigKeys.DebugMode] = true
 			}
 
-			if err := connector.Configure(configWithAppSettings); err != nil {
+			resolved, err := secrets.ResolveConfig(context.Background(), configWithAppSettings, connector.GetRequiredConfig())
+			if err != nil {
+				log.Printf("Warning: %s: %v", name, err)
+				continue
+			}
+			if err := connector.Configure(resolved); err != nil {
 				continue
 			}
 			connector.SetEnabled(true)
@@ -654,6 +1078,8 @@ type pageData struct {
 	ActivePage string
 	Date       string
 	Format     string
+	Template   string   // Selected named template of the template format
+	Templates  []string // Named templates to choose from
 	Days       string
 	Connectors []connectorInfo
 }
@@ -662,6 +1088,19 @@ type connectorInfo struct {
 	Name        string
 	Description string
 	Enabled     bool
+	Plugin      bool
+	Type        string // Type of an additional named instance
+}
+
+type workItemView struct {
+	Issue    string `json:"issue"`
+	Date     string `json:"date"`
+	Minutes  int    `json:"minutes"`
+	Duration string `json:"duration,omitempty"`
+	Text     string `json:"text"`
+	Status   string `json:"status,omitempty"`
+	Existing string `json:"existing,omitempty"`
+	Error    string `json:"error,omitempty"`
 }
 
 type activityView struct {
@@ -671,6 +1110,8 @@ type activityView struct {
 	Description string `json:"description"`
 	Duration    string `json:"duration"`
 	Gap         string `json:"gap"`
+	Project     string `json:"project,omitempty"`
+	Client      string `json:"client,omitempty"`
 }
 
 // FormatDuration is a helper to format durations for display.
